@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clustersv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
@@ -23,6 +24,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
@@ -31,8 +33,10 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -75,12 +79,30 @@ func doMain(ctx context.Context, agentConfig *configs.AgentConfig, restConfig *r
 		go utils.StartDefaultPprofServer()
 	}
 
+	// the spans of the sent events are exported, the trace is continued by the manager
+	shutdownTracing, err := tracing.Init(ctx, "multicluster-global-hub-agent", agentConfig.TracingConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init the tracing: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.DefaultZapLogger().Warnw("failed to flush the spans", "error", err)
+		}
+	}()
+
 	// init manager
 	mgr, err := createManager(restConfig, agentConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create manager: %w", err)
 	}
 
+	// the metrics of the transport outbox, which persists the undeliverable events
+	if outbox := agentConfig.TransportConfig.Outbox; outbox != nil && outbox.Path != "" {
+		metrics.Registry.MustRegister(producer.OutboxMetrics...)
+	}
+
 	// add configmap controller
 	if err := configmap.AddConfigMapController(mgr, agentConfig); err != nil {
 		return fmt.Errorf("failed to add ConfigMap controller: %w", err)
@@ -115,12 +137,15 @@ func doMain(ctx context.Context, agentConfig *configs.AgentConfig, restConfig *r
 }
 
 func parseFlags() *configs.AgentConfig {
+	var outboxMaxSizeMB int64
 	agentConfig := &configs.AgentConfig{
 		ElectionConfig: &commonobjects.LeaderElectionConfig{},
 		TransportConfig: &transport.TransportInternalConfig{
 			// EnableDatabaseOffset affects only the manager, deciding if consumption starts from a database-stored offset
 			EnableDatabaseOffset: false,
+			Outbox:               &transport.OutboxConfig{},
 		},
+		TracingConfig: &tracing.Config{},
 	}
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -130,14 +155,22 @@ func parseFlags() *configs.AgentConfig {
 	pflag.StringVar(&agentConfig.PodNamespace, "pod-namespace", constants.GHAgentNamespace,
 		"The agent running namespace, also used as leader election namespace")
 	pflag.IntVar(&agentConfig.SpecWorkPoolSize, "consumer-worker-pool-size", 10,
-		"The goroutine number to propagate the bundles on managed cluster.")
+		"The number of the workers to sync the spec events, the events of the same type and object are synced in order.")
 	pflag.IntVar(&agentConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar(&agentConfig.TransportConfig.Outbox.Path, "transport-outbox-path", "",
+		"The file to persist the undeliverable events, they're replayed in order once the transport is reachable. "+
+			"Empty disables the outbox.")
+	pflag.Int64Var(&outboxMaxSizeMB, "transport-outbox-max-size", 100,
+		"The maximum size(MB) of the transport outbox, the oldest events are dropped once it's exceeded.")
+	pflag.DurationVar(&agentConfig.TransportConfig.Outbox.MaxAge, "transport-outbox-max-age", 24*time.Hour,
+		"The maximum age of the events in the transport outbox, the older events are dropped instead of replayed.")
 	pflag.BoolVar(&agentConfig.SpecEnforceHohRbac, "enforce-hoh-rbac", false,
 		"enable hoh RBAC or not, default false")
 	pflag.IntVar(&agentConfig.StatusDeltaCountSwitchFactor,
 		"status-delta-count-switch-factor", 100,
-		"default with 100.")
+		"The status deltas are sent until their count exceeds the factor times the object count, then the complete "+
+			"snapshot is sent. 0 disables the switch.")
 	pflag.IntVar(&agentConfig.ElectionConfig.LeaseDuration, "lease-duration", 137,
 		"leader election lease duration")
 	pflag.IntVar(&agentConfig.ElectionConfig.RenewDeadline, "renew-deadline", 107,
@@ -157,7 +190,14 @@ func parseFlags() *configs.AgentConfig {
 		"The interval between each StackRox polling")
 	pflag.StringVar(&agentConfig.EventMode, "event-send-mode", string(constants.EventSendModeBatch),
 		"Event sending mode: batch or single")
+	pflag.StringVar(&agentConfig.TracingConfig.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC endpoint to export the spans of the events. Empty disables the exporting.")
+	pflag.BoolVar(&agentConfig.TracingConfig.Insecure, "tracing-insecure", false,
+		"Connect to the tracing endpoint without TLS.")
+	pflag.IntVar(&agentConfig.TracingConfig.SamplingPercentage, "tracing-sampling-percentage", 100,
+		"The percentage of the traces sampled to export.")
 	pflag.Parse()
+	agentConfig.TransportConfig.Outbox.MaxSize = outboxMaxSizeMB * 1024 * 1024
 
 	return agentConfig
 }
@@ -250,6 +290,10 @@ func initCache(restConfig *rest.Config, cacheOpts cache.Options) (cache.Cache, e
 			Field: fields.OneTermEqualSelector("metadata.namespace", configs.GetAgentConfig().PodNamespace),
 		},
 		&corev1.Event{}: {},
+		// there might be thousands of addons, only the status of them is synced
+		&addonv1alpha1.ManagedClusterAddOn{}: {
+			Transform: cache.TransformStripManagedFields(),
+		},
 	}
 	if configs.GetAgentConfig().DeployMode == string(constants.DefaultMode) {
 		cacheOpts.ByObject[&clustersv1alpha1.ClusterClaim{}] = cache.ByObject{}
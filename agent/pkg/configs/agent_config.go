@@ -5,6 +5,7 @@ import (
 	"time"
 
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -17,6 +18,7 @@ type AgentConfig struct {
 	SpecEnforceHohRbac           bool
 	StatusDeltaCountSwitchFactor int
 	TransportConfig              *transport.TransportInternalConfig
+	TracingConfig                *tracing.Config
 	TransportConfigSecretName    string
 	ElectionConfig               *commonobjects.LeaderElectionConfig
 	MetricsAddress               string
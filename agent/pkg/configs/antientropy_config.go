@@ -0,0 +1,41 @@
+package configs
+
+import (
+	"sync"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+)
+
+type RepairQueue struct {
+	mu      sync.Mutex
+	repairs []*antientropy.RepairBundle
+}
+
+// GlobalRepairQueue holds the anti-entropy repair requests from the manager, the periodic syncer resends the
+// mismatched buckets of them
+var GlobalRepairQueue = &RepairQueue{}
+
+// Add appends a repair request to the end of the queue, the pending request of the same event type is replaced
+func (q *RepairQueue) Add(repair *antientropy.RepairBundle) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, pending := range q.repairs {
+		if pending.EventType == repair.EventType {
+			q.repairs[i] = repair
+			return
+		}
+	}
+	q.repairs = append(q.repairs, repair)
+}
+
+// Pop removes and returns the first repair request in the queue. Returns nil if empty.
+func (q *RepairQueue) Pop() *antientropy.RepairBundle {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.repairs) == 0 {
+		return nil
+	}
+	repair := q.repairs[0]
+	q.repairs = q.repairs[1:]
+	return repair
+}
@@ -0,0 +1,30 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+)
+
+func TestRepairQueue(t *testing.T) {
+	queue := &RepairQueue{}
+	if queue.Pop() != nil {
+		t.Error("Empty queue should return nil")
+	}
+
+	queue.Add(&antientropy.RepairBundle{EventType: "type1", Buckets: []int{1}})
+	queue.Add(&antientropy.RepairBundle{EventType: "type2", Buckets: []int{2}})
+	queue.Add(&antientropy.RepairBundle{EventType: "type1", Buckets: []int{3}})
+
+	repair := queue.Pop()
+	if repair == nil || repair.EventType != "type1" || repair.Buckets[0] != 3 {
+		t.Errorf("The pending request should be replaced by the latest one: %+v", repair)
+	}
+	repair = queue.Pop()
+	if repair == nil || repair.EventType != "type2" {
+		t.Errorf("Should return the second request: %+v", repair)
+	}
+	if queue.Pop() != nil {
+		t.Error("Empty queue should return nil")
+	}
+}
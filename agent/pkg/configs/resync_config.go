@@ -3,13 +3,31 @@ package configs
 import "sync"
 
 type ResyncTypeQueue struct {
-	mu    sync.Mutex
-	queue []string
+	mu       sync.Mutex
+	queue    []string
+	requests map[string][]*ResyncRequest
 }
 
 // GlobalResyncQueue is a ready-to-use, globally accessible queue.
 var GlobalResyncQueue = &ResyncTypeQueue{}
 
+// ResyncRequest tracks the event types of a resync request from the manager, the Done channel is closed once all the
+// event types are resynced
+type ResyncRequest struct {
+	ID      string
+	mu      sync.Mutex
+	results map[string]*ResyncResult
+	pending int
+	done    chan struct{}
+}
+
+// ResyncResult is the result of resyncing an event type, the Registered is false if no emitter is registered for it
+type ResyncResult struct {
+	Objects    int
+	Registered bool
+	Err        error
+}
+
 // Add appends a type to the end of the queue
 func (q *ResyncTypeQueue) Add(t string) {
 	q.mu.Lock()
@@ -28,3 +46,73 @@ func (q *ResyncTypeQueue) Pop() string {
 	q.queue = q.queue[1:]
 	return t
 }
+
+// AddRequest appends the event types of the request to the queue, and tracks the request until the event types are
+// finished
+func (q *ResyncTypeQueue) AddRequest(id string, eventTypes []string) *ResyncRequest {
+	req := &ResyncRequest{
+		ID:      id,
+		results: map[string]*ResyncResult{},
+		done:    make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.requests == nil {
+		q.requests = map[string][]*ResyncRequest{}
+	}
+	for _, eventType := range eventTypes {
+		if _, ok := req.results[eventType]; ok {
+			continue
+		}
+		req.results[eventType] = nil
+		req.pending++
+		q.queue = append(q.queue, eventType)
+		q.requests[eventType] = append(q.requests[eventType], req)
+	}
+	if req.pending == 0 {
+		close(req.done)
+	}
+	return req
+}
+
+// Finish records the result of the resynced event type for the requests waiting on it
+func (q *ResyncTypeQueue) Finish(eventType string, objects int, registered bool, err error) {
+	q.mu.Lock()
+	requests := q.requests[eventType]
+	delete(q.requests, eventType)
+	q.mu.Unlock()
+
+	for _, req := range requests {
+		req.finish(eventType, &ResyncResult{Objects: objects, Registered: registered, Err: err})
+	}
+}
+
+func (r *ResyncRequest) finish(eventType string, result *ResyncResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.results[eventType]; !ok || existing != nil {
+		return
+	}
+	r.results[eventType] = result
+	r.pending--
+	if r.pending == 0 {
+		close(r.done)
+	}
+}
+
+// Done returns a channel that is closed once all the event types of the request are resynced
+func (r *ResyncRequest) Done() <-chan struct{} {
+	return r.done
+}
+
+// Results returns the results of the event types, the result is nil if the event type isn't resynced yet
+func (r *ResyncRequest) Results() map[string]*ResyncResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make(map[string]*ResyncResult, len(r.results))
+	for eventType, result := range r.results {
+		results[eventType] = result
+	}
+	return results
+}
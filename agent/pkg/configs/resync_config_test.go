@@ -37,3 +37,44 @@ func TestGlobalResyncQueue(t *testing.T) {
 		t.Error("Global queue should work")
 	}
 }
+
+func TestResyncTypeQueue_Request(t *testing.T) {
+	queue := &ResyncTypeQueue{}
+
+	req := queue.AddRequest("req1", []string{"type1", "type2", "type1"})
+	if queue.Pop() != "type1" || queue.Pop() != "type2" || queue.Pop() != "" {
+		t.Fatal("Request should enqueue each event type once")
+	}
+
+	queue.Finish("type1", 3, true, nil)
+	select {
+	case <-req.Done():
+		t.Fatal("Request should not be done before all the event types are finished")
+	default:
+	}
+
+	queue.Finish("type2", 0, false, nil)
+	select {
+	case <-req.Done():
+	default:
+		t.Fatal("Request should be done after all the event types are finished")
+	}
+
+	results := req.Results()
+	if results["type1"].Objects != 3 || !results["type1"].Registered {
+		t.Errorf("Unexpected result for type1: %+v", results["type1"])
+	}
+	if results["type2"].Registered {
+		t.Errorf("Unexpected result for type2: %+v", results["type2"])
+	}
+
+	// finishing an event type without requests should be a no-op
+	queue.Finish("type3", 1, true, nil)
+
+	empty := queue.AddRequest("req2", nil)
+	select {
+	case <-empty.Done():
+	default:
+		t.Fatal("Request without event types should be done")
+	}
+}
@@ -5,6 +5,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	agentspec "github.com/stolostron/multicluster-global-hub/agent/pkg/spec"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/applications"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/provisioning"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/security"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
@@ -47,6 +50,10 @@ func (c *initController) Reconcile(ctx context.Context, request ctrl.Request) (c
 		return c.addACMController(ctx, request)
 	case request.Name == stackRoxCentralCRDName && c.agentConfig.EnableStackroxIntegration:
 		return c.addStackRoxCentrals()
+	case applications.IsApplicationCRD(request.Name):
+		return c.addApplicationSyncer(ctx, request.Name)
+	case provisioning.IsProvisioningCRD(request.Name):
+		return c.addProvisioningSyncer(ctx, request.Name)
 	default:
 		return ctrl.Result{}, nil
 	}
@@ -124,6 +131,35 @@ func (c *initController) addStackRoxCentrals() (result ctrl.Result, err error) {
 	return result, err
 }
 
+func (c *initController) addApplicationSyncer(ctx context.Context, crdName string) (ctrl.Result, error) {
+	log.Infof("Detected the presence of the Argo CD CRD %s", crdName)
+	// the application syncer depends on the status controllers, which are added once the ACM is detected
+	err := status.AddApplicationSyncer(ctx, c.mgr, c.transportClient, crdName)
+	if errors.Is(err, status.ErrStatusNotStarted) {
+		log.Infof("waiting for the status controllers to add the syncer of %s", crdName)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to add the syncer of %s: %w", crdName, err)
+	}
+	log.Infof("Added the syncer of %s", crdName)
+	return ctrl.Result{}, nil
+}
+
+func (c *initController) addProvisioningSyncer(ctx context.Context, crdName string) (ctrl.Result, error) {
+	log.Infof("Detected the presence of the provisioning CRD %s", crdName)
+	err := status.AddProvisioningSyncer(ctx, c.mgr, c.transportClient, crdName)
+	if errors.Is(err, status.ErrStatusNotStarted) {
+		log.Infof("waiting for the status controllers to add the syncer of %s", crdName)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to add the syncer of %s: %w", crdName, err)
+	}
+	log.Infof("Added the syncer of %s", crdName)
+	return ctrl.Result{}, nil
+}
+
 // this controller is used to watch the multiclusterhub crd or clustermanager crd
 // if the crd exists, then add controllers to the manager dynamically
 func AddInitController(mgr ctrl.Manager, restConfig *rest.Config, agentConfig *configs.AgentConfig,
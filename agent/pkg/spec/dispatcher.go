@@ -2,17 +2,25 @@ package spec
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -22,13 +30,19 @@ var addToMgr = false
 type genericDispatcher struct {
 	log         *zap.SugaredLogger
 	consumer    transport.Consumer
+	producer    transport.Producer
 	agentConfig *configs.AgentConfig
 	syncers     map[string]Syncer
 	mu          sync.RWMutex
-	wg          sync.WaitGroup // Track in-flight syncer goroutines for graceful shutdown
+	workPool    *keyedWorkPool
+
+	// the version of the acknowledgements, it's shared by the syncer goroutines
+	ackVersion   *version.Version
+	ackVersionMu sync.Mutex
 }
 
-func AddGenericDispatcher(mgr ctrl.Manager, consumer transport.Consumer, config *configs.AgentConfig,
+func AddGenericDispatcher(mgr ctrl.Manager, consumer transport.Consumer, producer transport.Producer,
+	config *configs.AgentConfig,
 ) (Dispatcher, error) {
 	if addToMgr {
 		return nil, nil
@@ -36,12 +50,16 @@ func AddGenericDispatcher(mgr ctrl.Manager, consumer transport.Consumer, config
 	dispatcher := &genericDispatcher{
 		log:         logger.DefaultZapLogger(),
 		consumer:    consumer,
+		producer:    producer,
 		agentConfig: config,
 		syncers:     make(map[string]Syncer),
+		workPool:    newKeyedWorkPool(config.SpecWorkPoolSize),
+		ackVersion:  version.NewVersion(),
 	}
 	if err := mgr.Add(dispatcher); err != nil {
 		return nil, err
 	}
+	metrics.Registry.MustRegister(specPendingEvents, specRunningEvents, specWaitDuration)
 
 	addToMgr = true
 	return dispatcher, nil
@@ -85,7 +103,7 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			d.wg.Wait() // Wait for in-flight syncers before returning
+			d.workPool.Wait() // Wait for in-flight syncers before returning
 			return
 		case evt := <-d.consumer.EventChan():
 			d.log.Debugf("get event: %v", evt.Type())
@@ -116,16 +134,84 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 					"syncer", syncer, "event", evt)
 				continue
 			}
-			// Async call - don't block dispatcher for long-running syncers (e.g., migration)
-			d.wg.Add(1)
-			go func(ctx context.Context, evt *cloudevents.Event, syncer Syncer) {
-				defer d.wg.Done()
-				if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-					return syncer.Sync(ctx, evt)
-				}); err != nil {
-					d.log.Errorw("sync failed", "type", evt.Type(), "error", err)
-				}
-			}(ctx, evt, syncer)
+			// the events of the same key are synced in order, and the consumer is blocked if the pool is full
+			if err := d.workPool.Submit(ctx, workKeyOf(evt), evt.Type(), func() {
+				d.sync(ctx, evt, syncer)
+			}); err != nil {
+				d.log.Infow("event dropped since the dispatcher is stopped", "type", evt.Type())
+			}
+		}
+	}
+}
+
+func (d *genericDispatcher) sync(ctx context.Context, evt *cloudevents.Event, syncer Syncer) {
+	start := time.Now()
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		return syncer.Sync(ctx, evt)
+	})
+	if err != nil {
+		d.log.Errorw("sync failed", "type", evt.Type(), "error", err)
+	}
+	d.acknowledge(ctx, evt, err, time.Since(start))
+}
+
+// acknowledge sends the result of the syncer to the manager if the spec event has the correlation id, the manager
+// resends the spec event if it isn't acknowledged in time. The events denied by the RBAC are always reported, so the
+// denied operations are recorded by the manager even if they aren't sent by it.
+func (d *genericDispatcher) acknowledge(ctx context.Context, evt *cloudevents.Event, syncErr error,
+	duration time.Duration,
+) {
+	denied := rbac.IsDenied(syncErr)
+	correlationID, err := cetypes.ToString(evt.Extensions()[constants.CloudEventExtensionKeyCorrelationId])
+	if (err != nil || correlationID == "") && denied {
+		correlationID = evt.ID()
+		if correlationID == "" {
+			correlationID = uuid.New().String()
 		}
 	}
+	if correlationID == "" || d.producer == nil {
+		return
+	}
+	ack := &specack.SpecAckBundle{
+		CorrelationID:        correlationID,
+		EventType:            evt.Type(),
+		Success:              syncErr == nil,
+		DurationMilliseconds: duration.Milliseconds(),
+		Denied:               denied,
+	}
+	if syncErr != nil {
+		ack.Error = syncErr.Error()
+	}
+	if denied {
+		ack.Source = evt.Source()
+	}
+	if err := d.sendAck(ctx, ack); err != nil {
+		d.log.Warnw("failed to acknowledge the spec event", "type", evt.Type(), "correlationId", correlationID,
+			"error", err)
+	}
+}
+
+func (d *genericDispatcher) sendAck(ctx context.Context, ack *specack.SpecAckBundle) error {
+	payloadBytes, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the spec ack bundle - %w", err)
+	}
+
+	d.ackVersionMu.Lock()
+	defer d.ackVersionMu.Unlock()
+	d.ackVersion.Incr()
+	e := cloudevents.NewEvent()
+	e.SetType(string(enum.SpecAckType))
+	e.SetSource(d.agentConfig.LeafHubName)
+	e.SetSubject(constants.CloudEventGlobalHubClusterName)
+	e.SetExtension(constants.CloudEventExtensionKeyCorrelationId, ack.CorrelationID)
+	e.SetExtension(version.ExtVersion, d.ackVersion.String())
+	if err := e.SetData(cloudevents.ApplicationJSON, payloadBytes); err != nil {
+		return fmt.Errorf("failed to set the spec ack payload - %w", err)
+	}
+	if err := d.producer.SendEvent(ctx, e); err != nil {
+		return err
+	}
+	d.ackVersion.Next()
+	return nil
 }
@@ -1,13 +1,22 @@
 package spec
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 func TestIsEventExpired(t *testing.T) {
@@ -53,3 +62,66 @@ func TestIsEventExpired(t *testing.T) {
 		})
 	}
 }
+
+type fakeProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *fakeProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func TestAcknowledge(t *testing.T) {
+	producer := &fakeProducer{}
+	d := &genericDispatcher{
+		log:         logger.DefaultZapLogger(),
+		producer:    producer,
+		agentConfig: &configs.AgentConfig{LeafHubName: "hub1"},
+		ackVersion:  version.NewVersion(),
+	}
+
+	// the spec event without the correlation id isn't acknowledged
+	evt := cloudevents.NewEvent()
+	evt.SetType(constants.HAConfigMsgKey)
+	d.acknowledge(context.Background(), &evt, nil, time.Second)
+	assert.Empty(t, producer.events)
+
+	evt.SetExtension(constants.CloudEventExtensionKeyCorrelationId, "id1")
+	d.acknowledge(context.Background(), &evt, nil, time.Second)
+	evt.SetExtension(constants.CloudEventExtensionKeyCorrelationId, "id2")
+	d.acknowledge(context.Background(), &evt, errors.New("forbidden"), 2*time.Second)
+	assert.Len(t, producer.events, 2)
+
+	ack := &specack.SpecAckBundle{}
+	assert.NoError(t, producer.events[0].DataAs(ack))
+	assert.Equal(t, string(enum.SpecAckType), producer.events[0].Type())
+	assert.Equal(t, "hub1", producer.events[0].Source())
+	assert.Equal(t, specack.SpecAckBundle{
+		CorrelationID: "id1", EventType: constants.HAConfigMsgKey, Success: true, DurationMilliseconds: 1000,
+	}, *ack)
+
+	assert.NoError(t, producer.events[1].DataAs(ack))
+	assert.False(t, ack.Success)
+	assert.Equal(t, "forbidden", ack.Error)
+	assert.NotEqual(t, producer.events[0].Extensions()[version.ExtVersion],
+		producer.events[1].Extensions()[version.ExtVersion])
+
+	// the denied spec event is acknowledged even without the correlation id, so it's audited on the global hub
+	denied := cloudevents.NewEvent()
+	denied.SetID("event1")
+	denied.SetSource("global-hub")
+	denied.SetType(constants.HAConfigMsgKey)
+	d.acknowledge(context.Background(), &denied, &rbac.DeniedError{Operations: []string{"create"}}, time.Second)
+	assert.Len(t, producer.events, 3)
+	ack = &specack.SpecAckBundle{}
+	assert.NoError(t, producer.events[2].DataAs(ack))
+	assert.False(t, ack.Success)
+	assert.True(t, ack.Denied)
+	assert.Equal(t, "event1", ack.CorrelationID)
+	assert.Equal(t, "global-hub", ack.Source)
+}
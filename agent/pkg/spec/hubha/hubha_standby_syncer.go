@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
@@ -23,6 +24,8 @@ import (
 // HubHAStandbySyncer receives ACM resources from active hub and applies them to standby hub
 type HubHAStandbySyncer struct {
 	client client.Client
+	// enforcer applies the resources as the global hub user who initiates them if the RBAC is enforced
+	enforcer *rbac.Enforcer
 }
 
 func NewHubHAStandbySyncer(c client.Client) *HubHAStandbySyncer {
@@ -48,11 +51,22 @@ func (s *HubHAStandbySyncer) Sync(ctx context.Context, evt *cloudevents.Event) e
 
 	sourceHub := evt.Source()
 
+	if s.enforcer != nil {
+		writer, err := s.enforcer.ClientFor(evt, false)
+		if err != nil {
+			return fmt.Errorf("failed to apply Hub HA resources from active hub %s: %w", sourceHub, err)
+		}
+		ctx = withWriter(ctx, writer)
+	}
+	// the denied resources are reported together, the other failures are only logged
+	denials := &rbac.Denials{}
+
 	// Apply created resources
 	for _, obj := range bundle.Create {
 		if err := s.createResource(ctx, obj, sourceHub); err != nil {
 			log.Errorf("failed to create resource %s/%s from active hub %s: %v",
 				obj.GetNamespace(), obj.GetName(), sourceHub, err)
+			denials.Add(fmt.Sprintf("create %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()), err)
 			// Continue with other resources instead of failing entirely
 		}
 	}
@@ -62,6 +76,7 @@ func (s *HubHAStandbySyncer) Sync(ctx context.Context, evt *cloudevents.Event) e
 		if err := s.updateResource(ctx, obj, sourceHub); err != nil {
 			log.Errorf("failed to update resource %s/%s from active hub %s: %v",
 				obj.GetNamespace(), obj.GetName(), sourceHub, err)
+			denials.Add(fmt.Sprintf("update %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()), err)
 		}
 	}
 
@@ -70,6 +85,7 @@ func (s *HubHAStandbySyncer) Sync(ctx context.Context, evt *cloudevents.Event) e
 		if err := s.updateResource(ctx, obj, sourceHub); err != nil {
 			log.Errorf("failed to resync resource %s/%s from active hub %s: %v",
 				obj.GetNamespace(), obj.GetName(), sourceHub, err)
+			denials.Add(fmt.Sprintf("update %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()), err)
 		}
 	}
 
@@ -78,13 +94,35 @@ func (s *HubHAStandbySyncer) Sync(ctx context.Context, evt *cloudevents.Event) e
 		if err := s.deleteResource(ctx, &meta, sourceHub); err != nil {
 			log.Errorf("failed to delete resource %s/%s from active hub %s: %v",
 				meta.Namespace, meta.Name, sourceHub, err)
+			denials.Add(fmt.Sprintf("delete %s %s/%s", meta.Kind, meta.Namespace, meta.Name), err)
 		}
 	}
 
 	log.Infof("standby hub processed Hub HA bundle from %s: created=%d, updated=%d, resynced=%d, deleted=%d",
 		sourceHub, len(bundle.Create), len(bundle.Update), len(bundle.Resync), len(bundle.Delete))
 
-	return nil
+	return denials.Err()
+}
+
+// SetEnforcer sets the enforcer to apply the resources as the global hub user who initiates them
+func (s *HubHAStandbySyncer) SetEnforcer(enforcer *rbac.Enforcer) {
+	s.enforcer = enforcer
+}
+
+type writerKeyType struct{}
+
+var writerKey = writerKeyType{}
+
+// withWriter sets the client to apply the resources of the event, it's the impersonated client if the RBAC is enforced
+func withWriter(ctx context.Context, writer client.Client) context.Context {
+	return context.WithValue(ctx, writerKey, writer)
+}
+
+func (s *HubHAStandbySyncer) writer(ctx context.Context) client.Client {
+	if writer, ok := ctx.Value(writerKey).(client.Client); ok {
+		return writer
+	}
+	return s.client
 }
 
 func (s *HubHAStandbySyncer) createResource(ctx context.Context, obj *unstructured.Unstructured,
@@ -108,7 +146,7 @@ func (s *HubHAStandbySyncer) createResource(ctx context.Context, obj *unstructur
 	}
 
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		_, err := controllerutil.CreateOrUpdate(ctx, s.client, obj, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, s.writer(ctx), obj, func() error {
 			// Resource will be created or updated as needed
 			return nil
 		})
@@ -147,16 +185,16 @@ func (s *HubHAStandbySyncer) updateResource(ctx context.Context, obj *unstructur
 		existing := &unstructured.Unstructured{}
 		existing.SetGroupVersionKind(obj.GroupVersionKind())
 
-		if err := s.client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if err := s.writer(ctx).Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
 			// Resource doesn't exist, create it
-			return s.client.Create(ctx, obj)
+			return s.writer(ctx).Create(ctx, obj)
 		}
 
 		// Preserve some metadata from existing resource
 		obj.SetResourceVersion(existing.GetResourceVersion())
 		obj.SetUID(existing.GetUID())
 
-		return s.client.Update(ctx, obj)
+		return s.writer(ctx).Update(ctx, obj)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update resource: %w", err)
@@ -187,7 +225,7 @@ func (s *HubHAStandbySyncer) deleteResource(ctx context.Context, meta *generic.O
 
 	// Delete the resource
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return s.client.Delete(ctx, obj)
+		return s.writer(ctx).Delete(ctx, obj)
 	})
 	if err != nil {
 		if errors.IsNotFound(err) {
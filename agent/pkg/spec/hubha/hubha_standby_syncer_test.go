@@ -5,6 +5,8 @@ package hubha
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -13,9 +15,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
@@ -545,3 +551,52 @@ func TestHubHAStandbySyncer_UpdateManagedCluster_SetsHubAcceptsClient(t *testing
 		t.Errorf("Labels not updated correctly, got %v", labels)
 	}
 }
+
+func TestHubHAStandbySyncer_Sync_Denied(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if obj.GetName() == "denied-cm" {
+				return errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+					fmt.Errorf("the user can't create configmaps"))
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	syncer := NewHubHAStandbySyncer(c)
+
+	bundle := generic.NewGenericBundle[*unstructured.Unstructured]()
+	for _, name := range []string{"denied-cm", "new-cm"} {
+		bundle.Create = append(bundle.Create, &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+			},
+		})
+	}
+	evt := cloudevents.NewEvent()
+	evt.SetType(constants.HubHAResourcesMsgKey)
+	evt.SetSource("hub1")
+	if err := evt.SetData(cloudevents.ApplicationJSON, bundle); err != nil {
+		t.Fatalf("SetData() error = %v", err)
+	}
+
+	// the denied resource is reported, and the others are still applied
+	err := syncer.Sync(context.Background(), &evt)
+	if !rbac.IsDenied(err) {
+		t.Fatalf("Sync() error = %v, want denied error", err)
+	}
+	if !strings.Contains(err.Error(), "create ConfigMap default/denied-cm") {
+		t.Errorf("Sync() error = %v, want the denied resource", err)
+	}
+	newCM := &unstructured.Unstructured{}
+	newCM.SetGroupVersionKind(bundle.Create[1].GroupVersionKind())
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "new-cm", Namespace: "default"},
+		newCM); err != nil {
+		t.Errorf(errFailedToGetCreatedResource, err)
+	}
+}
@@ -104,6 +104,7 @@ func (s *MigrationSourceSyncer) Sync(ctx context.Context, evt *cloudevents.Event
 	}
 
 	ctx = withExpireTime(ctx, parseExpireTime(evt))
+	ctx = withUserIdentity(ctx, utils.UserIdentityOf(evt))
 
 	// Parse migration event
 	migrationEvent := &migration.MigrationSourceBundle{}
@@ -405,6 +406,8 @@ func (s *MigrationSourceSyncer) sendMigrationBundle(
 	expireAfter := remainingExpireTime(expireTimeFromContext(ctx))
 	e := utils.ToMigrationEvent(eventType, fromHub, toHub,
 		s.processingMigrationId, migrationv1alpha1.PhaseDeploying, expireAfter, payloadBytes)
+	// the target hub impersonates the requester to deploy the resources if the RBAC is enforced
+	utils.SetUserIdentity(&e, userIdentityFromContext(ctx))
 
 	if err := s.transportClient.GetProducer().SendEvent(
 		cecontext.WithTopic(ctx, s.transportConfig.KafkaCredential.SpecTopic), e,
@@ -659,6 +662,22 @@ func expireTimeFromContext(ctx context.Context) time.Time {
 	return time.Time{}
 }
 
+// Context key for passing the requester of the migration, it's forwarded with the resources to the target hub
+type userIdentityKeyType struct{}
+
+var userIdentityKey = userIdentityKeyType{}
+
+func withUserIdentity(ctx context.Context, annotations map[string]string) context.Context {
+	return context.WithValue(ctx, userIdentityKey, annotations)
+}
+
+func userIdentityFromContext(ctx context.Context) map[string]string {
+	if v, ok := ctx.Value(userIdentityKey).(map[string]string); ok {
+		return v
+	}
+	return nil
+}
+
 // remainingExpireTime returns the duration until expireTime.
 // Returns 10 minutes if expireTime is zero (not set), or 0 if already past.
 func remainingExpireTime(expireTime time.Time) time.Duration {
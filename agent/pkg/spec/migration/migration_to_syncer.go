@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/migration"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
@@ -115,6 +116,8 @@ type MigrationTargetSyncer struct {
 	deployingProcessedClusters map[string]bool // Track which clusters have been processed
 	mu                         sync.Mutex
 	completedStages            map[string]string // tracks stage state: "in-progress" or "completed"
+	// enforcer deploys the resources as the requester of the migration if the RBAC is enforced
+	enforcer *rbac.Enforcer
 }
 
 func NewMigrationTargetSyncer(client client.Client,
@@ -551,9 +554,18 @@ func (s *MigrationTargetSyncer) deploying(ctx context.Context, evt *cloudevents.
 	}
 	s.mu.Unlock()
 
+	// the resources are deployed by the requester of the migration if the RBAC is enforced
+	resourceClient := s.client
+	if s.enforcer != nil {
+		var err error
+		if resourceClient, err = s.enforcer.ClientFor(evt, true); err != nil {
+			return fmt.Errorf("failed to deploy the resources of migration %s: %w", resourceEvent.MigrationId, err)
+		}
+	}
+
 	// Process the resources in this batch (outside lock to avoid blocking)
 	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		return s.syncMigrationResources(ctx, resourceEvent)
+		return s.syncMigrationResources(ctx, resourceClient, resourceEvent)
 	}); err != nil {
 		return err
 	}
@@ -591,7 +603,7 @@ func (s *MigrationTargetSyncer) ensureNamespace(ctx context.Context, namespace s
 	return nil
 }
 
-func (s *MigrationTargetSyncer) syncMigrationResources(ctx context.Context,
+func (s *MigrationTargetSyncer) syncMigrationResources(ctx context.Context, resourceClient client.Client,
 	migrationResources *migration.MigrationResourceBundle,
 ) error {
 	log.Infof("deploying: processing %d clusters", len(migrationResources.MigrationClusterResources))
@@ -615,7 +627,7 @@ func (s *MigrationTargetSyncer) syncMigrationResources(ctx context.Context,
 				clusterName, resIdx+1, len(clusterResource.ResourceList),
 				resource.GetKind(), resource.GetName(), resource.GetNamespace())
 
-			if err := s.syncResource(ctx, &resource); err != nil {
+			if err := s.syncResource(ctx, resourceClient, &resource); err != nil {
 				return fmt.Errorf("failed to sync resource %s/%s for cluster %s: %w",
 					resource.GetKind(), resource.GetName(), clusterName, err)
 			}
@@ -637,7 +649,9 @@ func (s *MigrationTargetSyncer) syncMigrationResources(ctx context.Context,
 
 // syncResource handles syncing individual resources from the migration bundle
 // Works directly with unstructured resources without type conversion
-func (s *MigrationTargetSyncer) syncResource(ctx context.Context, resource *unstructured.Unstructured) error {
+func (s *MigrationTargetSyncer) syncResource(ctx context.Context, resourceClient client.Client,
+	resource *unstructured.Unstructured,
+) error {
 	resourceKey := fmt.Sprintf("%s/%s/%s", resource.GetKind(), resource.GetNamespace(), resource.GetName())
 	log.Debugf("deploying: syncResource started for resource=%s", resourceKey)
 
@@ -665,7 +679,7 @@ func (s *MigrationTargetSyncer) syncResource(ctx context.Context, resource *unst
 
 	log.Debugf("deploying: creating or updating resource=%s", resourceKey)
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		operation, err := controllerutil.CreateOrUpdate(ctx, s.client, resource,
+		operation, err := controllerutil.CreateOrUpdate(ctx, resourceClient, resource,
 			func() error {
 				// Apply labels and annotations from source
 				if sourceLabels != nil {
@@ -709,7 +723,7 @@ func (s *MigrationTargetSyncer) syncResource(ctx context.Context, resource *unst
 			// Get the latest version of the resource to update status
 			latestResource := &unstructured.Unstructured{}
 			latestResource.SetGroupVersionKind(resource.GroupVersionKind())
-			if err := s.client.Get(ctx, client.ObjectKeyFromObject(resource), latestResource); err != nil {
+			if err := resourceClient.Get(ctx, client.ObjectKeyFromObject(resource), latestResource); err != nil {
 				return fmt.Errorf("failed to get latest resource: %w", err)
 			}
 
@@ -719,7 +733,7 @@ func (s *MigrationTargetSyncer) syncResource(ctx context.Context, resource *unst
 			}
 
 			// Update only the status subresource
-			return s.client.Status().Update(ctx, latestResource)
+			return resourceClient.Status().Update(ctx, latestResource)
 		})
 		if err != nil {
 			log.Errorf("deploying: failed to update status for resource=%s: %v", resourceKey, err)
@@ -1512,6 +1526,11 @@ func deleteResourceIfExists(ctx context.Context, c client.Client, obj client.Obj
 	return nil
 }
 
+// SetEnforcer sets the enforcer to deploy the resources as the requester of the migration
+func (s *MigrationTargetSyncer) SetEnforcer(enforcer *rbac.Enforcer) {
+	s.enforcer = enforcer
+}
+
 // SetMigrationID sets the processing migration ID for testing purposes
 func (s *MigrationTargetSyncer) SetMigrationID(migrationID string) {
 	s.processingMigrationId = migrationID
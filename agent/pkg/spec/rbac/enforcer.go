@@ -0,0 +1,155 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// ErrNoIdentity is returned if the RBAC is enforced, but the spec event doesn't carry the global hub user
+var ErrNoIdentity = errors.New("the spec event has no global hub user identity")
+
+// NewEnforcer creates the enforcer, the writes are made by the agent client unless the RBAC is enforced.
+func NewEnforcer(config *rest.Config, agentClient client.Client, enforced bool) *Enforcer {
+	manager := NewImpersonationManager(config)
+	return &Enforcer{
+		enforced:    enforced,
+		client:      agentClient,
+		manager:     manager,
+		impersonate: manager.Impersonate,
+		clients:     map[string]client.Client{},
+	}
+}
+
+// Enforcer provides the clients for the spec syncers to write the hub. If the RBAC is enforced by the
+// --enforce-hoh-rbac, the writes are made by the global hub user who initiates the spec event, so the global hub users
+// can't escalate their privileges on the managed hub.
+type Enforcer struct {
+	enforced    bool
+	client      client.Client
+	manager     *ImpersonationManager
+	impersonate func(userIdentity string, userGroups []string) (client.Client, error)
+
+	mu      sync.Mutex
+	clients map[string]client.Client // the impersonated clients by the user and groups
+}
+
+// Enforced returns whether the writes are made by the global hub users.
+func (e *Enforcer) Enforced() bool {
+	return e.enforced
+}
+
+// ClientFor returns the client to write the hub for the spec event. If the RBAC is enforced, it impersonates the
+// global hub user carried by the event. The events initiated by the global hub itself carry no user, they're written by
+// the agent client unless the identity is required.
+func (e *Enforcer) ClientFor(evt *cloudevents.Event, identityRequired bool) (client.Client, error) {
+	if !e.enforced {
+		return e.client, nil
+	}
+	userIdentity, userGroups, err := e.identityOf(evt)
+	if err != nil {
+		return nil, err
+	}
+	if userIdentity == NoIdentity {
+		if identityRequired {
+			return nil, ErrNoIdentity
+		}
+		return e.client, nil
+	}
+
+	key := userIdentity + "/" + strings.Join(userGroups, ",")
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if userClient, ok := e.clients[key]; ok {
+		return userClient, nil
+	}
+	userClient, err := e.impersonate(userIdentity, userGroups)
+	if err != nil {
+		return nil, err
+	}
+	e.clients[key] = userClient
+	return userClient, nil
+}
+
+// identityOf decodes the global hub user in the extensions of the event, they're encoded as the identity annotations
+func (e *Enforcer) identityOf(evt *cloudevents.Event) (string, []string, error) {
+	userIdentity, err := types.ToString(evt.Extensions()[constants.CloudEventExtensionKeyUserIdentity])
+	if err != nil || userIdentity == "" {
+		return NoIdentity, nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	annotations := map[string]string{UserIdentityAnnotation: userIdentity}
+	if userGroups, err := types.ToString(evt.Extensions()[constants.CloudEventExtensionKeyUserGroups]); err == nil &&
+		userGroups != "" {
+		annotations[UserGroupsAnnotation] = userGroups
+	}
+	obj.SetAnnotations(annotations)
+
+	decodedIdentity, err := e.manager.GetUserIdentity(obj)
+	if err != nil {
+		return NoIdentity, nil, err
+	}
+	if _, ok := annotations[UserGroupsAnnotation]; !ok {
+		return decodedIdentity, nil, nil
+	}
+	_, decodedGroups, err := e.manager.GetUserGroups(obj)
+	if err != nil {
+		return NoIdentity, nil, err
+	}
+	return decodedIdentity, decodedGroups, nil
+}
+
+// DeniedError is returned by the spec syncers if the operations are denied to the global hub user
+type DeniedError struct {
+	Operations []string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("denied by the RBAC of the managed hub: %s", strings.Join(e.Operations, "; "))
+}
+
+// IsDenied returns whether the error is caused by the RBAC of the managed hub
+func IsDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	deniedErr := &DeniedError{}
+	return errors.As(err, &deniedErr) || errors.Is(err, ErrNoIdentity) || apierrors.IsForbidden(err)
+}
+
+// Denials collects the denied operations of a spec event, so they're reported together
+type Denials struct {
+	mu         sync.Mutex
+	operations []string
+}
+
+// Add records the operation if the error is denied, it returns false for the other errors.
+func (d *Denials) Add(operation string, err error) bool {
+	if !IsDenied(err) {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.operations = append(d.operations, fmt.Sprintf("%s: %v", operation, err))
+	return true
+}
+
+// Err returns the DeniedError of the recorded operations, or nil if none of them is denied
+func (d *Denials) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.operations) == 0 {
+		return nil
+	}
+	return &DeniedError{Operations: append([]string{}, d.operations...)}
+}
@@ -0,0 +1,100 @@
+package rbac
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newTestEnforcer(enforced bool) (*Enforcer, client.Client, *[]string) {
+	agentClient := fake.NewClientBuilder().Build()
+	enforcer := NewEnforcer(&rest.Config{}, agentClient, enforced)
+	impersonated := []string{}
+	enforcer.impersonate = func(userIdentity string, userGroups []string) (client.Client, error) {
+		impersonated = append(impersonated, userIdentity)
+		return fake.NewClientBuilder().Build(), nil
+	}
+	return enforcer, agentClient, &impersonated
+}
+
+func newUserEvent(userIdentity, userGroups string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	if userIdentity != "" {
+		evt.SetExtension(constants.CloudEventExtensionKeyUserIdentity,
+			base64.StdEncoding.EncodeToString([]byte(userIdentity)))
+	}
+	if userGroups != "" {
+		evt.SetExtension(constants.CloudEventExtensionKeyUserGroups,
+			base64.StdEncoding.EncodeToString([]byte(userGroups)))
+	}
+	return &evt
+}
+
+func TestEnforcerClientFor(t *testing.T) {
+	// the agent client is used if the RBAC isn't enforced
+	enforcer, agentClient, impersonated := newTestEnforcer(false)
+	c, err := enforcer.ClientFor(newUserEvent("alice", ""), true)
+	require.NoError(t, err)
+	assert.Same(t, agentClient, c)
+	assert.Empty(t, *impersonated)
+
+	enforcer, agentClient, impersonated = newTestEnforcer(true)
+	assert.True(t, enforcer.Enforced())
+
+	// the event without the user is written by the agent client unless the identity is required
+	c, err = enforcer.ClientFor(newUserEvent("", ""), false)
+	require.NoError(t, err)
+	assert.Same(t, agentClient, c)
+	_, err = enforcer.ClientFor(newUserEvent("", ""), true)
+	assert.ErrorIs(t, err, ErrNoIdentity)
+
+	// the user is impersonated, and the client is reused for the same user and groups
+	alice, err := enforcer.ClientFor(newUserEvent("alice", "system:authenticated,hub-admins"), true)
+	require.NoError(t, err)
+	assert.NotSame(t, agentClient, alice)
+	c, err = enforcer.ClientFor(newUserEvent("alice", "system:authenticated,hub-admins"), true)
+	require.NoError(t, err)
+	assert.Same(t, alice, c)
+	_, err = enforcer.ClientFor(newUserEvent("alice", ""), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "alice"}, *impersonated)
+
+	// the identity isn't base64 encoded
+	evt := cloudevents.NewEvent()
+	evt.SetExtension(constants.CloudEventExtensionKeyUserIdentity, "not-base64!")
+	_, err = enforcer.ClientFor(&evt, false)
+	assert.Error(t, err)
+}
+
+func TestDenials(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "cm1", errors.New("no access"))
+	assert.True(t, IsDenied(forbidden))
+	assert.True(t, IsDenied(ErrNoIdentity))
+	assert.False(t, IsDenied(errors.New("connection refused")))
+	assert.False(t, IsDenied(nil))
+
+	denials := &Denials{}
+	assert.NoError(t, denials.Err())
+	assert.False(t, denials.Add("create ConfigMap default/cm2", errors.New("connection refused")))
+	assert.True(t, denials.Add("create ConfigMap default/cm1", forbidden))
+
+	err := denials.Err()
+	require.Error(t, err)
+	assert.True(t, IsDenied(err))
+	assert.True(t, IsDenied(errors.Join(errors.New("wrapped"), err)))
+	deniedErr := &DeniedError{}
+	require.ErrorAs(t, err, &deniedErr)
+	require.Len(t, deniedErr.Operations, 1)
+	assert.Contains(t, deniedErr.Operations[0], "create ConfigMap default/cm1")
+}
@@ -0,0 +1,94 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+var revertInterval = time.Minute
+
+// policyReverter reverts the remediationAction of the root policies once their enforce windows are passed. The
+// windows are kept in the annotations of the policies, so they're reverted even if the agent is restarted.
+type policyReverter struct {
+	log    *zap.SugaredLogger
+	client client.Client
+}
+
+func AddPolicyReverter(mgr ctrl.Manager) error {
+	return mgr.Add(&policyReverter{
+		log:    logger.ZapLogger("policy-reverter"),
+		client: mgr.GetClient(),
+	})
+}
+
+func (r *policyReverter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(revertInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.revert(ctx, time.Now()); err != nil {
+				r.log.Warnw("failed to revert the enforced policies", "error", err)
+			}
+		}
+	}
+}
+
+// revert restores the original remediationAction of the policies whose enforce windows are passed
+func (r *policyReverter) revert(ctx context.Context, now time.Time) error {
+	policies := &policiesv1.PolicyList{}
+	if err := r.client.List(ctx, policies); err != nil {
+		return err
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		value, ok := policy.Annotations[EnforceUntilAnnotation]
+		if !ok {
+			continue
+		}
+		until, err := time.Parse(time.RFC3339, value)
+		if err == nil && now.Before(until) {
+			continue
+		}
+
+		// a malformed window is reverted immediately
+		patch := map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]any{
+					EnforceUntilAnnotation:              nil,
+					OriginalRemediationActionAnnotation: nil,
+				},
+			},
+		}
+		if original, ok := policy.Annotations[OriginalRemediationActionAnnotation]; ok {
+			var remediationAction any = original
+			if original == "" { // the policy had no remediationAction
+				remediationAction = nil
+			}
+			patch["spec"] = map[string]any{"remediationAction": remediationAction}
+		}
+		patchBytes, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+		if err := r.client.Patch(ctx, policy, client.RawPatch(types.MergePatchType, patchBytes)); err != nil {
+			r.log.Warnw("failed to revert the enforced policy", "namespace", policy.Namespace, "name", policy.Name,
+				"error", err)
+			continue
+		}
+		r.log.Infow("reverted the enforced policy", "namespace", policy.Namespace, "name", policy.Name,
+			"remediationAction", policy.Annotations[OriginalRemediationActionAnnotation])
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+func TestPolicyReverter(t *testing.T) {
+	now := time.Now()
+	expired := newPolicy("expired", "enforce")
+	expired.Annotations = map[string]string{
+		EnforceUntilAnnotation:              now.Add(-time.Minute).UTC().Format(time.RFC3339),
+		OriginalRemediationActionAnnotation: "inform",
+	}
+	enforcing := newPolicy("enforcing", "enforce")
+	enforcing.Annotations = map[string]string{
+		EnforceUntilAnnotation:              now.Add(time.Minute).UTC().Format(time.RFC3339),
+		OriginalRemediationActionAnnotation: "inform",
+	}
+	untouched := newPolicy("untouched", "enforce")
+	_, c, _ := newSyncer(t, expired, enforcing, untouched)
+
+	reverter := &policyReverter{log: logger.ZapLogger("policy-reverter-test"), client: c}
+	require.NoError(t, reverter.revert(context.TODO(), now))
+
+	policy := getPolicy(t, c, "expired")
+	assert.Equal(t, policiesv1.RemediationAction("inform"), policy.Spec.RemediationAction)
+	assert.NotContains(t, policy.Annotations, EnforceUntilAnnotation)
+	assert.NotContains(t, policy.Annotations, OriginalRemediationActionAnnotation)
+
+	policy = getPolicy(t, c, "enforcing")
+	assert.Equal(t, policiesv1.RemediationAction("enforce"), policy.Spec.RemediationAction)
+	assert.Contains(t, policy.Annotations, EnforceUntilAnnotation)
+
+	assert.Equal(t, policiesv1.RemediationAction("enforce"), getPolicy(t, c, "untouched").Spec.RemediationAction)
+
+	// the enforcing policy is reverted once its window is passed
+	require.NoError(t, reverter.revert(context.TODO(), now.Add(2*time.Minute)))
+	assert.Equal(t, policiesv1.RemediationAction("inform"), getPolicy(t, c, "enforcing").Spec.RemediationAction)
+}
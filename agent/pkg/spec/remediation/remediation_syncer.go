@@ -0,0 +1,328 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// EnforceUntilAnnotation is the end of the enforce window of the root policy, the remediationAction of the policy
+	// is reverted by the reverter once it's passed
+	EnforceUntilAnnotation = "global-hub.open-cluster-management.io/remediation-enforce-until"
+	// OriginalRemediationActionAnnotation is the remediationAction of the root policy before it's enforced
+	OriginalRemediationActionAnnotation = "global-hub.open-cluster-management.io/remediation-original-action"
+	// triggerUpdateAnnotation is from governance-policy-propagator, the propagator reprocesses the root policy once
+	// its value is changed
+	triggerUpdateAnnotation = "policy.open-cluster-management.io/trigger-update"
+
+	defaultEnforceDuration = time.Hour
+)
+
+var (
+	policyGVK = schema.GroupVersionKind{
+		Group:   "policy.open-cluster-management.io",
+		Version: "v1",
+		Kind:    "Policy",
+	}
+	policyAutomationGVK = schema.GroupVersionKind{
+		Group:   "policy.open-cluster-management.io",
+		Version: "v1beta1",
+		Kind:    "PolicyAutomation",
+	}
+)
+
+// remediationSyncer runs the remediation actions requested by the PolicyRemediationRequest on the root policies of
+// the managed hub. The actions are run as the requester, so the RBAC of the managed hub is enforced on them, and the
+// results are reported back to the manager.
+type remediationSyncer struct {
+	log         *zap.SugaredLogger
+	producer    transport.Producer
+	identityOf  func(bundle *remediation.PolicyRemediationBundle) (string, []string, error)
+	impersonate func(userIdentity string, userGroups []string) (client.Client, error)
+
+	resultVersion   *version.Version
+	resultVersionMu sync.Mutex
+}
+
+func NewRemediationSyncer(mgr ctrl.Manager, producer transport.Producer) *remediationSyncer {
+	impersonationManager := rbac.NewImpersonationManager(mgr.GetConfig())
+	return &remediationSyncer{
+		log:      logger.ZapLogger("remediation-syncer"),
+		producer: producer,
+		identityOf: func(bundle *remediation.PolicyRemediationBundle) (string, []string, error) {
+			return identityOf(impersonationManager, bundle)
+		},
+		impersonate:   impersonationManager.Impersonate,
+		resultVersion: version.NewVersion(),
+	}
+}
+
+func (s *remediationSyncer) Sync(ctx context.Context, evt *cloudevents.Event) error {
+	bundle := &remediation.PolicyRemediationBundle{}
+	if err := evt.DataAs(bundle); err != nil {
+		return fmt.Errorf("failed to unmarshal the policy remediation bundle - %w", err)
+	}
+
+	result := &remediation.PolicyRemediationResultBundle{ID: bundle.ID}
+	userClient, userIdentity, err := s.userClient(bundle)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		for _, ref := range bundle.Policies {
+			policyResult := s.remediate(ctx, userClient, bundle, ref)
+			// record the action with the requester on the managed hub
+			s.log.Infow("remediated the policy", "id", bundle.ID, "action", bundle.Action, "user", userIdentity,
+				"namespace", ref.Namespace, "name", ref.Name, "result", policyResult.Result,
+				"message", policyResult.Message)
+			result.Policies = append(result.Policies, policyResult)
+		}
+	}
+
+	if sendErr := s.sendResult(ctx, result); sendErr != nil {
+		return fmt.Errorf("failed to report the results of the policy remediation %s - %w", bundle.ID, sendErr)
+	}
+	return err
+}
+
+// userClient returns the client which impersonates the requester, the request is rejected if it has no requester
+func (s *remediationSyncer) userClient(bundle *remediation.PolicyRemediationBundle) (client.Client, string, error) {
+	userIdentity, userGroups, err := s.identityOf(bundle)
+	if err != nil {
+		return nil, "", err
+	}
+	if userIdentity == rbac.NoIdentity {
+		return nil, "", fmt.Errorf("the policy remediation %s has no user identity", bundle.ID)
+	}
+	userClient, err := s.impersonate(userIdentity, userGroups)
+	if err != nil {
+		return nil, "", err
+	}
+	return userClient, userIdentity, nil
+}
+
+// remediate runs the action on the root policy, the policy is skipped if it isn't found, it's a replicated policy,
+// or it isn't propagated to any of the requested clusters
+func (s *remediationSyncer) remediate(ctx context.Context, c client.Client,
+	bundle *remediation.PolicyRemediationBundle, ref remediation.PolicyRef,
+) remediation.PolicyRemediationResult {
+	result := remediation.PolicyRemediationResult{Namespace: ref.Namespace, Name: ref.Name}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(policyGVK)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, policy); err != nil {
+		if errors.IsNotFound(err) {
+			return skipped(result, "the policy isn't found")
+		}
+		return failed(result, err)
+	}
+	if _, ok := policy.GetLabels()[constants.PolicyEventRootPolicyNameLabelKey]; ok {
+		return skipped(result, "the policy is a replicated policy")
+	}
+	if len(bundle.Clusters) > 0 && !propagatedTo(policy, bundle.Clusters) {
+		return skipped(result, "the policy isn't propagated to the clusters")
+	}
+
+	var err error
+	switch bundle.Action {
+	case remediationv1alpha1.ActionEnforce:
+		var message string
+		message, err = enforce(ctx, c, policy, bundle.EnforceDurationSeconds)
+		if err == nil && message != "" {
+			return skipped(result, message)
+		}
+	case remediationv1alpha1.ActionReevaluate:
+		err = patchPolicy(ctx, c, policy, map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]any{triggerUpdateAnnotation: time.Now().UTC().Format(time.RFC3339Nano)},
+			},
+		})
+	case remediationv1alpha1.ActionCreateAutomation:
+		err = createAutomation(ctx, c, policy, bundle)
+		if errors.IsAlreadyExists(err) {
+			return skipped(result, "the policy automation already exists")
+		}
+	default:
+		err = fmt.Errorf("unsupported remediation action %s", bundle.Action)
+	}
+	if err != nil {
+		return failed(result, err)
+	}
+	result.Result = remediationv1alpha1.ResultSucceeded
+	return result
+}
+
+// enforce switches the remediationAction of the policy to enforce until the end of the window, the original action is
+// kept in the annotation for the reverter. It returns the message if the policy is already enforced by the owner.
+func enforce(ctx context.Context, c client.Client, policy *unstructured.Unstructured, durationSeconds int64,
+) (string, error) {
+	duration := time.Duration(durationSeconds) * time.Second
+	if duration <= 0 {
+		duration = defaultEnforceDuration
+	}
+	original, _, _ := unstructured.NestedString(policy.Object, "spec", "remediationAction")
+	annotations := map[string]any{
+		EnforceUntilAnnotation: time.Now().Add(duration).UTC().Format(time.RFC3339),
+	}
+	// the original action is kept if the policy is enforced by a previous request
+	if _, enforcing := policy.GetAnnotations()[OriginalRemediationActionAnnotation]; !enforcing {
+		if isEnforce(original) {
+			return "the policy is already enforced", nil
+		}
+		annotations[OriginalRemediationActionAnnotation] = original
+	}
+	return "", patchPolicy(ctx, c, policy, map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+		"spec":     map[string]any{"remediationAction": "enforce"},
+	})
+}
+
+// createAutomation creates the PolicyAutomation which runs the ansible job once the policy is noncompliant, the
+// requested clusters are passed to the job as target_clusters
+func createAutomation(ctx context.Context, c client.Client, policy *unstructured.Unstructured,
+	bundle *remediation.PolicyRemediationBundle,
+) error {
+	if bundle.Automation == nil {
+		return fmt.Errorf("the automation isn't specified")
+	}
+	mode := bundle.Automation.Mode
+	if mode == "" {
+		mode = "once"
+	}
+	automationDef := map[string]any{
+		"type":   "AnsibleJob",
+		"name":   bundle.Automation.TemplateName,
+		"secret": bundle.Automation.TowerSecret,
+	}
+	if len(bundle.Clusters) > 0 {
+		clusters := make([]any, 0, len(bundle.Clusters))
+		for _, cluster := range bundle.Clusters {
+			clusters = append(clusters, cluster)
+		}
+		automationDef["extra_vars"] = map[string]any{"target_clusters": clusters}
+	}
+
+	automation := &unstructured.Unstructured{}
+	automation.SetGroupVersionKind(policyAutomationGVK)
+	automation.SetNamespace(policy.GetNamespace())
+	automation.SetName(policy.GetName() + "-policy-automation")
+	automation.Object["spec"] = map[string]any{
+		"policyRef":     policy.GetName(),
+		"mode":          mode,
+		"eventHook":     "noncompliant",
+		"automationDef": automationDef,
+	}
+	return c.Create(ctx, automation)
+}
+
+func patchPolicy(ctx context.Context, c client.Client, policy *unstructured.Unstructured, patch map[string]any,
+) error {
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, policy, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
+// propagatedTo returns true if the root policy is propagated to any of the clusters
+func propagatedTo(policy *unstructured.Unstructured, clusters []string) bool {
+	statuses, _, _ := unstructured.NestedSlice(policy.Object, "status", "status")
+	for _, status := range statuses {
+		statusMap, ok := status.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, cluster := range clusters {
+			if statusMap["clustername"] == cluster {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isEnforce(remediationAction string) bool {
+	return remediationAction == "enforce" || remediationAction == "Enforce"
+}
+
+func skipped(result remediation.PolicyRemediationResult, message string) remediation.PolicyRemediationResult {
+	result.Result = remediationv1alpha1.ResultSkipped
+	result.Message = message
+	return result
+}
+
+func failed(result remediation.PolicyRemediationResult, err error) remediation.PolicyRemediationResult {
+	result.Result = remediationv1alpha1.ResultFailed
+	result.Message = err.Error()
+	return result
+}
+
+// identityOf decodes the requester with the impersonation manager, the identity is carried as the annotations
+func identityOf(manager *rbac.ImpersonationManager, bundle *remediation.PolicyRemediationBundle,
+) (string, []string, error) {
+	obj := &unstructured.Unstructured{}
+	annotations := map[string]string{rbac.UserIdentityAnnotation: bundle.UserIdentity}
+	if bundle.UserGroups != "" {
+		annotations[rbac.UserGroupsAnnotation] = bundle.UserGroups
+	}
+	obj.SetAnnotations(annotations)
+
+	userIdentity, err := manager.GetUserIdentity(obj)
+	if err != nil {
+		return rbac.NoIdentity, nil, err
+	}
+	if bundle.UserGroups == "" {
+		return userIdentity, nil, nil
+	}
+	_, userGroups, err := manager.GetUserGroups(obj)
+	if err != nil {
+		return rbac.NoIdentity, nil, err
+	}
+	return userIdentity, userGroups, nil
+}
+
+func (s *remediationSyncer) sendResult(ctx context.Context, result *remediation.PolicyRemediationResultBundle) error {
+	payloadBytes, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the policy remediation result bundle - %w", err)
+	}
+
+	s.resultVersionMu.Lock()
+	defer s.resultVersionMu.Unlock()
+	s.resultVersion.Incr()
+	e := cloudevents.NewEvent()
+	e.SetType(string(enum.PolicyRemediationResultType))
+	e.SetSource(configs.GetLeafHubName())
+	e.SetSubject(constants.CloudEventGlobalHubClusterName)
+	e.SetExtension(constants.CloudEventExtensionKeyRemediationId, result.ID)
+	e.SetExtension(version.ExtVersion, s.resultVersion.String())
+	if err := e.SetData(cloudevents.ApplicationJSON, payloadBytes); err != nil {
+		return fmt.Errorf("failed to set the policy remediation result payload - %w", err)
+	}
+	if err := s.producer.SendEvent(ctx, e); err != nil {
+		return err
+	}
+	s.resultVersion.Next()
+	return nil
+}
@@ -0,0 +1,238 @@
+package remediation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	policiesv1beta1 "open-cluster-management.io/governance-policy-propagator/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type eventProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *eventProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *eventProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func newPolicy(name, remediationAction string, clusters ...string) *policiesv1.Policy {
+	policy := &policiesv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policiesv1.PolicySpec{
+			RemediationAction: policiesv1.RemediationAction(remediationAction),
+			PolicyTemplates:   []*policiesv1.PolicyTemplate{},
+		},
+	}
+	for _, cluster := range clusters {
+		policy.Status.Status = append(policy.Status.Status, &policiesv1.CompliancePerClusterStatus{
+			ClusterName: cluster, ClusterNamespace: cluster, ComplianceState: policiesv1.NonCompliant,
+		})
+	}
+	return policy
+}
+
+func newSyncer(t *testing.T, objects ...client.Object) (*remediationSyncer, client.Client, *eventProducer) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "hub1"})
+	scheme := runtime.NewScheme()
+	require.NoError(t, policiesv1.AddToScheme(scheme))
+	require.NoError(t, policiesv1beta1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(objects...).Build()
+
+	impersonationManager := rbac.NewImpersonationManager(&rest.Config{})
+	producer := &eventProducer{}
+	return &remediationSyncer{
+		log:      logger.ZapLogger("remediation-syncer-test"),
+		producer: producer,
+		identityOf: func(bundle *remediation.PolicyRemediationBundle) (string, []string, error) {
+			return identityOf(impersonationManager, bundle)
+		},
+		impersonate: func(userIdentity string, userGroups []string) (client.Client, error) {
+			return fakeClient, nil
+		},
+		resultVersion: version.NewVersion(),
+	}, fakeClient, producer
+}
+
+func syncBundle(t *testing.T, syncer *remediationSyncer, producer *eventProducer,
+	bundle *remediation.PolicyRemediationBundle,
+) (*remediation.PolicyRemediationResultBundle, error) {
+	payload, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	evt := cloudevents.NewEvent()
+	evt.SetType(constants.PolicyRemediationMsgKey)
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, payload))
+
+	syncErr := syncer.Sync(context.TODO(), &evt)
+	require.NotEmpty(t, producer.events)
+	reported := producer.events[len(producer.events)-1]
+	assert.Equal(t, string(enum.PolicyRemediationResultType), reported.Type())
+	assert.Equal(t, "hub1", reported.Source())
+	assert.Equal(t, bundle.ID, reported.Extensions()[constants.CloudEventExtensionKeyRemediationId])
+	result := &remediation.PolicyRemediationResultBundle{}
+	require.NoError(t, reported.DataAs(result))
+	return result, syncErr
+}
+
+func newBundle(action string, policies ...string) *remediation.PolicyRemediationBundle {
+	bundle := &remediation.PolicyRemediationBundle{
+		ID:           "remediation-uid",
+		Action:       action,
+		UserIdentity: base64.StdEncoding.EncodeToString([]byte("alice")),
+		UserGroups:   base64.StdEncoding.EncodeToString([]byte("policy-operators")),
+	}
+	for _, policy := range policies {
+		bundle.Policies = append(bundle.Policies, remediation.PolicyRef{Namespace: "default", Name: policy})
+	}
+	return bundle
+}
+
+func getPolicy(t *testing.T, c client.Client, name string) *policiesv1.Policy {
+	policy := &policiesv1.Policy{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, policy))
+	return policy
+}
+
+func TestRemediationSyncerEnforce(t *testing.T) {
+	replicated := newPolicy("default.policy1", "inform")
+	replicated.Namespace = "cluster1"
+	replicated.Labels = map[string]string{constants.PolicyEventRootPolicyNameLabelKey: "default.policy1"}
+	syncer, c, producer := newSyncer(t,
+		newPolicy("policy1", "inform", "cluster1"),
+		newPolicy("policy2", "enforce", "cluster1"),
+		newPolicy("policy3", "inform", "cluster2"),
+		replicated,
+	)
+
+	bundle := newBundle(remediationv1alpha1.ActionEnforce, "policy1", "policy2", "policy3", "policy4")
+	bundle.Clusters = []string{"cluster1"}
+	bundle.EnforceDurationSeconds = 600
+	result, err := syncBundle(t, syncer, producer, bundle)
+	require.NoError(t, err)
+	require.Len(t, result.Policies, 4)
+	assert.Equal(t, remediationv1alpha1.ResultSucceeded, result.Policies[0].Result)
+	assert.Equal(t, remediationv1alpha1.ResultSkipped, result.Policies[1].Result)
+	assert.Equal(t, "the policy is already enforced", result.Policies[1].Message)
+	assert.Equal(t, remediationv1alpha1.ResultSkipped, result.Policies[2].Result)
+	assert.Equal(t, "the policy isn't propagated to the clusters", result.Policies[2].Message)
+	assert.Equal(t, remediationv1alpha1.ResultSkipped, result.Policies[3].Result)
+	assert.Equal(t, "the policy isn't found", result.Policies[3].Message)
+
+	policy := getPolicy(t, c, "policy1")
+	assert.Equal(t, policiesv1.RemediationAction("enforce"), policy.Spec.RemediationAction)
+	assert.Equal(t, "inform", policy.Annotations[OriginalRemediationActionAnnotation])
+	until, err := time.Parse(time.RFC3339, policy.Annotations[EnforceUntilAnnotation])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), until, time.Minute)
+
+	// the window is extended by the next request, and the original action is kept
+	bundle = newBundle(remediationv1alpha1.ActionEnforce, "policy1")
+	result, err = syncBundle(t, syncer, producer, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, remediationv1alpha1.ResultSucceeded, result.Policies[0].Result)
+	policy = getPolicy(t, c, "policy1")
+	assert.Equal(t, "inform", policy.Annotations[OriginalRemediationActionAnnotation])
+	until, err = time.Parse(time.RFC3339, policy.Annotations[EnforceUntilAnnotation])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(defaultEnforceDuration), until, time.Minute)
+
+	// the replicated policy isn't remediated
+	bundle = newBundle(remediationv1alpha1.ActionEnforce)
+	bundle.Policies = []remediation.PolicyRef{{Namespace: "cluster1", Name: "default.policy1"}}
+	result, err = syncBundle(t, syncer, producer, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, "the policy is a replicated policy", result.Policies[0].Message)
+}
+
+func TestRemediationSyncerReevaluate(t *testing.T) {
+	syncer, c, producer := newSyncer(t, newPolicy("policy1", "inform"))
+
+	result, err := syncBundle(t, syncer, producer, newBundle(remediationv1alpha1.ActionReevaluate, "policy1"))
+	require.NoError(t, err)
+	assert.Equal(t, remediationv1alpha1.ResultSucceeded, result.Policies[0].Result)
+	assert.NotEmpty(t, getPolicy(t, c, "policy1").Annotations[triggerUpdateAnnotation])
+}
+
+func TestRemediationSyncerCreateAutomation(t *testing.T) {
+	syncer, c, producer := newSyncer(t, newPolicy("policy1", "inform", "cluster1"))
+
+	bundle := newBundle(remediationv1alpha1.ActionCreateAutomation, "policy1")
+	bundle.Clusters = []string{"cluster1"}
+	bundle.Automation = &remediation.AutomationSpec{TemplateName: "fix-policy", TowerSecret: "aap-credential"}
+	result, err := syncBundle(t, syncer, producer, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, remediationv1alpha1.ResultSucceeded, result.Policies[0].Result)
+
+	automation := &unstructured.Unstructured{}
+	automation.SetGroupVersionKind(policyAutomationGVK)
+	require.NoError(t, c.Get(context.TODO(),
+		types.NamespacedName{Namespace: "default", Name: "policy1-policy-automation"}, automation))
+	mode, _, _ := unstructured.NestedString(automation.Object, "spec", "mode")
+	assert.Equal(t, "once", mode)
+	policyRef, _, _ := unstructured.NestedString(automation.Object, "spec", "policyRef")
+	assert.Equal(t, "policy1", policyRef)
+	targets, _, _ := unstructured.NestedStringSlice(automation.Object, "spec", "automationDef", "extra_vars",
+		"target_clusters")
+	assert.Equal(t, []string{"cluster1"}, targets)
+
+	// the existing automation isn't overridden
+	result, err = syncBundle(t, syncer, producer, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, remediationv1alpha1.ResultSkipped, result.Policies[0].Result)
+}
+
+func TestRemediationSyncerNoIdentity(t *testing.T) {
+	syncer, c, producer := newSyncer(t, newPolicy("policy1", "inform"))
+
+	bundle := newBundle(remediationv1alpha1.ActionEnforce, "policy1")
+	bundle.UserIdentity = ""
+	result, err := syncBundle(t, syncer, producer, bundle)
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "no user identity")
+	assert.Empty(t, result.Policies)
+	assert.Equal(t, policiesv1.RemediationAction("inform"), getPolicy(t, c, "policy1").Spec.RemediationAction)
+}
+
+func TestIdentityOf(t *testing.T) {
+	manager := rbac.NewImpersonationManager(&rest.Config{})
+	bundle := newBundle(remediationv1alpha1.ActionEnforce)
+	bundle.UserGroups = base64.StdEncoding.EncodeToString([]byte("system:authenticated,policy-operators"))
+
+	userIdentity, userGroups, err := identityOf(manager, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", userIdentity)
+	assert.Equal(t, []string{"system:authenticated", "policy-operators"}, userGroups)
+
+	bundle.UserIdentity = "not-base64!"
+	_, _, err = identityOf(manager, bundle)
+	require.Error(t, err)
+}
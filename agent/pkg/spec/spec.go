@@ -10,6 +10,8 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/hubha"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/hubstatus"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/migration"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/remediation"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/syncers"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
@@ -29,16 +31,21 @@ func AddToManager(context context.Context, mgr ctrl.Manager, transportClient tra
 	}
 
 	// add bundle dispatcher to manager
-	dispatcher, err := AddGenericDispatcher(mgr, transportClient.GetConsumer(), agentConfig)
+	dispatcher, err := AddGenericDispatcher(mgr, transportClient.GetConsumer(), transportClient.GetProducer(),
+		agentConfig)
 	if err != nil {
 		return fmt.Errorf("failed to add bundle dispatcher to runtime manager: %w", err)
 	}
 
+	// the spec syncers write the hub as the global hub users who initiate the spec events if the RBAC is enforced
+	enforcer := rbac.NewEnforcer(mgr.GetConfig(), mgr.GetClient(), agentConfig.SpecEnforceHohRbac)
+
 	// register single migration syncer that routes internally by payload
 	sourceSyncer := migration.NewMigrationSourceSyncer(mgr.GetClient(),
 		mgr.GetConfig(), transportClient, agentConfig)
 	targetSyncer := migration.NewMigrationTargetSyncer(mgr.GetClient(),
 		transportClient, agentConfig)
+	targetSyncer.SetEnforcer(enforcer)
 	dispatcher.RegisterSyncer(string(enum.ManagedClusterMigrationType),
 		migration.NewMigrationSyncer(sourceSyncer, targetSyncer))
 	if err := migration.ResyncMigrationEvent(context, transportClient, agentConfig.TransportConfig); err != nil {
@@ -48,8 +55,9 @@ func AddToManager(context context.Context, mgr ctrl.Manager, transportClient tra
 	// Register Hub HA standby syncer if this is a standby hub
 	if agentConfig.GetHubRole() == constants.GHHubRoleStandby {
 		log.Info("registering Hub HA standby syncer - this is a standby hub")
-		dispatcher.RegisterSyncer(constants.HubHAResourcesMsgKey,
-			hubha.NewHubHAStandbySyncer(mgr.GetClient()))
+		standbySyncer := hubha.NewHubHAStandbySyncer(mgr.GetClient())
+		standbySyncer.SetEnforcer(enforcer)
+		dispatcher.RegisterSyncer(constants.HubHAResourcesMsgKey, standbySyncer)
 
 		// Register hub status syncer to handle active hub failover
 		// This syncer updates ManagedCluster.spec.hubAcceptsClient based on active hub status
@@ -58,7 +66,16 @@ func AddToManager(context context.Context, mgr ctrl.Manager, transportClient tra
 			hubstatus.NewHubStatusSyncer(mgr))
 	}
 
-	dispatcher.RegisterSyncer(constants.ResyncMsgKey, syncers.NewResyncer())
+	dispatcher.RegisterSyncer(constants.ResyncMsgKey, syncers.NewResyncer(transportClient.GetProducer()))
+
+	dispatcher.RegisterSyncer(constants.AntiEntropyRepairMsgKey, syncers.NewAntiEntropySyncer())
+
+	// the policy remediation runs as the requester, the enforced policies are reverted by the reverter
+	dispatcher.RegisterSyncer(constants.PolicyRemediationMsgKey,
+		remediation.NewRemediationSyncer(mgr, transportClient.GetProducer()))
+	if err := remediation.AddPolicyReverter(mgr); err != nil {
+		return fmt.Errorf("failed to add the policy reverter: %w", err)
+	}
 
 	dispatcher.RegisterSyncer(constants.HAConfigMsgKey,
 		hubha.NewHAConfigSyncer(mgr.GetClient(), agentConfig))
@@ -0,0 +1,41 @@
+package syncers
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// antiEntropySyncer receives the mismatched buckets from the manager, and queues them to be resent by the status
+// periodic syncer
+type antiEntropySyncer struct {
+	log *zap.SugaredLogger
+}
+
+func NewAntiEntropySyncer() *antiEntropySyncer {
+	return &antiEntropySyncer{
+		log: logger.ZapLogger("antientropy-syncer"),
+	}
+}
+
+func (s *antiEntropySyncer) Sync(ctx context.Context, evt *cloudevents.Event) error {
+	repair := &antientropy.RepairBundle{}
+	if err := evt.DataAs(repair); err != nil {
+		s.log.Errorw("failed to unmarshal the repair bundle", "error", err)
+		return err
+	}
+	if repair.EventType == "" || len(repair.Buckets) == 0 {
+		return nil
+	}
+
+	s.log.Infow("repairing the mismatched buckets", "eventType", enum.ShortenEventType(repair.EventType),
+		"buckets", len(repair.Buckets))
+	configs.GlobalRepairQueue.Add(repair)
+	return nil
+}
@@ -3,30 +3,43 @@ package syncers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/resync"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
+// ResyncAckTimeout is the maximum duration to wait for the requested event types before acknowledging the resync,
+// the event types which aren't resynced in time are reported with an error
+var ResyncAckTimeout = 5 * time.Minute
+
 var (
 	registeredResyncTypes   map[string]*version.Version
 	registeredResyncTypesMu sync.RWMutex
+
+	resyncAckVersion = version.NewVersion()
 )
 
 // resyncer resync the bundle info.
 type resyncer struct {
-	log *zap.SugaredLogger
+	log      *zap.SugaredLogger
+	producer transport.Producer
 }
 
-func NewResyncer() *resyncer {
+func NewResyncer(producer transport.Producer) *resyncer {
 	return &resyncer{
-		log: logger.ZapLogger("status-resyncer"),
+		log:      logger.ZapLogger("status-resyncer"),
+		producer: producer,
 	}
 }
 
@@ -47,9 +60,18 @@ func (s *resyncer) Sync(ctx context.Context, evt *cloudevents.Event) error {
 		return err
 	}
 
+	// the resync requested by the GlobalHubResync is acknowledged once the event types are resynced
+	resyncID, _ := evt.Extensions()[constants.CloudEventExtensionKeyResyncId].(string)
+	if resyncID != "" {
+		req := configs.GlobalResyncQueue.AddRequest(resyncID, eventTypes)
+		go s.acknowledge(ctx, req, eventTypes)
+	}
+
 	for _, eventType := range eventTypes {
 		s.log.Infow("resyncing event type", "eventType", enum.ShortenEventType(eventType))
-		configs.GlobalResyncQueue.Add(eventType)
+		if resyncID == "" {
+			configs.GlobalResyncQueue.Add(eventType)
+		}
 		// deprecated
 		registeredResyncTypesMu.RLock()
 		resyncVersion, ok := registeredResyncTypes[eventType]
@@ -63,6 +85,73 @@ func (s *resyncer) Sync(ctx context.Context, evt *cloudevents.Event) error {
 	return nil
 }
 
+// acknowledge waits for the event types of the request to be resynced, then sends the results to the manager
+func (s *resyncer) acknowledge(ctx context.Context, req *configs.ResyncRequest, eventTypes []string) {
+	timer := time.NewTimer(ResyncAckTimeout)
+	defer timer.Stop()
+	select {
+	case <-req.Done():
+	case <-timer.C:
+		s.log.Warnw("timeout to wait for the resync", "id", req.ID)
+	case <-ctx.Done():
+		return
+	}
+
+	if err := s.sendAck(ctx, toAckBundle(req, eventTypes)); err != nil {
+		s.log.Errorw("failed to acknowledge the resync", "id", req.ID, "error", err)
+		return
+	}
+	s.log.Infow("acknowledged the resync", "id", req.ID)
+}
+
+func (s *resyncer) sendAck(ctx context.Context, bundle *resync.ResyncAckBundle) error {
+	payloadBytes, err := json.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the resync ack bundle - %w", err)
+	}
+
+	resyncAckVersion.Incr()
+	e := cloudevents.NewEvent()
+	e.SetType(string(enum.ResyncAckType))
+	e.SetSource(configs.GetLeafHubName())
+	e.SetSubject(constants.CloudEventGlobalHubClusterName)
+	e.SetExtension(constants.CloudEventExtensionKeyResyncId, bundle.ID)
+	e.SetExtension(version.ExtVersion, resyncAckVersion.String())
+	if err := e.SetData(cloudevents.ApplicationJSON, payloadBytes); err != nil {
+		return fmt.Errorf("failed to set the resync ack payload - %w", err)
+	}
+	if err := s.producer.SendEvent(ctx, e); err != nil {
+		return err
+	}
+	resyncAckVersion.Next()
+	return nil
+}
+
+func toAckBundle(req *configs.ResyncRequest, eventTypes []string) *resync.ResyncAckBundle {
+	results := req.Results()
+	bundle := &resync.ResyncAckBundle{ID: req.ID, EventTypes: []resync.ResyncedEventType{}}
+	for _, eventType := range eventTypes {
+		result, ok := results[eventType]
+		if !ok {
+			continue
+		}
+		delete(results, eventType) // skip the duplicated event types
+		resynced := resync.ResyncedEventType{EventType: eventType}
+		switch {
+		case result == nil:
+			resynced.Error = "timeout to resync the event type"
+		case result.Err != nil:
+			resynced.Registered = result.Registered
+			resynced.Error = result.Err.Error()
+		default:
+			resynced.Registered = result.Registered
+			resynced.Objects = result.Objects
+		}
+		bundle.EventTypes = append(bundle.EventTypes, resynced)
+	}
+	return bundle
+}
+
 func EnableResync(evtType string, syncVersion *version.Version) {
 	registeredResyncTypesMu.Lock()
 	defer registeredResyncTypesMu.Unlock()
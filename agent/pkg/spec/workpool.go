@@ -0,0 +1,211 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// pendingPerWorker is the number of the events each worker can hold before the consumer is blocked
+const pendingPerWorker = 10
+
+var (
+	specPendingEvents = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_agent_spec_pending_events",
+		Help: "The number of the spec events which are received but not synced yet.",
+	})
+	specRunningEvents = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_agent_spec_running_events",
+		Help: "The number of the spec events which are being synced.",
+	}, []string{"type"})
+	specWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_agent_spec_wait_duration_seconds",
+		Help:    "The duration the spec events wait in the queue before they are synced.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"type"})
+)
+
+// workItem is a spec event waiting to be synced
+type workItem struct {
+	eventType string
+	queuedAt  time.Time
+	run       func()
+}
+
+// keyedWorkPool syncs the spec events with a bounded number of workers. The events with the same key are synced one
+// by one in the order they're received, and each event type can only occupy half of the workers, so a long-running
+// syncer, e.g. the migration, doesn't block the others. Submit blocks once the pending events reach the capacity,
+// which holds the consumer back.
+type keyedWorkPool struct {
+	workers chan struct{}
+	pending chan struct{}
+
+	typeLimit int
+	typeSlots map[string]chan struct{}
+
+	mu     sync.Mutex
+	queues map[string][]*workItem // a key exists while its events are being drained
+	wg     sync.WaitGroup
+}
+
+func newKeyedWorkPool(size int) *keyedWorkPool {
+	if size < 1 {
+		size = 1
+	}
+	return &keyedWorkPool{
+		workers:   make(chan struct{}, size),
+		pending:   make(chan struct{}, size*pendingPerWorker),
+		typeLimit: (size + 1) / 2,
+		typeSlots: map[string]chan struct{}{},
+		queues:    map[string][]*workItem{},
+	}
+}
+
+// Submit queues the event of the key, it blocks until there is room for the event or the context is done
+func (p *keyedWorkPool) Submit(ctx context.Context, key, eventType string, run func()) error {
+	select {
+	case p.pending <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	specPendingEvents.Inc()
+
+	p.mu.Lock()
+	queue, draining := p.queues[key]
+	p.queues[key] = append(queue, &workItem{eventType: eventType, queuedAt: time.Now(), run: run})
+	if !draining {
+		p.wg.Add(1)
+		go p.drain(ctx, key)
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+// Wait blocks until all the submitted events are synced or dropped
+func (p *keyedWorkPool) Wait() {
+	p.wg.Wait()
+}
+
+// drain syncs the events of the key in order, the key is removed once its queue is empty
+func (p *keyedWorkPool) drain(ctx context.Context, key string) {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		queue := p.queues[key]
+		if len(queue) == 0 {
+			delete(p.queues, key)
+			p.mu.Unlock()
+			return
+		}
+		item := queue[0]
+		queue[0] = nil
+		p.queues[key] = queue[1:]
+		p.mu.Unlock()
+
+		p.execute(ctx, item)
+		<-p.pending
+		specPendingEvents.Dec()
+	}
+}
+
+// execute runs the event once both a slot of its event type and a worker are acquired, the event is dropped if the
+// context is done in the meantime
+func (p *keyedWorkPool) execute(ctx context.Context, item *workItem) {
+	slots := p.slotsOf(item.eventType)
+	if !acquire(ctx, slots) {
+		return
+	}
+	defer func() { <-slots }()
+	if !acquire(ctx, p.workers) {
+		return
+	}
+	defer func() { <-p.workers }()
+
+	eventType := enum.ShortenEventType(item.eventType)
+	specWaitDuration.WithLabelValues(eventType).Observe(time.Since(item.queuedAt).Seconds())
+	specRunningEvents.WithLabelValues(eventType).Inc()
+	defer specRunningEvents.WithLabelValues(eventType).Dec()
+	item.run()
+}
+
+func (p *keyedWorkPool) slotsOf(eventType string) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slots, ok := p.typeSlots[eventType]
+	if !ok {
+		slots = make(chan struct{}, p.typeLimit)
+		p.typeSlots[eventType] = slots
+	}
+	return slots
+}
+
+func acquire(ctx context.Context, slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// workKeyOf returns the key to serialize the event. The events of a migration stage, a resync or a remediation are
+// serialized by their ids, and the other events are serialized by the event type and the object they target, e.g.
+// the status updates of the same hub. The events without a target object, e.g. the HA configs, are serialized by the
+// event type.
+func workKeyOf(evt *cloudevents.Event) string {
+	extensions := evt.Extensions()
+	if migrationId, err := cetypes.ToString(extensions[constants.CloudEventExtensionKeyMigrationId]); err == nil {
+		stage, _ := cetypes.ToString(extensions[constants.CloudEventExtensionKeyMigrationStage])
+		return evt.Type() + "/" + migrationId + "/" + stage
+	}
+	if resyncId, err := cetypes.ToString(extensions[constants.CloudEventExtensionKeyResyncId]); err == nil {
+		return evt.Type() + "/" + resyncId
+	}
+	if remediationId, err := cetypes.ToString(extensions[constants.CloudEventExtensionKeyRemediationId]); err == nil {
+		return evt.Type() + "/" + remediationId
+	}
+	if objectKey := objectKeyOf(evt.Data()); objectKey != "" {
+		return evt.Type() + "/" + objectKey
+	}
+	return evt.Type()
+}
+
+// objectTarget is the object an event payload targets, e.g. the hub of a hub status update or the event type of an
+// anti-entropy repair
+type objectTarget struct {
+	Metadata struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"metadata"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	HubName   string `json:"hubName"`
+	EventType string `json:"eventType"`
+}
+
+// objectKeyOf returns the key of the object the payload targets, it's empty if the payload isn't a single object
+func objectKeyOf(payload []byte) string {
+	target := &objectTarget{}
+	if len(payload) == 0 || json.Unmarshal(payload, target) != nil {
+		return ""
+	}
+	switch {
+	case target.Metadata.Name != "":
+		return path.Join(target.Metadata.Namespace, target.Metadata.Name)
+	case target.Name != "":
+		return path.Join(target.Namespace, target.Name)
+	case target.HubName != "":
+		return target.HubName
+	default:
+		return target.EventType
+	}
+}
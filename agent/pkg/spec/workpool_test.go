@@ -0,0 +1,151 @@
+package spec
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/hubha"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestKeyedWorkPool_Ordering(t *testing.T) {
+	pool := newKeyedWorkPool(4)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	synced := map[string][]int{}
+	for i := 0; i < 20; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			require.NoError(t, pool.Submit(ctx, key, "type-"+key, func() {
+				// the former events are slower, they're reordered without the serialization
+				time.Sleep(time.Duration(20-i) * 100 * time.Microsecond)
+				mu.Lock()
+				defer mu.Unlock()
+				synced[key] = append(synced[key], i)
+			}))
+		}
+	}
+	pool.Wait()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.Len(t, synced[key], 20)
+		for i, v := range synced[key] {
+			assert.Equal(t, i, v, "the events of the key %s are out of order", key)
+		}
+	}
+	assert.Empty(t, pool.queues)
+}
+
+func TestKeyedWorkPool_Concurrency(t *testing.T) {
+	pool := newKeyedWorkPool(4)
+	ctx := context.Background()
+
+	var running, maxRunning, maxTypeRunning atomic.Int32
+	typeRunning := map[string]*atomic.Int32{"slow": {}, "fast": {}}
+	track := func(eventType string) func() {
+		return func() {
+			setMax := func(m *atomic.Int32, v int32) {
+				for cur := m.Load(); v > cur && !m.CompareAndSwap(cur, v); cur = m.Load() {
+				}
+			}
+			setMax(&maxRunning, running.Add(1))
+			setMax(&maxTypeRunning, typeRunning[eventType].Add(1))
+			time.Sleep(5 * time.Millisecond)
+			typeRunning[eventType].Add(-1)
+			running.Add(-1)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		for _, eventType := range []string{"slow", "fast"} {
+			key := eventType + "/" + string(rune('a'+i))
+			require.NoError(t, pool.Submit(ctx, key, eventType, track(eventType)))
+		}
+	}
+	pool.Wait()
+
+	// the workers are bounded, and an event type can only occupy half of them
+	assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+	assert.LessOrEqual(t, maxTypeRunning.Load(), int32(2))
+	assert.Greater(t, maxRunning.Load(), int32(1))
+}
+
+func TestKeyedWorkPool_Backpressure(t *testing.T) {
+	pool := newKeyedWorkPool(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	for i := 0; i < pendingPerWorker; i++ {
+		require.NoError(t, pool.Submit(ctx, "key", "type", func() { <-release }))
+	}
+
+	// the pool is full, the submission is blocked until the context is done
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer timeoutCancel()
+	assert.ErrorIs(t, pool.Submit(timeoutCtx, "key", "type", func() {}), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, pool.Submit(ctx, "key", "type", func() {}))
+	cancel()
+	pool.Wait()
+}
+
+func TestWorkKeyOf(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetType(constants.HAConfigMsgKey)
+	assert.Equal(t, constants.HAConfigMsgKey, workKeyOf(&evt))
+
+	evt.SetType(constants.ResyncMsgKey)
+	evt.SetExtension(constants.CloudEventExtensionKeyResyncId, "123")
+	assert.Equal(t, constants.ResyncMsgKey+"/123", workKeyOf(&evt))
+
+	migration := cloudevents.NewEvent()
+	migration.SetType("migration")
+	migration.SetExtension(constants.CloudEventExtensionKeyMigrationId, "456")
+	migration.SetExtension(constants.CloudEventExtensionKeyMigrationStage, "Deploying")
+	assert.Equal(t, "migration/456/Deploying", workKeyOf(&migration))
+
+	remediation := cloudevents.NewEvent()
+	remediation.SetType(constants.PolicyRemediationMsgKey)
+	remediation.SetExtension(constants.CloudEventExtensionKeyRemediationId, "789")
+	assert.Equal(t, constants.PolicyRemediationMsgKey+"/789", workKeyOf(&remediation))
+
+	// the events without an id are serialized by the object they target
+	inactive := cloudevents.NewEvent()
+	inactive.SetType(constants.HubStatusUpdateMsgKey)
+	require.NoError(t, inactive.SetData(cloudevents.ApplicationJSON,
+		hubha.HubStatusUpdate{HubName: "hub1", Status: constants.HubStatusInactive}))
+	active := inactive.Clone()
+	require.NoError(t, active.SetData(cloudevents.ApplicationJSON,
+		hubha.HubStatusUpdate{HubName: "hub1", Status: constants.HubStatusActive}))
+	other := inactive.Clone()
+	require.NoError(t, other.SetData(cloudevents.ApplicationJSON,
+		hubha.HubStatusUpdate{HubName: "hub2", Status: constants.HubStatusInactive}))
+	assert.Equal(t, constants.HubStatusUpdateMsgKey+"/hub1", workKeyOf(&inactive))
+	assert.Equal(t, workKeyOf(&inactive), workKeyOf(&active))
+	assert.NotEqual(t, workKeyOf(&inactive), workKeyOf(&other))
+
+	secret := cloudevents.NewEvent()
+	secret.SetType("secret")
+	require.NoError(t, secret.SetData(cloudevents.ApplicationJSON, map[string]any{
+		"metadata": map[string]any{"namespace": "default", "name": "bootstrap"},
+	}))
+	assert.Equal(t, "secret/default/bootstrap", workKeyOf(&secret))
+
+	repair := cloudevents.NewEvent()
+	repair.SetType(constants.AntiEntropyRepairMsgKey)
+	require.NoError(t, repair.SetData(cloudevents.ApplicationJSON, map[string]any{"eventType": "managedcluster"}))
+	assert.Equal(t, constants.AntiEntropyRepairMsgKey+"/managedcluster", workKeyOf(&repair))
+
+	// a bundle doesn't target a single object
+	bundle := cloudevents.NewEvent()
+	bundle.SetType(constants.HubHAResourcesMsgKey)
+	require.NoError(t, bundle.SetData(cloudevents.ApplicationJSON, map[string]any{"update": []any{}}))
+	assert.Equal(t, constants.HubHAResourcesMsgKey, workKeyOf(&bundle))
+}
@@ -3,6 +3,8 @@ package emitters
 import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
 )
 
 // Emitter defines methods to manage bundle lifecycle, including object updates,
@@ -31,3 +33,20 @@ type Emitter interface {
 	// Returns an error if sending fails.
 	Send() error
 }
+
+// Digester is implemented by the emitters which support the anti-entropy: the manager compares the bucket hashes of
+// the objects with the rows in the database, and requests the mismatched buckets to be resent.
+type Digester interface {
+	// SendDigest sends the bucket hashes of the provided objects.
+	SendDigest(objects []client.Object) error
+
+	// Repair resends the provided objects in the mismatched buckets, and deletes the stale objects of the buckets.
+	Repair(objects []client.Object, repair *antientropy.RepairBundle) error
+}
+
+// Hybrid is implemented by the emitters which send the delta bundles between the complete snapshots, the complete
+// snapshot is sent once the deltas are accumulated enough, so the manager doesn't replay too many deltas.
+type Hybrid interface {
+	// SwitchToComplete returns whether the complete snapshot should be sent instead of more deltas.
+	SwitchToComplete() bool
+}
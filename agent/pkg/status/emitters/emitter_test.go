@@ -3,16 +3,20 @@ package emitters
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
 	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -211,3 +215,85 @@ func TestObjectEmitter_WithTweakFunction(t *testing.T) {
 	// assert the object in the bundle is tweaked
 	require.Equal(t, "tweaked-original", emitter.bundle.Update[0].GetName())
 }
+
+func TestObjectEmitter_DigestAndRepair(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "test-leaf-hub"})
+	producer := &MockProducer{}
+	emitter := NewObjectEmitter(enum.EventType("test-event"), producer)
+
+	objects := []client.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "obj1", UID: "uid1", ResourceVersion: "1"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "obj2", UID: "uid2", ResourceVersion: "2"}},
+	}
+
+	// the digest has the bucket hashes of the objects
+	require.NoError(t, emitter.SendDigest(objects))
+	require.Len(t, producer.events, 1)
+	require.Equal(t, string(enum.AntiEntropyDigestType), producer.events[0].Type())
+	digest := &antientropy.DigestBundle{}
+	require.NoError(t, json.Unmarshal(producer.events[0].Data(), digest))
+	require.Equal(t, "test-event", digest.EventType)
+	require.Equal(t, antientropy.BucketHashes([]antientropy.ObjectDigest{
+		{ID: "uid1", Version: "1"}, {ID: "uid2", Version: "2"},
+	}, digest.BucketCount), digest.Buckets)
+
+	// repair resends the objects of the buckets and deletes the stale ones
+	repair := &antientropy.RepairBundle{
+		EventType:   "test-event",
+		BucketCount: 1,
+		Buckets:     []int{0},
+		Objects: []genericbundle.ObjectMetadata{
+			{ID: "uid1", Name: "obj1"},
+			{ID: "uid3", Name: "obj3"},
+		},
+	}
+	require.NoError(t, emitter.Repair(objects, repair))
+	require.Len(t, producer.events, 2)
+	bundle := &genericbundle.GenericBundle[corev1.ConfigMap]{}
+	require.NoError(t, json.Unmarshal(producer.events[1].Data(), bundle))
+	require.Len(t, bundle.Update, 2)
+	require.Len(t, bundle.Delete, 1)
+	require.Equal(t, "uid3", bundle.Delete[0].ID)
+
+	require.Error(t, emitter.Repair(objects, &antientropy.RepairBundle{EventType: "test-event"}))
+}
+
+func TestObjectEmitter_SwitchToComplete(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "test-leaf-hub", StatusDeltaCountSwitchFactor: 2})
+	producer := &MockProducer{}
+	emitter := NewObjectEmitter(enum.EventType("test-event"), producer)
+
+	newConfigMap := func(name, resourceVersion string) client.Object {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "ns1", UID: types.UID(name), ResourceVersion: resourceVersion,
+		}}
+	}
+	objects := []client.Object{newConfigMap("obj1", "1"), newConfigMap("obj2", "1")}
+
+	// the complete snapshot doesn't depend on the previous bundles
+	require.NoError(t, emitter.Resync(objects))
+	require.Len(t, producer.events, 2)
+	require.NotContains(t, producer.events[1].Extensions(), eventversion.ExtDependencyVersion)
+	require.False(t, emitter.SwitchToComplete())
+
+	// each delta bundle depends on the previous bundle
+	for i := 2; i <= 6; i++ {
+		require.NoError(t, emitter.Update(newConfigMap("obj1", strconv.Itoa(i))))
+		require.NoError(t, emitter.Send())
+		sent := producer.events[len(producer.events)-1]
+		previous := producer.events[len(producer.events)-2]
+		require.Equal(t, previous.Extensions()[eventversion.ExtVersion],
+			sent.Extensions()[eventversion.ExtDependencyVersion])
+		// switch once the deltas exceed the factor(2) times the object count(2)
+		require.Equal(t, i == 6, emitter.SwitchToComplete())
+	}
+	require.False(t, emitter.SwitchToComplete())
+
+	// the switch is disabled by the factor 0
+	emitter = NewObjectEmitter(enum.EventType("test-event"), producer, WithDeltaCountSwitchFactor(0))
+	for i := 2; i <= 5; i++ {
+		require.NoError(t, emitter.Update(newConfigMap("obj1", strconv.Itoa(i))))
+		require.NoError(t, emitter.Send())
+	}
+	require.False(t, emitter.SwitchToComplete())
+}
@@ -9,6 +9,8 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -17,6 +19,7 @@ import (
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -27,6 +30,9 @@ type EventEmitter struct {
 	producer      transport.Producer
 	runtimeClient client.Client
 	filter        func(client.Object) bool // filter events by the predicate
+	// rules drops the events by the filtering and sampling rules, unlike the filter, it's applied once for each event
+	// since the rate limits are stateful, and the rate limits aren't applied to the resynced events
+	rules func(obj client.Object, resync bool) bool
 	// transform converts a object to an event object. It can return nil to indicate that the object should be skipped.
 	// It can return a list of events(like replicated policy events) or a single event.
 	transform func(client.Client, client.Object) interface{}
@@ -54,6 +60,7 @@ func NewEventEmitter(
 		producer:      producer,
 		runtimeClient: runtimeClient,
 		filter:        filter,
+		rules:         func(client.Object, bool) bool { return true },
 		transform:     transform,
 		postSend:      nil, // Will be set by options if needed
 		events:        make([]interface{}, 0),
@@ -79,7 +86,7 @@ func (e *EventEmitter) Update(obj client.Object) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if !e.filter(obj) {
+	if !e.filter(obj) || !e.rules(obj, false) {
 		return nil
 	}
 
@@ -168,7 +175,7 @@ func (e *EventEmitter) Resync(objects []client.Object) error {
 	defer e.mu.Unlock()
 
 	for _, obj := range objects {
-		if !e.filter(obj) {
+		if !e.filter(obj) || !e.rules(obj, true) {
 			continue
 		}
 		event := e.transform(e.runtimeClient, obj)
@@ -256,8 +263,11 @@ func (e *EventEmitter) sendEventBundle() error {
 		return fmt.Errorf("failed to set event data for bundle: %w", err)
 	}
 
-	ctx := e.createContext()
-	if err := e.producer.SendEvent(ctx, evt); err != nil {
+	ctx, span := tracing.StartEventSpan(e.createContext(), &evt, "emit", trace.SpanKindInternal,
+		attribute.Int("bundle.events", len(e.events)))
+	err := e.producer.SendEvent(ctx, evt)
+	tracing.EndSpan(span, err)
+	if err != nil {
 		log.Errorw("failed to send event bundle", "error", err)
 		return fmt.Errorf("failed to send event bundle: %w", err)
 	}
@@ -281,7 +291,10 @@ func (e *EventEmitter) sendEventsIndividually() error {
 			return fmt.Errorf("failed to set event data for individual event: %w", err)
 		}
 
-		if err := e.producer.SendEvent(ctx, evt); err != nil {
+		evtCtx, span := tracing.StartEventSpan(ctx, &evt, "emit", trace.SpanKindInternal)
+		err := e.producer.SendEvent(evtCtx, evt)
+		tracing.EndSpan(span, err)
+		if err != nil {
 			log.Errorw("failed to send individual event", "error", err, "sent", sentCount, "total", len(e.events))
 			return fmt.Errorf("failed to send individual event (sent %d/%d): %w",
 				sentCount, len(e.events), err)
@@ -324,6 +337,14 @@ func WithPostSend(postSend func([]interface{}) error) EventEmitterOption {
 	}
 }
 
+// WithRules drops the events by the filtering and sampling rules before they're transformed, the resync is true for
+// the events of the Resync
+func WithRules(rules func(obj client.Object, resync bool) bool) EventEmitterOption {
+	return func(e *EventEmitter) {
+		e.rules = rules
+	}
+}
+
 func toSlice(v interface{}) []interface{} {
 	if v == nil {
 		return nil
@@ -424,3 +424,58 @@ func TestEventEmitter_SingleEventExceedsLimit(t *testing.T) {
 
 	t.Logf("Correctly rejected single event exceeding limit: %v", err)
 }
+
+func TestEventEmitter_WithRules(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{
+		LeafHubName: "test-hub",
+		EventMode:   string(constants.EventSendModeBatch),
+	})
+	producer := &MockEventProducer{}
+	checked, resynced := 0, 0
+	emitter := NewEventEmitter(
+		enum.LocalRootPolicyEventType,
+		producer,
+		nil,
+		func(obj client.Object) bool { return true },
+		func(runtimeClient client.Client, obj client.Object) interface{} {
+			return event.RootPolicyEvent{BaseEvent: event.BaseEvent{EventName: obj.GetName()}}
+		},
+		// the rules are applied once for each event, and drop the normal events
+		WithRules(func(obj client.Object, resync bool) bool {
+			checked++
+			if resync {
+				resynced++
+			}
+			return obj.(*corev1.Event).Type != corev1.EventTypeNormal
+		}),
+	)
+
+	events := []client.Object{
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "normal"}, Type: corev1.EventTypeNormal},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "warning"}, Type: corev1.EventTypeWarning},
+	}
+	for _, evt := range events {
+		if err := emitter.Update(evt); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+	if len(emitter.events) != 1 {
+		t.Fatalf("Expected 1 event after the rules, got %d", len(emitter.events))
+	}
+	if err := emitter.Resync(events); err != nil {
+		t.Fatalf("Resync failed: %v", err)
+	}
+	if checked != 4 {
+		t.Fatalf("Expected the rules to be applied 4 times, got %d", checked)
+	}
+	if resynced != 2 {
+		t.Fatalf("Expected the rules to be applied to 2 resynced events, got %d", resynced)
+	}
+	var sent []event.RootPolicyEvent
+	if err := producer.events[0].DataAs(&sent); err != nil {
+		t.Fatalf("Failed to decode the sent events: %v", err)
+	}
+	if len(sent) != 2 || sent[0].EventName != "warning" || sent[1].EventName != "warning" {
+		t.Fatalf("Expected only the warning events to be sent, got %v", sent)
+	}
+}
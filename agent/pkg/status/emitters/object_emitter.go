@@ -8,6 +8,8 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -17,6 +19,7 @@ import (
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -43,6 +46,17 @@ type ObjectEmitter struct {
 	version      *eventversion.Version
 	mu           sync.Mutex
 	keyFunc      func(client.Object) string
+	// digestVersionFunc returns the version of the object for the anti-entropy, it must be the same as the version
+	// persisted in the database, the default is the resource version
+	digestVersionFunc func(client.Object) string
+	// the deltas are sent until their accumulated count exceeds the switch factor times the object count of the last
+	// complete snapshot, then the complete snapshot is sent instead. 0 disables the switch
+	deltaCountSwitchFactor int
+	deltaCount             int
+	objectCount            int
+	// lastSentVersion is the version of the last sent bundle, the next delta bundle depends on it, so the manager can
+	// detect the gaps in the delta chain
+	lastSentVersion string
 }
 
 // NewObjectEmitter creates a new ObjectEmitter with the provided event type and producer.
@@ -65,6 +79,12 @@ func NewObjectEmitter(
 		shouldDelete: func(obj client.Object) bool {
 			return false
 		},
+		digestVersionFunc: func(obj client.Object) string {
+			return obj.GetResourceVersion()
+		},
+	}
+	if agentConfig := configs.GetAgentConfig(); agentConfig != nil {
+		e.deltaCountSwitchFactor = agentConfig.StatusDeltaCountSwitchFactor
 	}
 	// apply the options
 	for _, fn := range opts {
@@ -173,8 +193,11 @@ func (e *ObjectEmitter) remove(obj client.Object) error {
 	if e.metadataFunc != nil {
 		metadata = e.metadataFunc(tweaked)
 	}
+	return e.addDelete(*metadata)
+}
 
-	added, err := e.bundle.AddDelete(*metadata)
+func (e *ObjectEmitter) addDelete(metadata genericbundle.ObjectMetadata) error {
+	added, err := e.bundle.AddDelete(metadata)
 	if err != nil {
 		return fmt.Errorf("failed to add delete event to bundle: %v", err)
 	}
@@ -184,12 +207,13 @@ func (e *ObjectEmitter) remove(obj client.Object) error {
 			return err
 		}
 		// re-add
-		added, err = e.bundle.AddDelete(*metadata)
+		added, err = e.bundle.AddDelete(metadata)
 		if err != nil {
 			return fmt.Errorf("failed to re-add delete event to bundle: %v", err)
 		}
 		if !added {
-			return fmt.Errorf("failed to re-add delete event to bundle for obj: %v ", tweaked)
+			return fmt.Errorf("failed to re-add delete event to bundle for obj: %s/%s ", metadata.Namespace,
+				metadata.Name)
 		}
 	}
 	e.version.Incr()
@@ -262,7 +286,28 @@ func (e *ObjectEmitter) Resync(objects []client.Object) error {
 	if err := e.bundle.AddResyncMetadata(metadataList); err != nil {
 		return err
 	}
-	return e.sendBundle()
+	if err := e.sendBundle(); err != nil {
+		return err
+	}
+	// the complete snapshot is sent, restart counting the deltas
+	e.objectCount = len(metadataList)
+	e.deltaCount = 0
+	return nil
+}
+
+// SwitchToComplete returns true if the deltas sent since the last complete snapshot exceed the switch factor times
+// the object count, the count is restarted so the complete snapshot is only requested once.
+func (e *ObjectEmitter) SwitchToComplete() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.deltaCountSwitchFactor <= 0 || e.deltaCount <= e.deltaCountSwitchFactor*max(e.objectCount, 1) {
+		return false
+	}
+	log.Infow("switching to the complete snapshot", "type", enum.ShortenEventType(string(e.eventType)),
+		"deltas", e.deltaCount, "objects", e.objectCount)
+	e.deltaCount = 0
+	return true
 }
 
 // Send triggers the emission of an event.
@@ -314,6 +359,11 @@ func (e *ObjectEmitter) sendBundle() error {
 	evt.SetSource(configs.GetLeafHubName())
 	evt.SetType(string(e.eventType))
 	evt.SetExtension(eventversion.ExtVersion, e.version.String())
+	// the resync bundles are the complete snapshot, they don't depend on the previous bundles
+	complete := len(e.bundle.Resync) > 0 || len(e.bundle.ResyncMetadata) > 0
+	if !complete && e.lastSentVersion != "" {
+		evt.SetExtension(eventversion.ExtDependencyVersion, e.lastSentVersion)
+	}
 
 	payload, err := json.Marshal(e.bundle)
 	if err != nil {
@@ -330,7 +380,12 @@ func (e *ObjectEmitter) sendBundle() error {
 	if e.topic != "" {
 		ctx = cecontext.WithTopic(ctx, e.topic)
 	}
-	if err = e.producer.SendEvent(ctx, evt); err != nil {
+	ctx, span := tracing.StartEventSpan(ctx, &evt, "emit", trace.SpanKindInternal,
+		attribute.String("bundle.version", e.version.String()),
+		attribute.Bool("bundle.complete", complete))
+	err = e.producer.SendEvent(ctx, evt)
+	tracing.EndSpan(span, err)
+	if err != nil {
 		return fmt.Errorf("failed to send event: %v", err)
 	}
 	log.Debugw("sending",
@@ -340,6 +395,10 @@ func (e *ObjectEmitter) sendBundle() error {
 		"delete", len(e.bundle.Delete),
 		"resync", len(e.bundle.Resync),
 		"resync_metadata", len(e.bundle.ResyncMetadata))
+	e.lastSentVersion = e.version.String()
+	if !complete {
+		e.deltaCount += len(e.bundle.Create) + len(e.bundle.Update) + len(e.bundle.Delete)
+	}
 	e.bundle.Clean()
 	return nil
 }
@@ -378,6 +437,22 @@ func WithShouldDeleteFunc(shouldDeleteFunc func(client.Object) bool) EmitterOpti
 	}
 }
 
+// WithDigestVersionFunc sets the function to get the version of the object for the anti-entropy, e.g. the generation
+// if the object is only sent when the generation is changed
+func WithDigestVersionFunc(digestVersionFunc func(client.Object) string) EmitterOption {
+	return func(e *ObjectEmitter) {
+		e.digestVersionFunc = digestVersionFunc
+	}
+}
+
+// WithDeltaCountSwitchFactor overrides the --status-delta-count-switch-factor of the agent, 0 disables the switch to
+// the complete snapshot
+func WithDeltaCountSwitchFactor(factor int) EmitterOption {
+	return func(e *ObjectEmitter) {
+		e.deltaCountSwitchFactor = factor
+	}
+}
+
 func WithTopic(topic string) EmitterOption {
 	return func(e *ObjectEmitter) {
 		e.topic = topic
@@ -0,0 +1,151 @@
+package emitters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
+)
+
+var (
+	// the digests of all the event types are sent with the same event type, so they share the version
+	digestVersion   = eventversion.NewVersion()
+	digestVersionMu sync.Mutex
+)
+
+// SendDigest sends the bucket hashes of the objects, the manager compares them with the rows in the database
+func (e *ObjectEmitter) SendDigest(objects []client.Object) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	digests, _, err := e.digests(objects)
+	if err != nil {
+		return err
+	}
+	bundle := &antientropy.DigestBundle{
+		EventType:   string(e.eventType),
+		BucketCount: antientropy.DefaultBucketCount,
+		Buckets:     antientropy.BucketHashes(digests, antientropy.DefaultBucketCount),
+	}
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest bundle: %v", err)
+	}
+
+	digestVersionMu.Lock()
+	defer digestVersionMu.Unlock()
+	digestVersion.Incr()
+	evt := cloudevents.NewEvent()
+	evt.SetSource(configs.GetLeafHubName())
+	evt.SetType(string(enum.AntiEntropyDigestType))
+	evt.SetExtension(eventversion.ExtVersion, digestVersion.String())
+	if err = evt.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return fmt.Errorf("failed to load digest bundle into cloudevent: %v", err)
+	}
+
+	ctx := context.Background()
+	if e.topic != "" {
+		ctx = cecontext.WithTopic(ctx, e.topic)
+	}
+	ctx, span := tracing.StartEventSpan(ctx, &evt, "emit", trace.SpanKindInternal,
+		attribute.String("bundle.version", digestVersion.String()))
+	err = e.producer.SendEvent(ctx, evt)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to send digest event: %v", err)
+	}
+	digestVersion.Next()
+	log.Debugw("sent digest", "type", enum.ShortenEventType(string(e.eventType)), "objects", len(digests))
+	return nil
+}
+
+// Repair resends the objects in the mismatched buckets, and deletes the objects of the buckets which are in the
+// database but don't exist in the managed hub
+func (e *ObjectEmitter) Repair(objects []client.Object, repair *antientropy.RepairBundle) error {
+	if repair.BucketCount <= 0 {
+		return fmt.Errorf("invalid bucket count %d of the repair request", repair.BucketCount)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	buckets := map[int]bool{}
+	for _, bucket := range repair.Buckets {
+		buckets[bucket] = true
+	}
+
+	digests, existing, err := e.digests(objects)
+	if err != nil {
+		return err
+	}
+	resent := 0
+	for _, digest := range digests {
+		if !buckets[antientropy.BucketOf(digest.ID, repair.BucketCount)] {
+			continue
+		}
+		if err := e.handleDeltaEvent(existing[digest.ID], e.bundle.AddUpdate); err != nil {
+			return err
+		}
+		resent++
+	}
+
+	deleted := 0
+	for _, metadata := range repair.Objects {
+		if _, ok := existing[metadata.ID]; ok || !buckets[antientropy.BucketOf(metadata.ID, repair.BucketCount)] {
+			continue
+		}
+		if err := e.addDelete(metadata); err != nil {
+			return err
+		}
+		deleted++
+	}
+
+	if err := e.sendBundle(); err != nil {
+		return err
+	}
+	e.version.Next()
+	log.Infow("repaired the mismatched buckets", "type", enum.ShortenEventType(string(e.eventType)),
+		"buckets", len(repair.Buckets), "resent", resent, "deleted", deleted)
+	return nil
+}
+
+// digests returns the digests of the target objects and the objects by their ids, the id is the same as the id of
+// the resync metadata, which is the id of the row in the database
+func (e *ObjectEmitter) digests(objects []client.Object) ([]antientropy.ObjectDigest,
+	map[string]client.Object, error,
+) {
+	digests := make([]antientropy.ObjectDigest, 0, len(objects))
+	existing := make(map[string]client.Object, len(objects))
+	for _, obj := range objects {
+		if !e.target(obj) || e.shouldDelete(obj) {
+			continue
+		}
+		tweaked, err := applyTweak(obj, e.tweakFunc)
+		if err != nil {
+			return nil, nil, err
+		}
+		metadata := &genericbundle.ObjectMetadata{ID: string(tweaked.GetUID())}
+		if e.metadataFunc != nil {
+			metadata = e.metadataFunc(tweaked)
+		}
+		if metadata.ID == "" {
+			continue
+		}
+		digests = append(digests, antientropy.ObjectDigest{ID: metadata.ID, Version: e.digestVersionFunc(tweaked)})
+		existing[metadata.ID] = obj
+	}
+	return digests, existing, nil
+}
@@ -0,0 +1,296 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// EventRulesKey is the key of the event filtering and sampling rules in the agent configmap
+const EventRulesKey = "eventFilters"
+
+// the rules which drop the events, they're the label values of the DroppedEventsCounter
+const (
+	RuleInclude   = "include"
+	RuleExclude   = "exclude"
+	RuleSampling  = "sampling"
+	RuleRateLimit = "ratelimit"
+)
+
+const (
+	defaultRateLimitInterval = time.Minute
+	// the expired rate limit windows are pruned once the number of the keys exceeds it
+	maxRateLimitKeys = 1024
+)
+
+var (
+	DroppedEventsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_dropped_events_total",
+		Help: "The number of the events which are dropped by the event filtering and sampling rules.",
+	}, []string{"type", "rule"})
+
+	eventRules   *EventRules
+	eventRulesMu sync.Mutex
+	timeNow      = time.Now
+)
+
+// EventRules are the declarative rules to filter and sample the events sent by the agent, e.g.
+//
+//	include:
+//	- types: [Warning]
+//	exclude:
+//	- namespaces: [open-cluster-management-agent]
+//	  reasons: [ClusterImported]
+//	rateLimits:
+//	- kinds: [Policy]
+//	  keyBy: [namespace, name]
+//	  limit: 10
+//	  interval: 1m
+//	sampling:
+//	- reasons: [BackOff]
+//	  rate: 10
+type EventRules struct {
+	// Include sends the events matching any of the matchers, all the events are included if it's empty
+	Include []EventMatcher `json:"include,omitempty"`
+	// Exclude drops the events matching any of the matchers
+	Exclude []EventMatcher `json:"exclude,omitempty"`
+	// RateLimits limit the number of the events per key within the interval, the first matched one is applied
+	RateLimits []EventRateLimit `json:"rateLimits,omitempty"`
+	// Sampling keeps a part of the repetitive events, the first matched one is applied
+	Sampling []EventSampling `json:"sampling,omitempty"`
+}
+
+// EventMatcher matches the event if each of the non-empty fields contains the value of the event
+type EventMatcher struct {
+	Reasons []string `json:"reasons,omitempty"`
+	// Types are the event types, Normal or Warning
+	Types []string `json:"types,omitempty"`
+	// Namespaces are the namespaces of the events
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Kinds are the kinds of the involved objects
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// EventRateLimit sends at most Limit events of each key within the Interval
+type EventRateLimit struct {
+	EventMatcher `json:",inline"`
+	// KeyBy are the fields to group the events: reason, type, namespace, kind and name, the kind and name are of the
+	// involved object. The events are grouped by all the fields by default.
+	KeyBy    []string        `json:"keyBy,omitempty"`
+	Limit    int             `json:"limit"`
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// EventSampling keeps 1 of every Rate occurrences of the repetitive event, the occurrence is the count of the event
+type EventSampling struct {
+	EventMatcher `json:",inline"`
+	Rate         int `json:"rate"`
+}
+
+var eventKeyFields = []string{"reason", "type", "namespace", "kind", "name"}
+
+type rateLimitWindow struct {
+	start time.Time
+	count int
+}
+
+// eventRulesFilter applies the event rules to the events of an emitter, the rate limit windows are kept for each
+// emitter, and they're reset once the rules are changed
+type eventRulesFilter struct {
+	shortType string
+	mu        sync.Mutex
+	rules     *EventRules
+	windows   map[string]*rateLimitWindow
+}
+
+// SetEventRules parses the rules in the agent configmap, the empty rules send all the events. The previous rules are
+// kept if the rules are invalid.
+func SetEventRules(data string) error {
+	rules := EventRules{}
+	if err := yaml.Unmarshal([]byte(data), &rules); err != nil {
+		return fmt.Errorf("failed to parse the event rules: %w", err)
+	}
+	for i := range rules.RateLimits {
+		rateLimit := &rules.RateLimits[i]
+		if rateLimit.Limit <= 0 {
+			return fmt.Errorf("the limit of the rate limit %d must be positive", i)
+		}
+		if rateLimit.Interval.Duration <= 0 {
+			rateLimit.Interval.Duration = defaultRateLimitInterval
+		}
+		for _, field := range rateLimit.KeyBy {
+			if !slices.Contains(eventKeyFields, field) {
+				return fmt.Errorf("the key %s of the rate limit %d isn't one of %v", field, i, eventKeyFields)
+			}
+		}
+		if len(rateLimit.KeyBy) == 0 {
+			rateLimit.KeyBy = eventKeyFields
+		}
+	}
+	for i, sampling := range rules.Sampling {
+		if sampling.Rate <= 0 {
+			return fmt.Errorf("the rate of the sampling %d must be positive", i)
+		}
+	}
+
+	eventRulesMu.Lock()
+	defer eventRulesMu.Unlock()
+	if len(rules.Include) == 0 && len(rules.Exclude) == 0 && len(rules.RateLimits) == 0 && len(rules.Sampling) == 0 {
+		eventRules = nil
+		return nil
+	}
+	eventRules = &rules
+	return nil
+}
+
+// currentEventRules returns the rules set by the agent configmap, the rules aren't changed once they're set
+func currentEventRules() *EventRules {
+	eventRulesMu.Lock()
+	defer eventRulesMu.Unlock()
+	return eventRules
+}
+
+// EventRulesFor returns the function to apply the event rules to the events of an emitter, it must be called for each
+// emitter since the rate limits are counted by each of them. The rate limits are stateful, so the function must be
+// called once for each live event, and they aren't applied to the resynced events which are sent again.
+func EventRulesFor(eventType enum.EventType) func(obj client.Object, resync bool) bool {
+	f := &eventRulesFilter{shortType: enum.ShortenEventType(string(eventType))}
+	return func(obj client.Object, resync bool) bool {
+		evt, ok := obj.(*corev1.Event)
+		if !ok {
+			return true
+		}
+		rule := f.apply(evt, resync)
+		if rule == "" {
+			return true
+		}
+		DroppedEventsCounter.WithLabelValues(f.shortType, rule).Inc()
+		log.Debugw("event dropped by the rule", "type", f.shortType, "rule", rule,
+			"event", evt.Namespace+"/"+evt.Name)
+		return false
+	}
+}
+
+// apply returns the rule which drops the event, or empty if the event is sent
+func (f *eventRulesFilter) apply(evt *corev1.Event, resync bool) string {
+	rules := currentEventRules()
+	if rules == nil {
+		return ""
+	}
+
+	if len(rules.Include) > 0 && !slices.ContainsFunc(rules.Include, func(m EventMatcher) bool {
+		return m.matches(evt)
+	}) {
+		return RuleInclude
+	}
+	if slices.ContainsFunc(rules.Exclude, func(m EventMatcher) bool { return m.matches(evt) }) {
+		return RuleExclude
+	}
+	// the sampled events don't consume the rate limits
+	for _, sampling := range rules.Sampling {
+		if !sampling.matches(evt) {
+			continue
+		}
+		if count := eventCount(evt); count > 1 && count%sampling.Rate != 0 {
+			return RuleSampling
+		}
+		break
+	}
+	if resync {
+		return ""
+	}
+	for i, rateLimit := range rules.RateLimits {
+		if !rateLimit.matches(evt) {
+			continue
+		}
+		if !f.allow(rules, fmt.Sprintf("%d/%s", i, eventKey(evt, rateLimit.KeyBy)), rateLimit) {
+			return RuleRateLimit
+		}
+		break
+	}
+	return ""
+}
+
+// allow counts the event in the fixed window of the key, and returns whether the limit isn't exceeded
+func (f *eventRulesFilter) allow(rules *EventRules, key string, rateLimit EventRateLimit) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rules != rules {
+		f.rules = rules
+		f.windows = map[string]*rateLimitWindow{}
+	}
+
+	now := timeNow()
+	window, ok := f.windows[key]
+	if !ok || now.Sub(window.start) >= rateLimit.Interval.Duration {
+		if !ok && len(f.windows) >= maxRateLimitKeys {
+			f.prune(now)
+		}
+		window = &rateLimitWindow{start: now}
+		f.windows[key] = window
+	}
+	window.count++
+	return window.count <= rateLimit.Limit
+}
+
+// prune removes the windows which are older than the longest interval
+func (f *eventRulesFilter) prune(now time.Time) {
+	longest := time.Duration(0)
+	for _, rateLimit := range f.rules.RateLimits {
+		longest = max(longest, rateLimit.Interval.Duration)
+	}
+	for key, window := range f.windows {
+		if now.Sub(window.start) >= longest {
+			delete(f.windows, key)
+		}
+	}
+}
+
+func (m EventMatcher) matches(evt *corev1.Event) bool {
+	return matchValue(m.Reasons, evt.Reason) &&
+		matchValue(m.Types, evt.Type) &&
+		matchValue(m.Namespaces, evt.Namespace) &&
+		matchValue(m.Kinds, evt.InvolvedObject.Kind)
+}
+
+func matchValue(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+func eventKey(evt *corev1.Event, keyBy []string) string {
+	values := make([]string, 0, len(keyBy))
+	for _, field := range keyBy {
+		switch field {
+		case "reason":
+			values = append(values, evt.Reason)
+		case "type":
+			values = append(values, evt.Type)
+		case "namespace":
+			values = append(values, evt.Namespace)
+		case "kind":
+			values = append(values, evt.InvolvedObject.Kind)
+		case "name":
+			values = append(values, evt.InvolvedObject.Name)
+		}
+	}
+	return strings.Join(values, "/")
+}
+
+// eventCount returns the occurrences of the event, the event series is used by the events.k8s.io API
+func eventCount(evt *corev1.Event) int {
+	count := int(evt.Count)
+	if evt.Series != nil && int(evt.Series.Count) > count {
+		count = int(evt.Series.Count)
+	}
+	return max(count, 1)
+}
@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(namespace, reason, eventType, kind, name string, count int32) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: name + "." + reason},
+		Reason:         reason,
+		Type:           eventType,
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+		Count:          count,
+	}
+}
+
+func TestEventRules(t *testing.T) {
+	now := time.Date(2024, 7, 6, 15, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() {
+		timeNow = time.Now
+		require.NoError(t, SetEventRules(""))
+	}()
+
+	require.NoError(t, SetEventRules(`
+include:
+- types: [Warning]
+- kinds: [ManagedCluster]
+exclude:
+- namespaces: [kube-system]
+rateLimits:
+- kinds: [Policy]
+  keyBy: [name]
+  limit: 2
+  interval: 1m
+sampling:
+- reasons: [BackOff]
+  rate: 5
+`))
+	rules := EventRulesFor(enum.LocalRootPolicyEventType)
+	allow := func(obj client.Object) bool { return rules(obj, false) }
+	shortType := enum.ShortenEventType(string(enum.LocalRootPolicyEventType))
+	dropped := func(rule string) float64 {
+		m := &dto.Metric{}
+		require.NoError(t, DroppedEventsCounter.WithLabelValues(shortType, rule).Write(m))
+		return m.Counter.GetValue()
+	}
+
+	// include and exclude
+	assert.True(t, allow(newEvent("default", "Imported", "Normal", "ManagedCluster", "cluster1", 1)))
+	assert.False(t, allow(newEvent("default", "Created", "Normal", "Job", "job1", 1)))
+	assert.Equal(t, 1.0, dropped(RuleInclude))
+	assert.False(t, allow(newEvent("kube-system", "Failed", "Warning", "Pod", "pod1", 1)))
+	assert.Equal(t, 1.0, dropped(RuleExclude))
+
+	// sampling keeps the first occurrence and 1 of every 5 occurrences
+	for count, sent := range map[int32]bool{1: true, 2: false, 4: false, 5: true, 10: true} {
+		assert.Equal(t, sent, allow(newEvent("default", "BackOff", "Warning", "Pod", "pod1", count)), count)
+	}
+	assert.Equal(t, 2.0, dropped(RuleSampling))
+
+	// rate limit by the name of the involved object
+	assert.True(t, allow(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy1", 1)))
+	assert.True(t, allow(newEvent("default", "PolicyUpdated", "Warning", "Policy", "policy1", 1)))
+	assert.False(t, allow(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy1", 1)))
+	assert.True(t, allow(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy2", 1)))
+	assert.Equal(t, 1.0, dropped(RuleRateLimit))
+
+	// the resynced events don't consume the rate limits, and the other emitters have their own rate limits
+	assert.True(t, rules(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy1", 1), true))
+	assert.False(t, rules(newEvent("default", "Created", "Normal", "Job", "job1", 1), true))
+	other := EventRulesFor(enum.LocalRootPolicyEventType)
+	assert.True(t, other(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy1", 1), false))
+	assert.Equal(t, 1.0, dropped(RuleRateLimit))
+	now = now.Add(time.Minute)
+	assert.True(t, allow(newEvent("default", "PolicyStatusSync", "Warning", "Policy", "policy1", 1)))
+
+	// the invalid rules are rejected, and the previous rules are kept
+	require.Error(t, SetEventRules("rateLimits:\n- limit: 0"))
+	require.Error(t, SetEventRules("rateLimits:\n- limit: 1\n  keyBy: [uid]"))
+	require.Error(t, SetEventRules("sampling:\n- rate: 0"))
+	require.Error(t, SetEventRules("include: Warning"))
+	assert.False(t, allow(newEvent("default", "Created", "Normal", "Job", "job1", 1)))
+
+	// all the events are sent without the rules
+	require.NoError(t, SetEventRules(""))
+	assert.True(t, allow(newEvent("default", "Created", "Normal", "Job", "job1", 1)))
+	assert.True(t, allow(&corev1.ConfigMap{}))
+}
+
+func TestEventRateLimitPrune(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() {
+		timeNow = time.Now
+		require.NoError(t, SetEventRules(""))
+	}()
+
+	require.NoError(t, SetEventRules("rateLimits:\n- limit: 1\n  keyBy: [name]\n  interval: 10s"))
+	f := &eventRulesFilter{shortType: enum.ShortenEventType(string(enum.ManagedClusterEventType))}
+	for i := 0; i < maxRateLimitKeys; i++ {
+		assert.Empty(t, f.apply(newEvent("default", "Created", "Normal", "Job", time.Duration(i).String(), 1), false))
+	}
+	assert.Len(t, f.windows, maxRateLimitKeys)
+
+	// the expired windows are pruned once the keys reach the maximum
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, f.apply(newEvent("default", "Created", "Normal", "Job", "new", 1), false))
+	assert.Len(t, f.windows, 1)
+
+	// the windows are reset once the rules are changed
+	require.NoError(t, SetEventRules("rateLimits:\n- limit: 1\n  keyBy: [name]\n  interval: 10s"))
+	assert.Empty(t, f.apply(newEvent("default", "Created", "Normal", "Job", "new", 1), false))
+}
@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// ResourceEventsKey is the key of the resource event sources in the agent configmap, the events of the involved objects
+// declared by the sources are forwarded to the event.resource_events table
+const ResourceEventsKey = "resourceEvents"
+
+var (
+	resourceEventSources   []ResourceEventSource
+	resourceEventSourcesMu sync.RWMutex
+)
+
+// ResourceEventSource declares the involved objects whose events are forwarded, e.g. in the agent configmap
+//
+//	resourceEvents: |
+//	  - group: hive.openshift.io
+//	    kind: ClusterDeployment
+//	  - group: metal3.io
+//	    kind: BareMetalHost
+//	    namespaces: [openshift-machine-api]
+//	  - kind: ManagedClusterAddOn
+type ResourceEventSource struct {
+	// Group is the API group of the involved objects, the objects of the kind in any group are matched if it's empty
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the involved objects
+	Kind string `json:"kind"`
+	// Namespaces are the namespaces of the events, the events in all the namespaces are matched if it's empty
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// SetResourceEventSources parses the resource event sources in the agent configmap, the empty sources forward none of
+// the events. The previous sources are kept if the sources are invalid.
+func SetResourceEventSources(data string) error {
+	sources := []ResourceEventSource{}
+	if err := yaml.Unmarshal([]byte(data), &sources); err != nil {
+		return fmt.Errorf("failed to parse the resource event sources: %w", err)
+	}
+	for i, source := range sources {
+		if source.Kind == "" {
+			return fmt.Errorf("the kind of the resource event source %d must be specified", i)
+		}
+	}
+
+	resourceEventSourcesMu.Lock()
+	defer resourceEventSourcesMu.Unlock()
+	resourceEventSources = sources
+	return nil
+}
+
+// MatchResourceEvent returns whether the involved object of the event is declared by any of the resource event sources
+func MatchResourceEvent(evt *corev1.Event) bool {
+	resourceEventSourcesMu.RLock()
+	defer resourceEventSourcesMu.RUnlock()
+	if len(resourceEventSources) == 0 {
+		return false
+	}
+
+	group := ""
+	if gv, err := schema.ParseGroupVersion(evt.InvolvedObject.APIVersion); err == nil {
+		group = gv.Group
+	}
+	return slices.ContainsFunc(resourceEventSources, func(source ResourceEventSource) bool {
+		return source.Kind == evt.InvolvedObject.Kind &&
+			(source.Group == "" || source.Group == group) &&
+			matchValue(source.Namespaces, evt.Namespace)
+	})
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newResourceEvent(namespace, apiVersion, kind string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: "event1"},
+		InvolvedObject: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Name: "object1"},
+	}
+}
+
+func TestResourceEventSources(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetResourceEventSources(""))
+	}()
+
+	// none of the events are matched without the sources
+	assert.False(t, MatchResourceEvent(newResourceEvent("cluster1", "hive.openshift.io/v1", "ClusterDeployment")))
+
+	require.NoError(t, SetResourceEventSources(`
+- group: hive.openshift.io
+  kind: ClusterDeployment
+- group: metal3.io
+  kind: BareMetalHost
+  namespaces: [openshift-machine-api]
+- kind: ManagedClusterAddOn
+`))
+	assert.True(t, MatchResourceEvent(newResourceEvent("cluster1", "hive.openshift.io/v1", "ClusterDeployment")))
+	assert.False(t, MatchResourceEvent(newResourceEvent("cluster1", "other.io/v1", "ClusterDeployment")))
+	assert.True(t, MatchResourceEvent(newResourceEvent("openshift-machine-api", "metal3.io/v1alpha1", "BareMetalHost")))
+	assert.False(t, MatchResourceEvent(newResourceEvent("default", "metal3.io/v1alpha1", "BareMetalHost")))
+	// the kind in any group is matched without the group
+	assert.True(t, MatchResourceEvent(newResourceEvent("cluster1", "addon.open-cluster-management.io/v1alpha1",
+		"ManagedClusterAddOn")))
+	assert.False(t, MatchResourceEvent(newResourceEvent("default", "v1", "Pod")))
+
+	// the invalid sources are rejected, and the previous sources are kept
+	require.Error(t, SetResourceEventSources("- group: metal3.io"))
+	require.Error(t, SetResourceEventSources("kind: BareMetalHost"))
+	assert.True(t, MatchResourceEvent(newResourceEvent("cluster1", "hive.openshift.io/v1", "ClusterDeployment")))
+}
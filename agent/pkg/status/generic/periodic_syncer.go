@@ -3,6 +3,7 @@ package generic
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -11,6 +12,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 )
 
@@ -22,15 +24,17 @@ type EmitterRegistration struct {
 }
 
 // syncStates holds the next sync time for each bundle and is only updated by the current goroutine.
-// Items are added to the slice before the goroutine starts.
-// After the goroutine begins, it only reads from the slice, so there is no risk of race conditions.
+// Items might be registered after the goroutine starts, e.g., the syncer of an optional CRD, so the slice is
+// guarded by the mutex, while the states in it are only updated by the goroutine.
 type SyncState struct {
 	Registration *EmitterRegistration
 	NextResyncAt time.Time
 	NextSyncAt   time.Time
+	NextDigestAt time.Time
 }
 
 type PeriodicSyncer struct {
+	mu         sync.RWMutex
 	syncStates []*SyncState
 }
 
@@ -51,6 +55,8 @@ func (p *PeriodicSyncer) Register(e *EmitterRegistration) {
 		return
 	}
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	for _, state := range p.syncStates {
 		if state.Registration.Emitter.EventType() == e.Emitter.EventType() {
 			log.Warnf("Emitter for event type %s is already registered, skipping", e.Emitter.EventType())
@@ -71,9 +77,23 @@ func (p *PeriodicSyncer) Register(e *EmitterRegistration) {
 	log.Infof("registered emitter for event type: %s", enum.ShortenEventType(e.Emitter.EventType()))
 }
 
+// states returns a snapshot of the registered sync states
+func (p *PeriodicSyncer) states() []*SyncState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*SyncState{}, p.syncStates...)
+}
+
 func (p *PeriodicSyncer) Resync(ctx context.Context, eventType string) error {
-	resynced := false
-	for _, state := range p.syncStates {
+	_, _, err := p.resync(ctx, eventType)
+	return err
+}
+
+// resync sends the objects of the event type, it returns the number of the resynced objects and whether the event
+// type is registered
+func (p *PeriodicSyncer) resync(ctx context.Context, eventType string) (int, bool, error) {
+	resynced, total := false, 0
+	for _, state := range p.states() {
 		registeredType := state.Registration.Emitter.EventType()
 		if registeredType != eventType {
 			continue
@@ -81,27 +101,62 @@ func (p *PeriodicSyncer) Resync(ctx context.Context, eventType string) error {
 
 		objects, err := state.Registration.ListFunc()
 		if err != nil {
-			return fmt.Errorf("failed to list objects for event type %s in Resync: %w", registeredType, err)
+			return 0, true, fmt.Errorf("failed to list objects for event type %s in Resync: %w", registeredType, err)
 		}
 
 		if len(objects) == 0 {
 			log.Infof("No objects found for event type: %s", registeredType)
-			return nil
+			return total, true, nil
 		}
 
 		if err = state.Registration.Emitter.Resync(objects); err != nil {
-			return fmt.Errorf("failed to resync objects for event type %s: %w", registeredType, err)
+			return 0, true, fmt.Errorf("failed to resync objects for event type %s: %w", registeredType, err)
 		}
 		resynced = true
 		// Update the next resync time for this emitter
 		state.NextResyncAt = time.Now().Add(configmap.GetResyncInterval(enum.EventType(registeredType)))
 		log.Infof("resynced %d objects for event type: %s", len(objects), enum.ShortenEventType(registeredType))
+		total += len(objects)
 	}
 
 	if !resynced {
 		log.Infof("No emitter registered for event type: %s", eventType)
+		return 0, false, nil
+	}
+	return total, true, nil
+}
+
+// digest sends the bucket hashes of the objects if the emitter supports the anti-entropy
+func (p *PeriodicSyncer) digest(state *SyncState) error {
+	digester, ok := state.Registration.Emitter.(emitters.Digester)
+	if !ok {
 		return nil
 	}
+	objects, err := state.Registration.ListFunc()
+	if err != nil {
+		return fmt.Errorf("failed to list objects for event type %s in digest: %w",
+			state.Registration.Emitter.EventType(), err)
+	}
+	return digester.SendDigest(objects)
+}
+
+// repair resends the mismatched buckets requested by the manager
+func (p *PeriodicSyncer) repair(repair *antientropy.RepairBundle) error {
+	for _, state := range p.states() {
+		if state.Registration.Emitter.EventType() != repair.EventType {
+			continue
+		}
+		digester, ok := state.Registration.Emitter.(emitters.Digester)
+		if !ok {
+			return fmt.Errorf("the emitter of event type %s doesn't support the anti-entropy", repair.EventType)
+		}
+		objects, err := state.Registration.ListFunc()
+		if err != nil {
+			return fmt.Errorf("failed to list objects for event type %s in repair: %w", repair.EventType, err)
+		}
+		return digester.Repair(objects, repair)
+	}
+	log.Infof("No emitter registered for event type: %s", repair.EventType)
 	return nil
 }
 
@@ -116,13 +171,22 @@ func (p *PeriodicSyncer) Start(ctx context.Context) error {
 			// resync when the manager request, every tick to resync one
 			if eventType := configs.GlobalResyncQueue.Pop(); eventType != "" {
 				log.Infof("resyncing event type: %s", enum.ShortenEventType(eventType))
-				if err := p.Resync(ctx, eventType); err != nil {
+				objects, registered, err := p.resync(ctx, eventType)
+				if err != nil {
 					log.Errorf("failed to resync the request event(%s): %v", eventType, err)
 				}
+				configs.GlobalResyncQueue.Finish(eventType, objects, registered, err)
+			}
+
+			// resend the mismatched buckets when the manager request, every tick to repair one
+			if repair := configs.GlobalRepairQueue.Pop(); repair != nil {
+				if err := p.repair(repair); err != nil {
+					log.Errorf("failed to repair the event(%s): %v", enum.ShortenEventType(repair.EventType), err)
+				}
 			}
 
 			// sync all registered emitters
-			for _, state := range p.syncStates {
+			for _, state := range p.states() {
 				eventType := state.Registration.Emitter.EventType()
 				if eventType == "" {
 					log.Warnf("Emitter for event type %s is not registered, skipping", eventType)
@@ -136,6 +200,14 @@ func (p *PeriodicSyncer) Start(ctx context.Context) error {
 						log.Errorf("failed to sync the event(%s): %v", enum.ShortenEventType(eventType), err)
 					}
 					state.NextSyncAt = time.Now().Add(configmap.GetSyncInterval(enum.EventType(eventType)))
+
+					// send the complete snapshot if the deltas are accumulated over the switch factor
+					if hybrid, ok := state.Registration.Emitter.(emitters.Hybrid); ok && hybrid.SwitchToComplete() {
+						if err := p.Resync(ctx, eventType); err != nil {
+							log.Errorf("failed to switch the event(%s) to the complete snapshot: %v",
+								enum.ShortenEventType(eventType), err)
+						}
+					}
 				}
 
 				// check if the next resync time has passed
@@ -146,6 +218,18 @@ func (p *PeriodicSyncer) Start(ctx context.Context) error {
 					}
 					state.NextResyncAt = time.Now().Add(configmap.GetResyncInterval(enum.EventType(eventType)))
 				}
+
+				// send the bucket hashes if the anti-entropy is enabled for the event type
+				if interval := configmap.GetAntiEntropyInterval(enum.EventType(eventType)); interval > 0 {
+					if state.NextDigestAt.IsZero() {
+						state.NextDigestAt = time.Now().Add(interval)
+					} else if time.Now().After(state.NextDigestAt) {
+						if err := p.digest(state); err != nil {
+							log.Errorf("failed to send the digest of the event(%s): %v", enum.ShortenEventType(eventType), err)
+						}
+						state.NextDigestAt = time.Now().Add(interval)
+					}
+				}
 			}
 
 		case <-ctx.Done():
@@ -5,6 +5,7 @@ package status
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"k8s.io/client-go/rest"
@@ -15,15 +16,26 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/hubha"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/applications"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/events"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedcluster"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedclusteraddon"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedhub"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/placement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/policies"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/provisioning"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
-var statusCtrlStarted = false
+// ErrStatusNotStarted is returned when adding a syncer before the status controllers are started
+var ErrStatusNotStarted = errors.New("the status controllers are not started yet")
+
+var (
+	statusCtrlStarted = false
+	// periodicSyncer is shared with the syncers of the optional CRDs, which are added after the status controllers
+	periodicSyncer *generic.PeriodicSyncer
+)
 
 // AddToManager adds all the syncers to the Manager.
 func AddToManager(ctx context.Context, mgr ctrl.Manager, transportClient transport.TransportClient,
@@ -54,7 +66,8 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 	configmap.StartHubHASyncerIfActive()
 
 	// start periodic syncer
-	periodicSyncer, err := generic.AddPeriodicSyncer(mgr)
+	var err error
+	periodicSyncer, err = generic.AddPeriodicSyncer(mgr)
 	if err != nil {
 		return fmt.Errorf("failed to start the periodic syncer: %w", err)
 	}
@@ -63,6 +76,16 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 		return fmt.Errorf("failed to launch managedcluster syncer: %w", err)
 	}
 
+	// placements, placement decisions and managed cluster sets
+	if err := placement.AddPlacementSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add placement syncer: %w", err)
+	}
+
+	// managed cluster addon health
+	if err := managedclusteraddon.AddManagedClusterAddOnSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add managedclusteraddon syncer: %w", err)
+	}
+
 	// Hub HA active syncer lifecycle is now fully managed by the configmap controller
 	// It will start/stop the syncer based on hub role changes in the configmap
 	// No boot-time direct call to avoid creating untracked syncer instances
@@ -110,3 +133,25 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 	}
 	return nil
 }
+
+// AddApplicationSyncer adds the syncer of the Argo CD Application or ApplicationSet once its CRD is installed, it
+// requires the status controllers to be started, so that the status can be delivered by the periodic syncer
+func AddApplicationSyncer(ctx context.Context, mgr ctrl.Manager, transportClient transport.TransportClient,
+	crdName string,
+) error {
+	if !statusCtrlStarted {
+		return ErrStatusNotStarted
+	}
+	return applications.AddApplicationSyncer(ctx, mgr, transportClient.GetProducer(), periodicSyncer, crdName)
+}
+
+// AddProvisioningSyncer adds the syncer of the ClusterDeployment, ClusterInstance or ClusterCurator once its CRD is
+// installed, it requires the status controllers to be started like the application syncer
+func AddProvisioningSyncer(ctx context.Context, mgr ctrl.Manager, transportClient transport.TransportClient,
+	crdName string,
+) error {
+	if !statusCtrlStarted {
+		return ErrStatusNotStarted
+	}
+	return provisioning.AddProvisioningSyncer(ctx, mgr, transportClient.GetProducer(), periodicSyncer, crdName)
+}
@@ -0,0 +1,148 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	log          = logger.DefaultZapLogger()
+	addedSyncers = map[string]bool{}
+
+	ApplicationGVK = schema.GroupVersionKind{
+		Group: "argoproj.io", Version: "v1alpha1", Kind: "Application",
+	}
+	ApplicationSetGVK = schema.GroupVersionKind{
+		Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet",
+	}
+)
+
+type applicationResource struct {
+	name      string
+	eventType enum.EventType
+	gvk       schema.GroupVersionKind
+	tweakFunc func(client.Object)
+}
+
+// resources are the Argo CD resources keyed by the name of their CRDs, the ApplicationSet CRD might not be installed
+// with the Application CRD, so each of them is synced once its own CRD is detected
+var resources = map[string]applicationResource{
+	"applications.argoproj.io": {
+		name:      "application",
+		eventType: enum.ApplicationType,
+		gvk:       ApplicationGVK,
+		tweakFunc: applicationTweakFunc,
+	},
+	"applicationsets.argoproj.io": {
+		name:      "applicationset",
+		eventType: enum.ApplicationSetType,
+		gvk:       ApplicationSetGVK,
+		tweakFunc: applicationSetTweakFunc,
+	},
+}
+
+// IsApplicationCRD returns true if the CRD is the Argo CD Application or ApplicationSet
+func IsApplicationCRD(crdName string) bool {
+	_, ok := resources[crdName]
+	return ok
+}
+
+// AddApplicationSyncer syncs the delivery status of the Argo CD Applications or the generated results of the
+// ApplicationSets, it should be invoked only if the CRD is installed on the managed hub
+func AddApplicationSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer, crdName string,
+) error {
+	res, ok := resources[crdName]
+	if !ok {
+		return fmt.Errorf("the CRD %s isn't an Argo CD resource", crdName)
+	}
+	if addedSyncers[crdName] {
+		return nil
+	}
+
+	gvk := res.gvk
+	emitter := emitters.NewObjectEmitter(
+		res.eventType,
+		p,
+		emitters.WithPredicateFunc(predicate.ResourceVersionChangedPredicate{}),
+		// only the delivery status is synced, the other fields are removed to reduce the bundle size
+		emitters.WithTweakFunc(res.tweakFunc),
+	)
+
+	if err := generic.AddSyncCtrl(mgr, res.name, func() client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj
+	}, emitter); err != nil {
+		return err
+	}
+
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := mgr.GetClient().List(ctx, list); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: emitter,
+	})
+	log.Infof("added the %s syncer", res.name)
+
+	addedSyncers[crdName] = true
+	return nil
+}
+
+// applicationTweakFunc keeps the source, destination, sync and health status of the application
+func applicationTweakFunc(object client.Object) {
+	obj, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an Application: %v", object)
+		return
+	}
+	utils.SlimUnstructured(obj,
+		[]string{"spec", "project"},
+		[]string{"spec", "destination"},
+		[]string{"spec", "source"},
+		[]string{"spec", "sources"},
+		[]string{"status", "sync"},
+		[]string{"status", "health"},
+		[]string{"status", "conditions"},
+		[]string{"status", "reconciledAt"},
+		[]string{"status", "operationState", "phase"},
+		[]string{"status", "operationState", "finishedAt"},
+	)
+}
+
+// applicationSetTweakFunc keeps the generators and the generated applications of the applicationset
+func applicationSetTweakFunc(object client.Object) {
+	obj, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an ApplicationSet: %v", object)
+		return
+	}
+	utils.SlimUnstructured(obj,
+		[]string{"spec", "generators"},
+		[]string{"spec", "template", "spec", "project"},
+		[]string{"status", "conditions"},
+		[]string{"status", "resources"},
+	)
+}
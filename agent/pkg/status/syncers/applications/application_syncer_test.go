@@ -0,0 +1,107 @@
+package applications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestApplicationTweakFunc(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]any{
+			"name":          "guestbook-cluster1",
+			"namespace":     "openshift-gitops",
+			"uid":           "9c5c3c0b-5f4e-4a7b-8f3e-1d2c3b4a5f6e",
+			"annotations":   map[string]any{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+			"managedFields": []any{map[string]any{"manager": "argocd"}},
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1", "kind": "ApplicationSet", "name": "guestbook", "uid": "1",
+			}},
+		},
+		"spec": map[string]any{
+			"project":     "default",
+			"destination": map[string]any{"name": "cluster1", "namespace": "guestbook"},
+			"source":      map[string]any{"repoURL": "https://github.com/argoproj/argocd-example-apps", "path": "guestbook"},
+			"syncPolicy":  map[string]any{"automated": map[string]any{"prune": true}},
+		},
+		"status": map[string]any{
+			"sync":           map[string]any{"status": "OutOfSync", "revision": "abc"},
+			"health":         map[string]any{"status": "Healthy"},
+			"resources":      []any{map[string]any{"kind": "Service", "name": "guestbook-ui"}},
+			"history":        []any{map[string]any{"id": int64(1)}},
+			"operationState": map[string]any{"phase": "Succeeded", "message": "successfully synced"},
+		},
+	}}
+
+	applicationTweakFunc(app)
+
+	assert.Equal(t, "guestbook-cluster1", app.GetName())
+	assert.Equal(t, "openshift-gitops", app.GetNamespace())
+	assert.Equal(t, "Application", app.GetKind())
+	assert.Empty(t, app.GetAnnotations())
+	assert.Empty(t, app.GetManagedFields())
+	require.Len(t, app.GetOwnerReferences(), 1)
+	assert.Equal(t, "guestbook", app.GetOwnerReferences()[0].Name)
+
+	destination, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "name")
+	assert.Equal(t, "cluster1", destination)
+	syncStatus, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	assert.Equal(t, "OutOfSync", syncStatus)
+	phase, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "phase")
+	assert.Equal(t, "Succeeded", phase)
+
+	for _, fields := range [][]string{
+		{"spec", "syncPolicy"},
+		{"status", "resources"},
+		{"status", "history"},
+		{"status", "operationState", "message"},
+	} {
+		_, found, _ := unstructured.NestedFieldNoCopy(app.Object, fields...)
+		assert.False(t, found, "the field %v should be removed", fields)
+	}
+}
+
+func TestApplicationSetTweakFunc(t *testing.T) {
+	appSet := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "ApplicationSet",
+		"metadata": map[string]any{
+			"name":      "guestbook",
+			"namespace": "openshift-gitops",
+			"uid":       "1",
+		},
+		"spec": map[string]any{
+			"generators": []any{map[string]any{"clusterDecisionResource": map[string]any{"configMapRef": "acm-placement"}}},
+			"template": map[string]any{
+				"metadata": map[string]any{"name": "guestbook-{{name}}"},
+				"spec":     map[string]any{"project": "default"},
+			},
+		},
+		"status": map[string]any{
+			"resources": []any{map[string]any{"name": "guestbook-cluster1", "status": "Synced"}},
+		},
+	}}
+
+	applicationSetTweakFunc(appSet)
+
+	generators, found, _ := unstructured.NestedSlice(appSet.Object, "spec", "generators")
+	assert.True(t, found)
+	assert.Len(t, generators, 1)
+	project, _, _ := unstructured.NestedString(appSet.Object, "spec", "template", "spec", "project")
+	assert.Equal(t, "default", project)
+	resources, _, _ := unstructured.NestedSlice(appSet.Object, "status", "resources")
+	assert.Len(t, resources, 1)
+	_, found, _ = unstructured.NestedFieldNoCopy(appSet.Object, "spec", "template", "metadata")
+	assert.False(t, found)
+}
+
+func TestIsApplicationCRD(t *testing.T) {
+	assert.True(t, IsApplicationCRD("applications.argoproj.io"))
+	assert.True(t, IsApplicationCRD("applicationsets.argoproj.io"))
+	assert.False(t, IsApplicationCRD("appprojects.argoproj.io"))
+	assert.False(t, IsApplicationCRD("clusterdeployments.hive.openshift.io"))
+}
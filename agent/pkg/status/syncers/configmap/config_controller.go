@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
@@ -148,6 +149,26 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ManagedClusterEventType))
 	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ManagedClusterEventType))
 
+	// anti-entropy interval - antientropy.managedcluster: 10m, zero to disable it
+	intervalsMutex.RLock()
+	antiEntropyKeys := make([]string, 0, len(antiEntropyIntervals))
+	for key := range antiEntropyIntervals {
+		antiEntropyKeys = append(antiEntropyKeys, key)
+	}
+	intervalsMutex.RUnlock()
+	for _, key := range antiEntropyKeys {
+		c.setSyncInterval(agentConfigMap, key)
+	}
+
+	// the event filtering and sampling rules, the previous rules are kept if they're invalid
+	if err := filter.SetEventRules(agentConfigMap.Data[filter.EventRulesKey]); err != nil {
+		reqLogger.Errorw("failed to set the event rules", "key", filter.EventRulesKey, "error", err)
+	}
+	// the involved objects whose events are forwarded to the event.resource_events
+	if err := filter.SetResourceEventSources(agentConfigMap.Data[filter.ResourceEventsKey]); err != nil {
+		reqLogger.Errorw("failed to set the resource event sources", "key", filter.ResourceEventsKey, "error", err)
+	}
+
 	// Set the agent configs
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
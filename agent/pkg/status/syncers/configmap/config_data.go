@@ -30,6 +30,24 @@ var (
 		GetResyncKey(enum.LocalPolicySpecType): defaultResyncInterval,
 	}
 
+	// Default anti-entropy intervals, the agent sends the bucket hashes of the objects every interval, and the manager
+	// requests the mismatched buckets to be resent. Each key is formed as "antientropy.<eventType>", the anti-entropy
+	// is disabled for the event types without the key or with a zero interval
+	defaultAntiEntropyInterval = 10 * time.Minute
+	antiEntropyIntervals       = map[string]time.Duration{
+		GetAntiEntropyKey(enum.ManagedClusterType):           defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.LocalPolicySpecType):          defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ApplicationType):              defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ApplicationSetType):           defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.LocalPlacementType):           defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.LocalPlacementDecisionType):   defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ManagedClusterSetType):        defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ManagedClusterSetBindingType): defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ClusterDeploymentType):        defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ClusterInstanceType):          defaultAntiEntropyInterval,
+		GetAntiEntropyKey(enum.ClusterCuratorType):           defaultAntiEntropyInterval,
+	}
+
 	agentConfigs = map[string]AgentConfigValue{
 		AgentAggregationKey:  AggregationFull,
 		EnableLocalPolicyKey: EnableLocalPolicyTrue,
@@ -123,6 +141,13 @@ func GetSyncInterval(eventType enum.EventType) time.Duration {
 	return interval
 }
 
+// GetAntiEntropyInterval returns the interval to send the bucket hashes, zero means the anti-entropy is disabled
+func GetAntiEntropyInterval(eventType enum.EventType) time.Duration {
+	intervalsMutex.RLock()
+	defer intervalsMutex.RUnlock()
+	return antiEntropyIntervals[GetAntiEntropyKey(eventType)]
+}
+
 // SetInterval sets the sync/resync interval for a specific bundle.
 // The key is derived from the EventType, e.g., GetSyncKey(enum.ManagedClusterEventType).
 func SetInterval(key string, val time.Duration) {
@@ -130,6 +155,8 @@ func SetInterval(key string, val time.Duration) {
 	defer intervalsMutex.Unlock()
 	if strings.HasPrefix(key, "resync.") {
 		reSyncIntervals[key] = val
+	} else if strings.HasPrefix(key, "antientropy.") {
+		antiEntropyIntervals[key] = val
 	} else {
 		syncIntervals[key] = val
 	}
@@ -139,6 +166,10 @@ func GetResyncKey(eventType enum.EventType) string {
 	return fmt.Sprintf("resync.%s", enum.ShortenEventType(string(eventType)))
 }
 
+func GetAntiEntropyKey(eventType enum.EventType) string {
+	return fmt.Sprintf("antientropy.%s", enum.ShortenEventType(string(eventType)))
+}
+
 func GetSyncKey(eventType enum.EventType) string {
 	return enum.ShortenEventType(string(eventType))
 }
@@ -49,6 +49,16 @@ func TestSetResyncInterval(t *testing.T) {
 	assert.Equal(2*time.Hour, GetResyncInterval(enum.ManagedClusterType))
 }
 
+func TestAntiEntropyInterval(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(10*time.Minute, GetAntiEntropyInterval(enum.ManagedClusterType))
+	assert.Equal(time.Duration(0), GetAntiEntropyInterval(enum.ManagedClusterAddOnType))
+
+	SetInterval(GetAntiEntropyKey(enum.ManagedClusterType), 0)
+	assert.Equal(time.Duration(0), GetAntiEntropyInterval(enum.ManagedClusterType))
+	SetInterval(GetAntiEntropyKey(enum.ManagedClusterType), defaultAntiEntropyInterval)
+}
+
 func TestAgentConfigs(t *testing.T) {
 	assert := assert.New(t)
 	assert.Equal(AggregationFull, GetAggregationLevel())
@@ -6,8 +6,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
@@ -28,6 +30,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 	}
 
 	runtimeClient = mgr.GetClient()
+	metrics.Registry.MustRegister(filter.DroppedEventsCounter)
 
 	managedClusterEventEmitter := emitters.NewEventEmitter(
 		enum.ManagedClusterEventType,
@@ -36,6 +39,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		managedClusterEventPredicate,
 		managedClusterEventTransform,
 		emitters.WithPostSend(managedClusterPostSend),
+		emitters.WithRules(filter.EventRulesFor(enum.ManagedClusterEventType)),
 	)
 
 	localRootPolicyEventEmitter := emitters.NewEventEmitter(
@@ -45,6 +49,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		localRootPolicyEventPredicate,
 		localRootPolicyEventTransform,
 		emitters.WithPostSend(localRootPolicyPostSend),
+		emitters.WithRules(filter.EventRulesFor(enum.LocalRootPolicyEventType)),
 	)
 
 	clusterGroupUpgradeEventEmitter := emitters.NewEventEmitter(
@@ -54,6 +59,18 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		clusterGroupUpgradeEventPredicate,
 		clusterGroupUpgradeEventTransform,
 		emitters.WithPostSend(clusterGroupUpgradePostSend),
+		emitters.WithRules(filter.EventRulesFor(enum.ClusterGroupUpgradesEventType)),
+	)
+
+	// the events of the involved objects declared by the resourceEvents in the agent configmap
+	resourceEventEmitter := emitters.NewEventEmitter(
+		enum.ResourceEventType,
+		producer,
+		runtimeClient,
+		resourceEventPredicate,
+		resourceEventTransform,
+		emitters.WithPostSend(resourceEventPostSend),
+		emitters.WithRules(filter.EventRulesFor(enum.ResourceEventType)),
 	)
 
 	// 2. add the emitter to controller
@@ -61,7 +78,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		mgr,
 		"event",
 		func() client.Object { return &corev1.Event{} },
-		managedClusterEventEmitter, localRootPolicyEventEmitter, clusterGroupUpgradeEventEmitter,
+		managedClusterEventEmitter, localRootPolicyEventEmitter, clusterGroupUpgradeEventEmitter, resourceEventEmitter,
 	); err != nil {
 		return err
 	}
@@ -82,6 +99,11 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		Emitter:  clusterGroupUpgradeEventEmitter,
 	})
 
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) { return listEvents(ctx, runtimeClient) },
+		Emitter:  resourceEventEmitter,
+	})
+
 	addEventSyncer = true
 	return nil
 }
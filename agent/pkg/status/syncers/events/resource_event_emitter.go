@@ -0,0 +1,81 @@
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+var TimeFilterKeyForResourceEvent = enum.ShortenEventType(string(enum.ResourceEventType))
+
+func resourceEventPostSend(events []interface{}) error {
+	for _, resourceEvent := range events {
+		evt, ok := resourceEvent.(*models.ResourceEvent)
+		if !ok {
+			return fmt.Errorf("failed to type assert to models.ResourceEvent, event: %v", resourceEvent)
+		}
+		filter.CacheTime(TimeFilterKeyForResourceEvent, evt.CreatedAt)
+	}
+	return nil
+}
+
+// resourceEventPredicate filters the events of the involved objects declared by the resourceEvents in the agent
+// configmap
+func resourceEventPredicate(obj client.Object) bool {
+	evt, ok := obj.(*corev1.Event)
+	if !ok {
+		return false
+	}
+
+	if !filter.MatchResourceEvent(evt) {
+		return false
+	}
+
+	if !filter.Newer(TimeFilterKeyForResourceEvent, getEventLastTime(evt).Time) {
+		log.Debugw("event filtered:", "event", evt.Namespace+"/"+evt.Name, "eventTime", getEventLastTime(evt).Time)
+		return false
+	}
+
+	return true
+}
+
+// resourceEventTransform transforms k8s Event to ResourceEvent, the involved object is identified by its GVK
+func resourceEventTransform(_ client.Client, obj client.Object) interface{} {
+	evt, ok := obj.(*corev1.Event)
+	if !ok {
+		log.Errorw("failed to type assert to corev1.Event", "object", obj.GetName())
+		return nil
+	}
+
+	gv, err := schema.ParseGroupVersion(evt.InvolvedObject.APIVersion)
+	if err != nil {
+		log.Warnw("failed to parse the api version of the involved object", "event", evt.Namespace+"/"+evt.Name,
+			"apiVersion", evt.InvolvedObject.APIVersion, "error", err)
+	}
+
+	return &models.ResourceEvent{
+		EventNamespace:      evt.Namespace,
+		EventName:           evt.Name,
+		LeafHubName:         configs.GetLeafHubName(),
+		Group:               gv.Group,
+		Version:             gv.Version,
+		Kind:                evt.InvolvedObject.Kind,
+		ObjectNamespace:     evt.InvolvedObject.Namespace,
+		ObjectName:          evt.InvolvedObject.Name,
+		ObjectUID:           string(evt.InvolvedObject.UID),
+		Message:             evt.Message,
+		Reason:              evt.Reason,
+		Count:               int(evt.Count),
+		ReportingController: evt.ReportingController,
+		ReportingInstance:   evt.ReportingInstance,
+		EventType:           evt.Type,
+		CreatedAt:           getEventLastTime(evt).Time,
+	}
+}
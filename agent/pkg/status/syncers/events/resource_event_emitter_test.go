@@ -0,0 +1,54 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestResourceEventEmitter(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "hub1"})
+	require.NoError(t, filter.SetResourceEventSources("- group: metal3.io\n  kind: BareMetalHost"))
+	defer func() {
+		require.NoError(t, filter.SetResourceEventSources(""))
+	}()
+
+	lastTime := metav1.NewTime(time.Date(2024, 7, 6, 15, 4, 5, 0, time.UTC))
+	evt := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-machine-api", Name: "host1.17e0a1b2c3d4"},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "metal3.io/v1alpha1", Kind: "BareMetalHost", Namespace: "openshift-machine-api",
+			Name: "host1", UID: "4f8b1c2d",
+		},
+		Reason:        "ProvisioningComplete",
+		Message:       "Image provisioning completed",
+		Type:          corev1.EventTypeNormal,
+		Count:         2,
+		LastTimestamp: lastTime,
+	}
+	assert.True(t, resourceEventPredicate(evt))
+
+	resourceEvent, ok := resourceEventTransform(nil, evt).(*models.ResourceEvent)
+	require.True(t, ok)
+	assert.Equal(t, "hub1", resourceEvent.LeafHubName)
+	assert.Equal(t, "metal3.io", resourceEvent.Group)
+	assert.Equal(t, "v1alpha1", resourceEvent.Version)
+	assert.Equal(t, "BareMetalHost", resourceEvent.Kind)
+	assert.Equal(t, "openshift-machine-api", resourceEvent.ObjectNamespace)
+	assert.Equal(t, "host1", resourceEvent.ObjectName)
+	assert.Equal(t, "4f8b1c2d", resourceEvent.ObjectUID)
+	assert.Equal(t, 2, resourceEvent.Count)
+	assert.Equal(t, lastTime.Time, resourceEvent.CreatedAt)
+
+	// the events of the undeclared involved objects aren't forwarded
+	evt.InvolvedObject.Kind = "Pod"
+	assert.False(t, resourceEventPredicate(evt))
+}
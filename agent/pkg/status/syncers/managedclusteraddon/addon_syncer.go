@@ -0,0 +1,101 @@
+package managedclusteraddon
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	log              = logger.DefaultZapLogger()
+	addedAddonSyncer = false
+)
+
+// AddManagedClusterAddOnSyncer syncs the health conditions of the addons on the managed clusters, only the changed
+// addons are sent in the delta bundles, the full list is sent by the periodic resync
+func AddManagedClusterAddOnSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedAddonSyncer {
+		return nil
+	}
+
+	addonEmitter := emitters.NewObjectEmitter(
+		enum.ManagedClusterAddOnType,
+		p,
+		emitters.WithPredicateFunc(addonPredicate),
+		emitters.WithTweakFunc(addonTweakFunc),
+	)
+
+	if err := generic.AddSyncCtrl(
+		mgr,
+		"managedclusteraddon",
+		func() client.Object { return &addonv1alpha1.ManagedClusterAddOn{} },
+		addonEmitter,
+	); err != nil {
+		return err
+	}
+
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var addons addonv1alpha1.ManagedClusterAddOnList
+			if err := mgr.GetClient().List(ctx, &addons); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(addons.Items))
+			for i := range addons.Items {
+				objects = append(objects, &addons.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: addonEmitter,
+	})
+	log.Info("added the managedclusteraddon syncer")
+
+	addedAddonSyncer = true
+	return nil
+}
+
+// addonPredicate ignores the updates that don't change the health of the addon, e.g. the lease renewal
+var addonPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldAddon, ok := e.ObjectOld.(*addonv1alpha1.ManagedClusterAddOn)
+		if !ok {
+			return true
+		}
+		newAddon, ok := e.ObjectNew.(*addonv1alpha1.ManagedClusterAddOn)
+		if !ok {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(oldAddon.Status.Conditions, newAddon.Status.Conditions) ||
+			!equality.Semantic.DeepEqual(oldAddon.Status.HealthCheck, newAddon.Status.HealthCheck)
+	},
+}
+
+// addonTweakFunc keeps the conditions and the health check mode of the addon status
+func addonTweakFunc(object client.Object) {
+	addon, ok := object.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not a ManagedClusterAddOn: %v", object)
+		return
+	}
+	addon.SetManagedFields(nil)
+	addon.SetAnnotations(nil)
+	addon.SetOwnerReferences(nil)
+	addon.Spec = addonv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: addon.Spec.InstallNamespace}
+	addon.Status = addonv1alpha1.ManagedClusterAddOnStatus{
+		Conditions:  addon.Status.Conditions,
+		HealthCheck: addon.Status.HealthCheck,
+	}
+}
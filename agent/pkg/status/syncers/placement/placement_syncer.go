@@ -0,0 +1,105 @@
+package placement
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	log                  = logger.DefaultZapLogger()
+	addedPlacementSyncer = false
+)
+
+// AddPlacementSyncer syncs the placements, the placement decisions, the managed cluster sets and the bindings of the
+// managed hub, so that the global hub knows which clusters are selected by each placement
+func AddPlacementSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedPlacementSyncer {
+		return nil
+	}
+
+	for _, s := range []struct {
+		name         string
+		eventType    enum.EventType
+		instanceFunc func() client.Object
+		listFunc     func() client.ObjectList
+	}{
+		{
+			"placement", enum.LocalPlacementType,
+			func() client.Object { return &clusterv1beta1.Placement{} },
+			func() client.ObjectList { return &clusterv1beta1.PlacementList{} },
+		},
+		{
+			"placementdecision", enum.LocalPlacementDecisionType,
+			func() client.Object { return &clusterv1beta1.PlacementDecision{} },
+			func() client.ObjectList { return &clusterv1beta1.PlacementDecisionList{} },
+		},
+		{
+			"managedclusterset", enum.ManagedClusterSetType,
+			func() client.Object { return &clusterv1beta2.ManagedClusterSet{} },
+			func() client.ObjectList { return &clusterv1beta2.ManagedClusterSetList{} },
+		},
+		{
+			"managedclustersetbinding", enum.ManagedClusterSetBindingType,
+			func() client.Object { return &clusterv1beta2.ManagedClusterSetBinding{} },
+			func() client.ObjectList { return &clusterv1beta2.ManagedClusterSetBindingList{} },
+		},
+	} {
+		listFunc := s.listFunc
+		emitter := emitters.NewObjectEmitter(
+			s.eventType,
+			p,
+			emitters.WithPredicateFunc(predicate.ResourceVersionChangedPredicate{}),
+			emitters.WithTweakFunc(tweakFunc),
+		)
+
+		if err := generic.AddSyncCtrl(mgr, s.name, s.instanceFunc, emitter); err != nil {
+			return err
+		}
+
+		periodicSyncer.Register(&generic.EmitterRegistration{
+			ListFunc: func() ([]client.Object, error) {
+				list := listFunc()
+				if err := mgr.GetClient().List(ctx, list); err != nil {
+					return nil, err
+				}
+				items, err := meta.ExtractList(list)
+				if err != nil {
+					return nil, err
+				}
+				objects := make([]client.Object, 0, len(items))
+				for _, item := range items {
+					if obj, ok := item.(client.Object); ok {
+						objects = append(objects, obj)
+					}
+				}
+				return objects, nil
+			},
+			Emitter: emitter,
+		})
+		log.Infof("added the %s syncer", s.name)
+	}
+
+	addedPlacementSyncer = true
+	return nil
+}
+
+func tweakFunc(object client.Object) {
+	object.SetManagedFields(nil)
+	annotations := object.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	object.SetAnnotations(annotations)
+}
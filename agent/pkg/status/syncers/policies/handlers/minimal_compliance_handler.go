@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+// minimalComplianceHandler only keeps the counts of the clusters for each policy, it's used by the minimal
+// aggregation level to report the compliance of the large hubs
+type minimalComplianceHandler struct {
+	eventData    *grc.MinimalComplianceBundle
+	shouldUpdate func(client.Object) bool
+}
+
+func NewMinimalComplianceHandler(eventData *grc.MinimalComplianceBundle,
+	shouldUpdate func(client.Object) bool,
+) interfaces.Handler {
+	return &minimalComplianceHandler{
+		eventData:    eventData,
+		shouldUpdate: shouldUpdate,
+	}
+}
+
+func (h *minimalComplianceHandler) Get() interface{} {
+	return h.eventData
+}
+
+// Update returns true only if the counts or the remediation action of the policy are changed
+func (h *minimalComplianceHandler) Update(obj client.Object) bool {
+	if !h.shouldUpdate(obj) {
+		return false
+	}
+
+	policy, isPolicy := obj.(*policiesv1.Policy)
+	if !isPolicy {
+		return false // do not handle objects other than policy
+	}
+
+	compliance := getMinimalCompliance(extractPolicyIdentity(obj), policy)
+	index := h.indexOf(obj)
+	if index == -1 {
+		if compliance.AppliedClusters == 0 {
+			return false
+		}
+		*h.eventData = append(*h.eventData, *compliance)
+		return true
+	}
+
+	if (*h.eventData)[index] == *compliance {
+		return false
+	}
+	(*h.eventData)[index] = *compliance
+	return true
+}
+
+func (h *minimalComplianceHandler) Delete(obj client.Object) bool {
+	if !h.shouldUpdate(obj) {
+		return false
+	}
+
+	index := h.indexOf(obj)
+	if index == -1 { // trying to delete object which doesn't exist
+		return false
+	}
+
+	*h.eventData = append((*h.eventData)[:index], (*h.eventData)[index+1:]...) // remove from objects
+	return true
+}
+
+func (h *minimalComplianceHandler) indexOf(obj client.Object) int {
+	uid := extractPolicyIdentity(obj)
+	namespacedName := fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+	for i, compliance := range *h.eventData {
+		if (len(uid) > 0 && compliance.PolicyID == uid) || (len(uid) == 0 &&
+			compliance.NamespacedName == namespacedName) {
+			return i
+		}
+	}
+	return -1
+}
+
+func getMinimalCompliance(policyID string, policy *policiesv1.Policy) *grc.MinimalCompliance {
+	compliance := &grc.MinimalCompliance{
+		PolicyID:          policyID,
+		NamespacedName:    fmt.Sprintf("%s/%s", policy.GetNamespace(), policy.GetName()),
+		RemediationAction: policy.Spec.RemediationAction,
+		AppliedClusters:   len(policy.Status.Status),
+	}
+	for _, clusterStatus := range policy.Status.Status {
+		switch clusterStatus.ComplianceState {
+		case policiesv1.Compliant:
+			compliance.CompliantClusters++
+		case policiesv1.NonCompliant:
+			compliance.NonCompliantClusters++
+		case policiesv1.Pending:
+			compliance.PendingClusters++
+		default:
+			compliance.UnknownClusters++
+		}
+	}
+	return compliance
+}
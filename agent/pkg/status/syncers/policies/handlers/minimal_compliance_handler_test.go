@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+func TestMinimalComplianceHandler(t *testing.T) {
+	bundle := &grc.MinimalComplianceBundle{}
+	handler := NewMinimalComplianceHandler(bundle, func(obj client.Object) bool { return true })
+
+	policy := &policiesv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "default", UID: "uid1"},
+		Spec:       policiesv1.PolicySpec{RemediationAction: policiesv1.Inform},
+	}
+
+	// the policy without the applied clusters isn't added
+	assert.False(t, handler.Update(policy))
+	require.Empty(t, *bundle)
+
+	policy.Status.Status = []*policiesv1.CompliancePerClusterStatus{
+		{ClusterName: "cluster1", ComplianceState: policiesv1.Compliant},
+		{ClusterName: "cluster2", ComplianceState: policiesv1.NonCompliant},
+		{ClusterName: "cluster3", ComplianceState: policiesv1.Pending},
+	}
+	assert.True(t, handler.Update(policy))
+	require.Len(t, *bundle, 1)
+	assert.Equal(t, grc.MinimalCompliance{
+		PolicyID:             "uid1",
+		NamespacedName:       "default/policy1",
+		RemediationAction:    policiesv1.Inform,
+		NonCompliantClusters: 1,
+		CompliantClusters:    1,
+		PendingClusters:      1,
+		AppliedClusters:      3,
+	}, (*bundle)[0])
+
+	// the counts aren't changed
+	policy.Status.Status[0].ClusterName = "cluster4"
+	assert.False(t, handler.Update(policy))
+
+	policy.Status.Status[2].ComplianceState = policiesv1.NonCompliant
+	assert.True(t, handler.Update(policy))
+	assert.Equal(t, 2, (*bundle)[0].NonCompliantClusters)
+	assert.Equal(t, 0, (*bundle)[0].PendingClusters)
+
+	// the cluster without the compliance state is unknown
+	policy.Status.Status[0].ComplianceState = ""
+	assert.True(t, handler.Update(policy))
+	assert.Equal(t, 0, (*bundle)[0].CompliantClusters)
+	assert.Equal(t, 1, (*bundle)[0].UnknownClusters)
+
+	policy.Spec.RemediationAction = policiesv1.Enforce
+	assert.True(t, handler.Update(policy))
+	assert.Equal(t, policiesv1.Enforce, (*bundle)[0].RemediationAction)
+
+	assert.True(t, handler.Delete(policy))
+	assert.Empty(t, *bundle)
+	assert.False(t, handler.Delete(policy))
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+var (
+	// the message of the configuration policy, e.g. "NonCompliant; violation - pods [nginx] not found in namespace
+	// default; notification - namespaces [default] found as specified"
+	templateClauseRegex = regexp.MustCompile(`^(violation|notification) - (.+)$`)
+	relatedObjectRegex  = regexp.MustCompile(`^(\S+) \[([^\]]*)\] (.+?)(?: in namespaces?:? (.+))?$`)
+	clauseCompliance    = map[string]string{
+		"violation":    string(policiesv1.NonCompliant),
+		"notification": string(policiesv1.Compliant),
+	}
+)
+
+// templateComplianceHandler keeps the compliance of each template of the replicated policies, the related objects of
+// the templates are parsed from the latest compliance messages
+type templateComplianceHandler struct {
+	ctx           context.Context
+	runtimeClient client.Client
+	eventData     *grc.PolicyTemplateComplianceBundle
+	shouldUpdate  func(client.Object) bool
+}
+
+func NewTemplateComplianceHandler(ctx context.Context, eventData *grc.PolicyTemplateComplianceBundle,
+	shouldUpdate func(client.Object) bool, c client.Client,
+) interfaces.Handler {
+	return &templateComplianceHandler{
+		ctx:           ctx,
+		runtimeClient: c,
+		eventData:     eventData,
+		shouldUpdate:  shouldUpdate,
+	}
+}
+
+func (h *templateComplianceHandler) Get() interface{} {
+	return h.eventData
+}
+
+func (h *templateComplianceHandler) Update(obj client.Object) bool {
+	if !h.shouldUpdate(obj) {
+		return false
+	}
+
+	policy, isPolicy := obj.(*policiesv1.Policy)
+	if !isPolicy {
+		return false // do not handle objects other than policy
+	}
+
+	templates := getTemplateCompliances(policy)
+	index := h.indexOf(obj)
+	if index == -1 {
+		if len(templates) == 0 {
+			return false
+		}
+		rootPolicy, clusterID, clusterName, err := GetRootPolicyAndClusterInfo(h.ctx, policy, h.runtimeClient)
+		if err != nil {
+			log.Warnw("failed to get the root policy and cluster of the replicated policy", "namespace",
+				policy.Namespace, "name", policy.Name, "error", err)
+			return false
+		}
+		*h.eventData = append(*h.eventData, grc.PolicyTemplateCompliance{
+			PolicyID:       string(rootPolicy.GetUID()),
+			ClusterName:    clusterName,
+			ClusterID:      clusterID,
+			NamespacedName: fmt.Sprintf("%s/%s", policy.GetNamespace(), policy.GetName()),
+			Templates:      templates,
+		})
+		return true
+	}
+
+	if reflect.DeepEqual((*h.eventData)[index].Templates, templates) {
+		return false
+	}
+	(*h.eventData)[index].Templates = templates
+	return true
+}
+
+func (h *templateComplianceHandler) Delete(obj client.Object) bool {
+	if !h.shouldUpdate(obj) {
+		return false
+	}
+
+	index := h.indexOf(obj)
+	if index == -1 { // trying to delete object which doesn't exist
+		return false
+	}
+
+	*h.eventData = append((*h.eventData)[:index], (*h.eventData)[index+1:]...) // remove from objects
+	return true
+}
+
+// indexOf finds the replicated policy by the namespaced name, the uid of the replicated policy isn't in the bundle
+func (h *templateComplianceHandler) indexOf(obj client.Object) int {
+	namespacedName := fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+	for i, compliance := range *h.eventData {
+		if compliance.NamespacedName == namespacedName {
+			return i
+		}
+	}
+	return -1
+}
+
+// getTemplateCompliances returns the compliance of the templates from the status details of the replicated policy,
+// the kind of the template is from the policy templates of the spec
+func getTemplateCompliances(policy *policiesv1.Policy) []grc.TemplateCompliance {
+	kindOf := map[string]string{}
+	for _, template := range policy.Spec.PolicyTemplates {
+		if template == nil || template.ObjectDefinition.Raw == nil {
+			continue
+		}
+		typeMeta := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(template.ObjectDefinition.Raw, typeMeta); err != nil {
+			continue
+		}
+		kindOf[typeMeta.Name] = typeMeta.Kind
+	}
+
+	templates := make([]grc.TemplateCompliance, 0, len(policy.Status.Details))
+	for _, detail := range policy.Status.Details {
+		if detail == nil {
+			continue
+		}
+		template := grc.TemplateCompliance{
+			TemplateKind: kindOf[detail.TemplateMeta.Name],
+			TemplateName: detail.TemplateMeta.Name,
+			Compliance:   string(detail.ComplianceState),
+		}
+		// the latest message is the first one of the history
+		if len(detail.History) > 0 {
+			template.Message = detail.History[0].Message
+			template.LastTimestamp = detail.History[0].LastTimestamp.Time
+			template.RelatedObjects = parseRelatedObjects(template.Message)
+		}
+		templates = append(templates, template)
+	}
+	return templates
+}
+
+// parseRelatedObjects parses the objects evaluated by the configuration policy from the compliance message, it
+// returns nil if the message isn't in the format of the configuration policy, e.g. the certificate policy
+func parseRelatedObjects(message string) []grc.RelatedObject {
+	clauses := strings.Split(message, "; ")
+	if len(clauses) < 2 {
+		return nil
+	}
+	var objects []grc.RelatedObject
+	// the first clause is the compliance state
+	for _, clause := range clauses[1:] {
+		clauseMatch := templateClauseRegex.FindStringSubmatch(strings.TrimSpace(clause))
+		if clauseMatch == nil {
+			continue
+		}
+		objectMatch := relatedObjectRegex.FindStringSubmatch(clauseMatch[2])
+		if objectMatch == nil {
+			continue
+		}
+		// the namespace is unknown if the objects are evaluated in several namespaces
+		namespace := strings.TrimSpace(objectMatch[4])
+		if strings.Contains(namespace, ",") {
+			namespace = ""
+		}
+		for _, name := range strings.Split(objectMatch[2], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			objects = append(objects, grc.RelatedObject{
+				Kind:       objectMatch[1],
+				Namespace:  namespace,
+				Name:       name,
+				Compliance: clauseCompliance[clauseMatch[1]],
+				Reason:     objectMatch[3],
+			})
+		}
+	}
+	return objects
+}
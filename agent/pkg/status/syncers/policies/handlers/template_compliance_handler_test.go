@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestParseRelatedObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected []grc.RelatedObject
+	}{
+		{
+			name:    "violation in namespace",
+			message: "NonCompliant; violation - pods [nginx-pod] not found in namespace default",
+			expected: []grc.RelatedObject{
+				{Kind: "pods", Namespace: "default", Name: "nginx-pod", Compliance: "NonCompliant", Reason: "not found"},
+			},
+		},
+		{
+			name: "several clauses and names",
+			message: "NonCompliant; violation - pods [a, b] found but not as specified in namespace test; " +
+				"notification - namespaces [test] found as specified",
+			expected: []grc.RelatedObject{
+				{Kind: "pods", Namespace: "test", Name: "a", Compliance: "NonCompliant", Reason: "found but not as specified"},
+				{Kind: "pods", Namespace: "test", Name: "b", Compliance: "NonCompliant", Reason: "found but not as specified"},
+				{Kind: "namespaces", Name: "test", Compliance: "Compliant", Reason: "found as specified"},
+			},
+		},
+		{
+			name:    "several namespaces",
+			message: "Compliant; notification - configmaps [cm] found as specified in namespaces: ns1, ns2",
+			expected: []grc.RelatedObject{
+				{Kind: "configmaps", Name: "cm", Compliance: "Compliant", Reason: "found as specified"},
+			},
+		},
+		{
+			name:     "unknown format",
+			message:  "NonCompliant; the certificate expires in 10 days",
+			expected: nil,
+		},
+		{
+			name:     "no clause",
+			message:  "Compliant",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRelatedObjects(tt.message))
+		})
+	}
+}
+
+func TestTemplateComplianceHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, policiesv1.AddToScheme(scheme))
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+
+	rootPolicy := &policiesv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "default", UID: "root-uid"}}
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", UID: "cluster-uid"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rootPolicy, cluster).Build()
+
+	bundle := &grc.PolicyTemplateComplianceBundle{}
+	handler := NewTemplateComplianceHandler(context.Background(), bundle,
+		func(obj client.Object) bool { return true }, c)
+
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	replicated := &policiesv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default.policy1",
+			Namespace: "cluster1",
+			Labels: map[string]string{
+				constants.PolicyEventRootPolicyNameLabelKey: "default.policy1",
+				constants.PolicyEventClusterNameLabelKey:    "cluster1",
+			},
+		},
+		Spec: policiesv1.PolicySpec{
+			PolicyTemplates: []*policiesv1.PolicyTemplate{
+				{ObjectDefinition: runtime.RawExtension{
+					Raw: []byte(`{"kind":"ConfigurationPolicy","metadata":{"name":"pod-policy"}}`),
+				}},
+			},
+		},
+	}
+
+	// the policy without the status isn't added
+	assert.False(t, handler.Update(replicated))
+
+	replicated.Status.Details = []*policiesv1.DetailsPerTemplate{
+		{
+			TemplateMeta:    metav1.ObjectMeta{Name: "pod-policy"},
+			ComplianceState: policiesv1.NonCompliant,
+			History: []policiesv1.ComplianceHistory{
+				{LastTimestamp: now, Message: "NonCompliant; violation - pods [nginx] not found in namespace default"},
+				{Message: "Compliant; notification - pods [nginx] found as specified in namespace default"},
+			},
+		},
+	}
+	assert.True(t, handler.Update(replicated))
+	require.Len(t, *bundle, 1)
+	compliance := (*bundle)[0]
+	assert.Equal(t, "root-uid", compliance.PolicyID)
+	assert.Equal(t, "cluster1", compliance.ClusterName)
+	assert.Equal(t, "cluster-uid", compliance.ClusterID)
+	require.Len(t, compliance.Templates, 1)
+	assert.Equal(t, grc.TemplateCompliance{
+		TemplateKind:  "ConfigurationPolicy",
+		TemplateName:  "pod-policy",
+		Compliance:    "NonCompliant",
+		Message:       "NonCompliant; violation - pods [nginx] not found in namespace default",
+		LastTimestamp: now.Time,
+		RelatedObjects: []grc.RelatedObject{
+			{Kind: "pods", Namespace: "default", Name: "nginx", Compliance: "NonCompliant", Reason: "not found"},
+		},
+	}, compliance.Templates[0])
+
+	// the status isn't changed
+	assert.False(t, handler.Update(replicated))
+
+	replicated.Status.Details[0].ComplianceState = policiesv1.Compliant
+	assert.True(t, handler.Update(replicated))
+	assert.Equal(t, "Compliant", (*bundle)[0].Templates[0].Compliance)
+
+	assert.True(t, handler.Delete(replicated))
+	assert.Empty(t, *bundle)
+}
@@ -2,6 +2,7 @@ package policies
 
 import (
 	"context"
+	"strconv"
 
 	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -52,6 +53,25 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 	localCompleteEmitter := generic.NewGenericEmitter(enum.LocalCompleteComplianceType,
 		generic.WithDependencyVersion(localComplianceVersion))
 
+	// 3. local minimal compliance, only the counts of the clusters are sent for the minimal level
+	localMinimalShouldUpdate := func(obj client.Object) bool {
+		return configmap.GetAggregationLevel() == configmap.AggregationMinimal && // minimal level
+			configmap.GetEnableLocalPolicy() == configmap.EnableLocalPolicyTrue && // enable local policy
+			!utils.HasLabel(obj, constants.PolicyEventRootPolicyNameLabelKey) // root policy
+	}
+	localMinimalHandler := handlers.NewMinimalComplianceHandler(&grc.MinimalComplianceBundle{},
+		localMinimalShouldUpdate)
+	localMinimalEmitter := generic.NewGenericEmitter(enum.MiniComplianceType)
+
+	// 4. local template compliance, the compliance of each template of the replicated policies
+	localTemplateShouldUpdate := func(obj client.Object) bool {
+		return configmap.GetAggregationLevel() == configmap.AggregationFull && // full level
+			enableLocalReplicatedPolicy(obj) // replicated policy
+	}
+	localTemplateHandler := handlers.NewTemplateComplianceHandler(ctx, &grc.PolicyTemplateComplianceBundle{},
+		localTemplateShouldUpdate, mgr.GetClient())
+	localTemplateEmitter := generic.NewGenericEmitter(enum.LocalTemplateComplianceType)
+
 	return generic.LaunchMultiEventSyncer(
 		"status.policy",
 		mgr,
@@ -67,6 +87,14 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 				Handler: localCompleteHandler,
 				Emitter: localCompleteEmitter,
 			},
+			{
+				Handler: localMinimalHandler,
+				Emitter: localMinimalEmitter,
+			},
+			{
+				Handler: localTemplateHandler,
+				Emitter: localTemplateEmitter,
+			},
 		},
 	)
 }
@@ -86,6 +114,10 @@ func AddPolicySyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer
 		emitters.WithPredicateFunc(localPolicySpecPredicate),
 		emitters.WithTargetFunc(enableLocalRootPolicy),
 		emitters.WithTweakFunc(localPolicySpecTweakFunc),
+		// the policy is only sent when the generation is changed
+		emitters.WithDigestVersionFunc(func(obj client.Object) string {
+			return strconv.FormatInt(obj.GetGeneration(), 10)
+		}),
 	)
 
 	// 1.2 local replicated policy status events
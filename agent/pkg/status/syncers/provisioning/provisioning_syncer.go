@@ -0,0 +1,152 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	log          = logger.DefaultZapLogger()
+	addedSyncers = map[string]bool{}
+
+	ClusterDeploymentGVK = schema.GroupVersionKind{
+		Group: "hive.openshift.io", Version: "v1", Kind: "ClusterDeployment",
+	}
+	ClusterInstanceGVK = schema.GroupVersionKind{
+		Group: "siteconfig.open-cluster-management.io", Version: "v1alpha1", Kind: "ClusterInstance",
+	}
+	ClusterCuratorGVK = schema.GroupVersionKind{
+		Group: "cluster.open-cluster-management.io", Version: "v1beta1", Kind: "ClusterCurator",
+	}
+)
+
+type provisioningResource struct {
+	name      string
+	eventType enum.EventType
+	gvk       schema.GroupVersionKind
+	// the fields are kept in the bundle, they are enough to derive the phase of the provisioning
+	fields [][]string
+}
+
+// resources are the provisioning resources keyed by the name of their CRDs
+var resources = map[string]provisioningResource{
+	"clusterdeployments.hive.openshift.io": {
+		name:      "clusterdeployment",
+		eventType: enum.ClusterDeploymentType,
+		gvk:       ClusterDeploymentGVK,
+		fields: [][]string{
+			{"spec", "clusterName"},
+			{"spec", "baseDomain"},
+			{"spec", "installed"},
+			{"spec", "clusterInstallRef"},
+			{"status", "installedTimestamp"},
+			{"status", "installVersion"},
+			{"status", "powerState"},
+			{"status", "conditions"},
+		},
+	},
+	"clusterinstances.siteconfig.open-cluster-management.io": {
+		name:      "clusterinstance",
+		eventType: enum.ClusterInstanceType,
+		gvk:       ClusterInstanceGVK,
+		fields: [][]string{
+			{"spec", "clusterName"},
+			{"spec", "baseDomain"},
+			{"spec", "clusterImageSetNameRef"},
+			{"status", "conditions"},
+			{"status", "deploymentConditions"},
+		},
+	},
+	"clustercurators.cluster.open-cluster-management.io": {
+		name:      "clustercurator",
+		eventType: enum.ClusterCuratorType,
+		gvk:       ClusterCuratorGVK,
+		fields: [][]string{
+			{"spec", "desiredCuration"},
+			{"spec", "upgrade", "desiredUpdate"},
+			{"spec", "upgrade", "channel"},
+			{"status", "conditions"},
+		},
+	},
+}
+
+// IsProvisioningCRD returns true if the CRD is one of the ClusterDeployment, ClusterInstance or ClusterCurator
+func IsProvisioningCRD(crdName string) bool {
+	_, ok := resources[crdName]
+	return ok
+}
+
+// AddProvisioningSyncer syncs the lifecycle of the clusters which are provisioned, upgraded or destroyed by the
+// resource of the CRD, it should be invoked only if the CRD is installed on the managed hub
+func AddProvisioningSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer, crdName string,
+) error {
+	res, ok := resources[crdName]
+	if !ok {
+		return fmt.Errorf("the CRD %s isn't a provisioning resource", crdName)
+	}
+	if addedSyncers[crdName] {
+		return nil
+	}
+
+	gvk := res.gvk
+	emitter := emitters.NewObjectEmitter(
+		res.eventType,
+		p,
+		emitters.WithPredicateFunc(predicate.ResourceVersionChangedPredicate{}),
+		emitters.WithTweakFunc(tweakFunc(res)),
+	)
+
+	if err := generic.AddSyncCtrl(mgr, res.name, func() client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj
+	}, emitter); err != nil {
+		return err
+	}
+
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := mgr.GetClient().List(ctx, list); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: emitter,
+	})
+	log.Infof("added the %s syncer", res.name)
+
+	addedSyncers[crdName] = true
+	return nil
+}
+
+// tweakFunc keeps only the fields of the resource which are used to derive the phase of the provisioning
+func tweakFunc(res provisioningResource) func(client.Object) {
+	return func(object client.Object) {
+		obj, ok := object.(*unstructured.Unstructured)
+		if !ok {
+			log.Errorf("wrong instance passed to tweak function, not a %s: %v", res.gvk.Kind, object)
+			return
+		}
+		utils.SlimUnstructured(obj, res.fields...)
+	}
+}
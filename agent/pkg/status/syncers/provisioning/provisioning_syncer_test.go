@@ -0,0 +1,69 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClusterDeploymentTweakFunc(t *testing.T) {
+	deletion := metav1.Now()
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "hive.openshift.io/v1",
+		"kind":       "ClusterDeployment",
+		"metadata": map[string]any{
+			"name":      "sno1",
+			"namespace": "sno1",
+			"uid":       "1",
+			"annotations": map[string]any{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		"spec": map[string]any{
+			"clusterName": "sno1",
+			"baseDomain":  "example.com",
+			"installed":   true,
+			"pullSecretRef": map[string]any{
+				"name": "pull-secret",
+			},
+			"clusterInstallRef": map[string]any{
+				"group": "extensions.hive.openshift.io", "kind": "AgentClusterInstall", "name": "sno1", "version": "v1beta1",
+			},
+		},
+		"status": map[string]any{
+			"installedTimestamp": "2026-10-01T10:00:00Z",
+			"conditions": []any{
+				map[string]any{"type": "Provisioned", "status": "True", "reason": "ProvisionedSucceeded"},
+			},
+			"webConsoleURL": "https://console.sno1.example.com",
+		},
+	}}
+	obj.SetDeletionTimestamp(&deletion)
+
+	tweakFunc(resources["clusterdeployments.hive.openshift.io"])(obj)
+
+	assert.Equal(t, "sno1", obj.GetName())
+	assert.Equal(t, "1", string(obj.GetUID()))
+	assert.NotNil(t, obj.GetDeletionTimestamp())
+	assert.Empty(t, obj.GetAnnotations())
+
+	installed, _, _ := unstructured.NestedBool(obj.Object, "spec", "installed")
+	assert.True(t, installed)
+	_, found, _ := unstructured.NestedMap(obj.Object, "spec", "clusterInstallRef")
+	assert.True(t, found)
+	_, found, _ = unstructured.NestedMap(obj.Object, "spec", "pullSecretRef")
+	assert.False(t, found)
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	assert.Len(t, conditions, 1)
+	_, found, _ = unstructured.NestedString(obj.Object, "status", "webConsoleURL")
+	assert.False(t, found)
+}
+
+func TestIsProvisioningCRD(t *testing.T) {
+	assert.True(t, IsProvisioningCRD("clusterdeployments.hive.openshift.io"))
+	assert.True(t, IsProvisioningCRD("clusterinstances.siteconfig.open-cluster-management.io"))
+	assert.True(t, IsProvisioningCRD("clustercurators.cluster.open-cluster-management.io"))
+	assert.False(t, IsProvisioningCRD("applications.argoproj.io"))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -22,18 +23,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/antientropy"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/controllers"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/delivery"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/ha"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/remediation"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/resync"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	dbmigration "github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
@@ -62,6 +71,7 @@ func parseFlags() *configs.ManagerConfig {
 		},
 		StatisticsConfig: &statistics.StatisticsConfig{},
 		ElectionConfig:   &commonobjects.LeaderElectionConfig{},
+		TracingConfig:    &tracing.Config{},
 		LaunchJobNames:   "",
 	}
 
@@ -95,13 +105,38 @@ func parseFlags() *configs.ManagerConfig {
 	pflag.IntVar(&managerConfig.ElectionConfig.RetryPeriod, "retry-period", 26, "controller leader retry period")
 	pflag.IntVar(&managerConfig.DatabaseConfig.DataRetention, "data-retention", 18,
 		"data retention indicates how many months the expired data will kept in the database")
+	pflag.IntVar(&managerConfig.DatabaseConfig.EventRetention, "event-retention", 0,
+		"event retention indicates how many months the event partitions will kept in the database, "+
+			"fall back to the data-retention if it isn't specified")
+	pflag.IntVar(&managerConfig.DatabaseConfig.HistoryRetention, "history-retention", 0,
+		"history retention indicates how many months the history partitions will kept in the database, "+
+			"fall back to the data-retention if it isn't specified")
+	pflag.IntVar(&managerConfig.DatabaseConfig.SoftDeletedRetention, "soft-deleted-retention", 0,
+		"soft deleted retention indicates how many months the soft deleted records will kept in the database, "+
+			"fall back to the data-retention if it isn't specified")
+	pflag.StringSliceVar(&managerConfig.DatabaseConfig.ReplicaDatabaseURLs, "replica-database-urls", nil,
+		"The URLs of the database read replicas, the read-only queries are routed to them, separated by comma.")
+	pflag.DurationVar(&managerConfig.DatabaseConfig.ReplicaCheckInterval, "replica-check-interval", 10*time.Second,
+		"The interval of the health check for the database read replicas.")
+	pflag.DurationVar(&managerConfig.DatabaseConfig.ReplicaMaxLag, "replica-max-lag", 30*time.Second,
+		"The maximum replay lag of a healthy read replica, the read-only queries fail over to the primary if "+
+			"the lag exceeds it.")
 	pflag.BoolVar(&managerConfig.WithACM, "with-acm", false,
 		"run on Red Hat Advanced Cluster Management")
 	pflag.BoolVar(&managerConfig.EnableInventoryAPI, "enable-inventory-api", false,
 		"enable the inventory api")
 	pflag.BoolVar(&managerConfig.EnablePprof, "enable-pprof", false, "enable the pprof tool")
+	pflag.StringVar(&managerConfig.ClusterConflictResolution, "cluster-conflict-resolution",
+		string(configs.ConflictResolutionNewest),
+		"the resolution of the cluster claimed by several managed hubs: newest, pinned or quarantine")
 	pflag.IntVar(&managerConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar(&managerConfig.TracingConfig.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC endpoint to export the spans of the events. Empty disables the exporting.")
+	pflag.BoolVar(&managerConfig.TracingConfig.Insecure, "tracing-insecure", false,
+		"Connect to the tracing endpoint without TLS.")
+	pflag.IntVar(&managerConfig.TracingConfig.SamplingPercentage, "tracing-sampling-percentage", 100,
+		"The percentage of the traces sampled to export.")
 	pflag.Parse()
 
 	pflag.Visit(func(f *pflag.Flag) {
@@ -174,6 +209,9 @@ func createManager(ctx context.Context,
 		return nil, fmt.Errorf("failed to add configmap controller to manager: %w", err)
 	}
 	configs.SetEnableInventoryAPI(managerConfig.EnableInventoryAPI)
+	if err = configs.SetClusterConflictResolution(managerConfig.ClusterConflictResolution); err != nil {
+		return nil, err
+	}
 	err = controller.NewTransportCtrl(
 		managerConfig.ManagerNamespace, constants.GHTransportConfigSecret,
 		transportCallback(mgr, managerConfig),
@@ -216,17 +254,45 @@ func transportCallback(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) c
 			return fmt.Errorf("failed to add transport-to-db syncers: %w", err)
 		}
 
+		// the spec events are audited and resent until they're acknowledged by the managed hubs, the migration and
+		// the anti-entropy repair are sent once since they have their own timeouts and retries
+		if err := delivery.AddDeliveryTrackerToManager(mgr); err != nil {
+			return fmt.Errorf("failed to add delivery tracker to manager - %w", err)
+		}
+		specProducer := func(initiator string, maxAttempts int, opts ...delivery.ProducerOption) transport.Producer {
+			return audit.NewProducer(delivery.NewProducer(producer, maxAttempts, opts...), initiator)
+		}
+
 		// add hub management
-		if err := hubmanagement.AddHubManagement(mgr, producer); err != nil {
+		if err := hubmanagement.AddHubManagement(mgr, specProducer("hub-management", 3)); err != nil {
 			return fmt.Errorf("failed to add hubmanagement to manager - %w", err)
 		}
 
 		// add managedClusterMigration controller
-		if err := migration.AddMigrationToManager(mgr, producer, managerConfig); err != nil {
+		if err := migration.AddMigrationToManager(mgr, specProducer("migration", 1), managerConfig); err != nil {
 			return fmt.Errorf("failed to add migration controller to manager - %w", err)
 		}
 
-		if err := ha.AddToManager(mgr, producer); err != nil {
+		// add globalHubResync controller
+		// the pending resync of the hub is only superseded by the newer one of the same event types
+		resyncProducer := specProducer("resync", 3, delivery.WithSupersedeKey(func(evt cloudevents.Event) string {
+			return evt.Type() + "/" + string(evt.Data())
+		}))
+		if err := resync.AddResyncToManager(mgr, resyncProducer); err != nil {
+			return fmt.Errorf("failed to add resync controller to manager - %w", err)
+		}
+
+		// add policyRemediationRequest controller
+		if err := remediation.AddRemediationToManager(mgr, specProducer("policy-remediation", 1)); err != nil {
+			return fmt.Errorf("failed to add remediation controller to manager - %w", err)
+		}
+
+		// add the anti-entropy between the managed hubs and the database
+		if err := antientropy.AddAntiEntropyToManager(mgr, specProducer("anti-entropy", 1)); err != nil {
+			return fmt.Errorf("failed to add anti-entropy to manager - %w", err)
+		}
+
+		if err := ha.AddToManager(mgr, specProducer("ha-config", 3)); err != nil {
 			return fmt.Errorf("failed to add HA config controller to manager - %w", err)
 		}
 		return nil
@@ -244,19 +310,37 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 		go utils.StartDefaultPprofServer()
 	}
 
+	// the spans continue the traces of the events sent by the agents
+	shutdownTracing, err := tracing.Init(ctx, "multicluster-global-hub-manager", managerConfig.TracingConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init the tracing: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.DefaultZapLogger().Warnw("failed to flush the spans", "error", err)
+		}
+	}()
+
 	utils.PrintRuntimeInfo()
 	databaseConfig := &database.DatabaseConfig{
 		URL:        managerConfig.DatabaseConfig.ProcessDatabaseURL,
 		Dialect:    database.PostgresDialect,
 		CaCertPath: managerConfig.DatabaseConfig.CACertPath,
 		PoolSize:   managerConfig.DatabaseConfig.MaxOpenConns,
+
+		ReplicaURLs: managerConfig.DatabaseConfig.ReplicaDatabaseURLs,
 	}
 	// Init the default gorm instance, it's used to sync data to db
-	err := database.InitGormInstance(databaseConfig)
+	err = database.InitGormInstance(databaseConfig)
 	if err != nil {
 		return fmt.Errorf("failed to initialize GORM instance %w", err)
 	}
 	defer database.CloseGorm(database.GetSqlDb())
+	defer database.CloseReplicas()
+	go database.StartReplicaHealthCheck(ctx, managerConfig.DatabaseConfig.ReplicaCheckInterval,
+		managerConfig.DatabaseConfig.ReplicaMaxLag)
 
 	// Init the backup gorm instance, it's used to add lock when backup database
 	_, sqlBackupConn, err := database.NewGormConn(databaseConfig)
@@ -283,6 +367,27 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 
 func main() {
 	defer func() { _ = logger.CoreZapLogger().Sync() }()
+	// restore the archived partition instead of running the manager
+	if len(os.Args) > 1 && os.Args[1] == archive.RestoreCommand {
+		if err := archive.Restore(ctrl.SetupSignalHandler(), os.Args[2:]); err != nil {
+			logger.DefaultZapLogger().Panicf("failed to restore the archive: %v", err)
+		}
+		return
+	}
+	// migrate the database manually instead of running the manager
+	if len(os.Args) > 1 && os.Args[1] == dbmigration.Command {
+		if err := dbmigration.Run(ctrl.SetupSignalHandler(), os.Args[2:]); err != nil {
+			logger.DefaultZapLogger().Panicf("failed to migrate the database: %v", err)
+		}
+		return
+	}
+	// export the audited actions instead of running the manager
+	if len(os.Args) > 1 && os.Args[1] == audit.ExportCommand {
+		if err := audit.Export(ctrl.SetupSignalHandler(), os.Args[2:]); err != nil {
+			logger.DefaultZapLogger().Panicf("failed to export the audited actions: %v", err)
+		}
+		return
+	}
 	if err := doMain(ctrl.SetupSignalHandler(), ctrl.GetConfigOrDie()); err != nil {
 		logger.DefaultZapLogger().Panicf("failed to run the main: %v", err)
 	}
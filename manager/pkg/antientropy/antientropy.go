@@ -0,0 +1,170 @@
+package antientropy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	log      = logger.DefaultZapLogger()
+	producer transport.Producer
+
+	MismatchedBucketsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_antientropy_mismatched_buckets_total",
+			Help: "The number of the buckets whose objects are different between the managed hub and the database.",
+		},
+		[]string{
+			"hub",  // The name of the managed hub.
+			"type", // The event type of the objects.
+		},
+	)
+)
+
+// source locates the rows of an event type in the database, the version is the sql expression of the version which
+// is hashed by the managed hub, and the kind is the value of the kind column if the table is shared by event types
+type source struct {
+	model   any
+	id      string
+	version string
+	kind    string
+}
+
+const (
+	resourceVersion = "payload->'metadata'->>'resourceVersion'"
+	generation      = "payload->'metadata'->>'generation'"
+)
+
+var sources = map[enum.EventType]source{
+	enum.ManagedClusterType:  {model: &models.ManagedCluster{}, id: "cluster_id", version: resourceVersion},
+	enum.LocalPolicySpecType: {model: &models.LocalSpecPolicy{}, id: "policy_id", version: generation},
+	enum.ApplicationType:     {model: &models.Application{}, id: "id", version: resourceVersion},
+	enum.ApplicationSetType:  {model: &models.ApplicationSet{}, id: "id", version: resourceVersion},
+
+	enum.LocalPlacementType:           {model: &models.LocalPlacement{}, id: "id", version: resourceVersion, kind: "Placement"},
+	enum.LocalPlacementDecisionType:   {model: &models.LocalPlacement{}, id: "id", version: resourceVersion, kind: "PlacementDecision"},
+	enum.ManagedClusterSetType:        {model: &models.LocalPlacement{}, id: "id", version: resourceVersion, kind: "ManagedClusterSet"},
+	enum.ManagedClusterSetBindingType: {model: &models.LocalPlacement{}, id: "id", version: resourceVersion, kind: "ManagedClusterSetBinding"},
+
+	enum.ClusterDeploymentType: {model: &models.ClusterProvisioning{}, id: "id", version: resourceVersion, kind: "ClusterDeployment"},
+	enum.ClusterInstanceType:   {model: &models.ClusterProvisioning{}, id: "id", version: resourceVersion, kind: "ClusterInstance"},
+	enum.ClusterCuratorType:    {model: &models.ClusterProvisioning{}, id: "id", version: resourceVersion, kind: "ClusterCurator"},
+}
+
+// row is the digest of a row in the database, the namespace and name are used by the managed hub to delete the
+// objects which don't exist anymore
+type row struct {
+	ID        string `gorm:"column:id"`
+	Version   string `gorm:"column:version"`
+	Namespace string `gorm:"column:namespace"`
+	Name      string `gorm:"column:name"`
+}
+
+// listRows returns the rows of the managed hub for the event type, the soft deleted rows are excluded. The rows are
+// read from the replicas, a row behind the primary is only repaired once more by the managed hub
+var listRows = func(leafHubName string, src source) ([]row, error) {
+	tx := database.GetReadGorm().Model(src.model).
+		Select(fmt.Sprintf("%s AS id, COALESCE(%s, '') AS version, "+
+			"COALESCE(payload->'metadata'->>'namespace', '') AS namespace, "+
+			"COALESCE(payload->'metadata'->>'name', '') AS name", src.id, src.version)).
+		Where("leaf_hub_name = ?", leafHubName)
+	if src.kind != "" {
+		tx = tx.Where("kind = ?", src.kind)
+	}
+	var rows []row
+	if err := tx.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// AddAntiEntropyToManager sets the producer to request the repairs and registers the metrics
+func AddAntiEntropyToManager(mgr ctrl.Manager, p transport.Producer) error {
+	if producer != nil {
+		return nil
+	}
+	producer = p
+	metrics.Registry.MustRegister(MismatchedBucketsCounter)
+	return nil
+}
+
+// Compare compares the bucket hashes of the managed hub with the rows in the database, it returns the repair of the
+// mismatched buckets, or nil if the buckets are matched or the event type isn't supported
+func Compare(leafHubName string, digest *antientropy.DigestBundle) (*antientropy.RepairBundle, error) {
+	src, ok := sources[enum.EventType(digest.EventType)]
+	if !ok || digest.BucketCount <= 0 {
+		return nil, nil
+	}
+
+	rows, err := listRows(leafHubName, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the rows of %s - %w", digest.EventType, err)
+	}
+	digests := make([]antientropy.ObjectDigest, 0, len(rows))
+	for _, r := range rows {
+		digests = append(digests, antientropy.ObjectDigest{ID: r.ID, Version: r.Version})
+	}
+
+	mismatched := antientropy.MismatchedBuckets(digest.Buckets,
+		antientropy.BucketHashes(digests, digest.BucketCount))
+	if len(mismatched) == 0 {
+		return nil, nil
+	}
+
+	inBuckets := map[int]bool{}
+	for _, bucket := range mismatched {
+		inBuckets[bucket] = true
+	}
+	repair := &antientropy.RepairBundle{
+		EventType:   digest.EventType,
+		BucketCount: digest.BucketCount,
+		Buckets:     mismatched,
+	}
+	for _, r := range rows {
+		if inBuckets[antientropy.BucketOf(r.ID, digest.BucketCount)] {
+			repair.Objects = append(repair.Objects, generic.ObjectMetadata{
+				ID:        r.ID,
+				Namespace: r.Namespace,
+				Name:      r.Name,
+			})
+		}
+	}
+	return repair, nil
+}
+
+// RequestRepair requests the managed hub to resend the objects of the mismatched buckets
+func RequestRepair(ctx context.Context, leafHubName string, repair *antientropy.RepairBundle) error {
+	if producer == nil {
+		return fmt.Errorf("the producer of the anti-entropy isn't initialized")
+	}
+	MismatchedBucketsCounter.WithLabelValues(leafHubName, enum.ShortenEventType(repair.EventType)).
+		Add(float64(len(repair.Buckets)))
+
+	payloadBytes, err := json.Marshal(repair)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the repair of %s - %w", repair.EventType, err)
+	}
+	e := utils.ToCloudEvent(constants.AntiEntropyRepairMsgKey, constants.CloudEventGlobalHubClusterName, leafHubName,
+		payloadBytes)
+	if err := producer.SendEvent(ctx, e); err != nil {
+		return fmt.Errorf("failed to send the repair to the hub %s - %w", leafHubName, err)
+	}
+	log.Infow("requested the managed hub to repair the mismatched buckets", "hub", leafHubName,
+		"type", enum.ShortenEventType(repair.EventType), "buckets", len(repair.Buckets))
+	return nil
+}
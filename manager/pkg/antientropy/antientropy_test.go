@@ -0,0 +1,61 @@
+package antientropy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestCompare(t *testing.T) {
+	rows := []row{
+		{ID: "uid1", Version: "1", Namespace: "ns", Name: "app1"},
+		{ID: "uid2", Version: "2", Namespace: "ns", Name: "app2"},
+	}
+	var listed source
+	listRows = func(leafHubName string, src source) ([]row, error) {
+		listed = src
+		return rows, nil
+	}
+
+	bucketCount := 4
+	matched := antientropy.BucketHashes([]antientropy.ObjectDigest{
+		{ID: "uid1", Version: "1"}, {ID: "uid2", Version: "2"},
+	}, bucketCount)
+
+	// the buckets are matched
+	repair, err := Compare("hub1", &antientropy.DigestBundle{
+		EventType: string(enum.ApplicationType), BucketCount: bucketCount, Buckets: matched,
+	})
+	require.NoError(t, err)
+	require.Nil(t, repair)
+	require.IsType(t, &models.Application{}, listed.model)
+
+	// the version of uid2 is changed in the managed hub
+	changed := antientropy.BucketHashes([]antientropy.ObjectDigest{
+		{ID: "uid1", Version: "1"}, {ID: "uid2", Version: "3"},
+	}, bucketCount)
+	repair, err = Compare("hub1", &antientropy.DigestBundle{
+		EventType: string(enum.ApplicationType), BucketCount: bucketCount, Buckets: changed,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, repair)
+	bucket := antientropy.BucketOf("uid2", bucketCount)
+	require.Equal(t, []int{bucket}, repair.Buckets)
+	ids := []string{}
+	for _, obj := range repair.Objects {
+		require.Equal(t, bucket, antientropy.BucketOf(obj.ID, bucketCount))
+		ids = append(ids, obj.ID)
+	}
+	require.Contains(t, ids, "uid2")
+
+	// the event type isn't supported
+	repair, err = Compare("hub1", &antientropy.DigestBundle{
+		EventType: string(enum.ManagedClusterAddOnType), BucketCount: bucketCount, Buckets: changed,
+	})
+	require.NoError(t, err)
+	require.Nil(t, repair)
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/pflag"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ExportCommand is the subcommand of the manager to export the actions for the change management, e.g.
+// "manager export-audit --since 720h --hub hub1 --format csv > actions.csv"
+const ExportCommand = "export-audit"
+
+var csvHeader = []string{
+	"id", "leaf_hub_name", "event_type", "initiator", "correlation_id", "payload_hash", "payload_size", "result",
+	"message", "created_at", "acknowledged_at",
+}
+
+// Export writes the actions which are created within the duration to the stdout, in the format of csv or json lines
+func Export(ctx context.Context, args []string) error {
+	var databaseURL, caCertPath, hub, format string
+	var since time.Duration
+	flags := pflag.NewFlagSet(ExportCommand, pflag.ContinueOnError)
+	flags.StringVar(&databaseURL, "database-url", os.Getenv("DATABASE_URL"), "The URL of database server")
+	flags.StringVar(&caCertPath, "postgres-ca-path", "/postgres-credential/ca.crt",
+		"The path of CA certificate for the database server")
+	flags.DurationVar(&since, "since", 30*24*time.Hour, "Export the actions created within the duration")
+	flags.StringVar(&hub, "hub", "", "Export the actions of the managed hub only")
+	flags.StringVar(&format, "format", "csv", "The format of the exported actions: csv or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if databaseURL == "" {
+		return fmt.Errorf("the database-url is required to export the actions")
+	}
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("invalid format %q, it must be csv or json", format)
+	}
+
+	cert, err := os.ReadFile(caCertPath) // #nosec G304
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read the database ca cert: %w", err)
+	}
+	conn, err := database.PostgresConnection(ctx, databaseURL, cert)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	actions, err := listActions(ctx, conn, time.Now().Add(-since), hub)
+	if err != nil {
+		return err
+	}
+	return writeActions(os.Stdout, format, actions)
+}
+
+func listActions(ctx context.Context, conn *pgx.Conn, since time.Time, hub string) ([]models.AuditAction, error) {
+	rows, err := conn.Query(ctx, `SELECT id, leaf_hub_name, event_type, initiator, COALESCE(correlation_id, ''),
+		payload_hash, payload_size, result, COALESCE(message, ''), created_at, acknowledged_at
+		FROM audit.actions WHERE created_at >= $1 AND ($2 = '' OR leaf_hub_name = $2) ORDER BY created_at`,
+		since, hub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the actions: %w", err)
+	}
+	actions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.AuditAction, error) {
+		a := models.AuditAction{}
+		err := row.Scan(&a.ID, &a.LeafHubName, &a.EventType, &a.Initiator, &a.CorrelationID, &a.PayloadHash,
+			&a.PayloadSize, &a.Result, &a.Message, &a.CreatedAt, &a.AcknowledgedAt)
+		return a, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the actions: %w", err)
+	}
+	return actions, nil
+}
+
+func writeActions(w io.Writer, format string, actions []models.AuditAction) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		for i := range actions {
+			if err := encoder.Encode(&actions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, a := range actions {
+		acknowledgedAt := ""
+		if a.AcknowledgedAt != nil {
+			acknowledgedAt = a.AcknowledgedAt.Format(time.RFC3339)
+		}
+		err := writer.Write([]string{
+			a.ID, a.LeafHubName, a.EventType, a.Initiator, a.CorrelationID, a.PayloadHash,
+			strconv.Itoa(a.PayloadSize), a.Result, a.Message, a.CreatedAt.Format(time.RFC3339), acknowledgedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,225 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// the results of the actions
+const (
+	ResultSent         = "sent"
+	ResultFailed       = "failed"
+	ResultAcknowledged = "acknowledged"
+	ResultRejected     = "rejected"
+	ResultExpired      = "expired"
+	ResultDenied       = "denied"
+	ResultSuperseded   = "superseded"
+)
+
+var log = logger.DefaultZapLogger()
+
+type initiatorKey struct{}
+
+// WithInitiator sets the initiator of the actions sent with the context, e.g. the object which triggers them
+func WithInitiator(ctx context.Context, initiator string) context.Context {
+	return context.WithValue(ctx, initiatorKey{}, initiator)
+}
+
+// InitiatorOf returns the initiator of the actions triggered by the object, e.g. "GlobalHubResync/ns/name"
+func InitiatorOf(kind string, obj metav1.Object) string {
+	return fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
+
+// MigrationCorrelationID correlates the migration event of the stage with the status reported by the managed hub
+func MigrationCorrelationID(migrationId, stage string) string {
+	return migrationId + "/" + stage
+}
+
+// Producer records the spec events sent to the managed hubs into the audit.actions, the initiator is the component
+// which sends the events unless it's set by the context
+type Producer struct {
+	transport.Producer
+	initiator string
+}
+
+func NewProducer(producer transport.Producer, initiator string) *Producer {
+	return &Producer{Producer: producer, initiator: initiator}
+}
+
+func (p *Producer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if evt.ID() == "" {
+		evt.SetID(uuid.New().String())
+	}
+	action := toAction(ctx, evt, p.initiator)
+
+	// the action is recorded before it's sent, so that the acknowledgement of the managed hub always finds it even if
+	// it arrives before the send returns. The action is sent even if it can't be recorded, the record shouldn't block
+	// the manager
+	if err := record(action); err != nil {
+		log.Warnw("failed to record the action", "id", action.ID, "type", action.EventType,
+			"hub", action.LeafHubName, "error", err)
+	}
+
+	sendErr := p.Producer.SendEvent(ctx, evt)
+	if sendErr != nil {
+		action.Result = ResultFailed
+		action.Message = sendErr.Error()
+		if err := recordFailure(action); err != nil {
+			log.Warnw("failed to record the failed action", "id", action.ID, "type", action.EventType,
+				"hub", action.LeafHubName, "error", err)
+		}
+	}
+	return sendErr
+}
+
+func toAction(ctx context.Context, evt cloudevents.Event, initiator string) *models.AuditAction {
+	if value, ok := ctx.Value(initiatorKey{}).(string); ok && value != "" {
+		initiator = value
+	}
+	hash := sha256.Sum256(evt.Data())
+	return &models.AuditAction{
+		ID:            evt.ID(),
+		LeafHubName:   evt.Subject(),
+		EventType:     evt.Type(),
+		Initiator:     initiator,
+		CorrelationID: correlationID(evt),
+		PayloadHash:   hex.EncodeToString(hash[:]),
+		PayloadSize:   len(evt.Data()),
+		Result:        ResultSent,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// correlationID returns the id to correlate the event with the acknowledgement of the managed hub
+func correlationID(evt cloudevents.Event) string {
+	extensions := evt.Extensions()
+	if resyncId, err := types.ToString(extensions[constants.CloudEventExtensionKeyResyncId]); err == nil {
+		return resyncId
+	}
+	if remediationId, err := types.ToString(extensions[constants.CloudEventExtensionKeyRemediationId]); err == nil {
+		return remediationId
+	}
+	migrationId, err := types.ToString(extensions[constants.CloudEventExtensionKeyMigrationId])
+	if err != nil {
+		return ""
+	}
+	stage, _ := types.ToString(extensions[constants.CloudEventExtensionKeyMigrationStage])
+	return MigrationCorrelationID(migrationId, stage)
+}
+
+var record = func(action *models.AuditAction) error {
+	db := database.GetGorm()
+	if db == nil {
+		return fmt.Errorf("db is nil")
+	}
+	return db.Create(action).Error
+}
+
+// recordFailure marks the recorded action as failed to send, the action is still sent if it isn't updated by others
+var recordFailure = func(action *models.AuditAction) error {
+	db := database.GetGorm()
+	if db == nil {
+		return fmt.Errorf("db is nil")
+	}
+	return db.Model(&models.AuditAction{}).
+		Where("leaf_hub_name = ? AND id = ? AND result = ?", action.LeafHubName, action.ID, ResultSent).
+		Updates(map[string]any{"result": action.Result, "message": action.Message}).Error
+}
+
+// Acknowledge updates the results of the actions correlated with the acknowledgement of the managed hub, the
+// correlation id is either the correlation id or the id of the action. The actions are rejected if the managed hub
+// reports the error message, and the rejected actions aren't acknowledged again
+func Acknowledge(leafHubName, correlationID, errMessage string) error {
+	if correlationID == "" {
+		return nil
+	}
+	result := ResultAcknowledged
+	if errMessage != "" {
+		result = ResultRejected
+	}
+	db := database.GetGorm()
+	if db == nil {
+		return fmt.Errorf("db is nil")
+	}
+	err := db.Model(&models.AuditAction{}).
+		Where("leaf_hub_name = ? AND (correlation_id = ? OR id = ?) AND result IN ?", leafHubName, correlationID,
+			correlationID, []string{ResultSent, ResultAcknowledged}).
+		Updates(map[string]any{"result": result, "message": errMessage, "acknowledged_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge the actions %s of the hub %s - %w", correlationID, leafHubName, err)
+	}
+	return nil
+}
+
+// Deny marks the actions correlated with the acknowledgement as denied by the RBAC of the managed hub. The denied
+// events which aren't sent by the manager, e.g. the Hub HA resources sent by the active hub, are recorded as the new
+// actions initiated by the source of the events
+func Deny(leafHubName string, ack *specack.SpecAckBundle) error {
+	db := database.GetGorm()
+	if db == nil {
+		return fmt.Errorf("db is nil")
+	}
+	now := time.Now()
+	ret := db.Model(&models.AuditAction{}).
+		Where("leaf_hub_name = ? AND (correlation_id = ? OR id = ?) AND result IN ?", leafHubName, ack.CorrelationID,
+			ack.CorrelationID, []string{ResultSent, ResultAcknowledged}).
+		Updates(map[string]any{"result": ResultDenied, "message": ack.Error, "acknowledged_at": now})
+	if ret.Error != nil {
+		return fmt.Errorf("failed to deny the actions %s of the hub %s - %w", ack.CorrelationID, leafHubName,
+			ret.Error)
+	}
+	if ret.RowsAffected > 0 {
+		return nil
+	}
+	return record(&models.AuditAction{
+		ID:             uuid.New().String(),
+		LeafHubName:    leafHubName,
+		EventType:      ack.EventType,
+		Initiator:      ack.Source,
+		CorrelationID:  ack.CorrelationID,
+		Result:         ResultDenied,
+		Message:        ack.Error,
+		CreatedAt:      now,
+		AcknowledgedAt: &now,
+	})
+}
+
+// Expire marks the action which isn't acknowledged by the managed hub in time
+func Expire(leafHubName, id string) error {
+	return updateUnacknowledged(leafHubName, id, ResultExpired)
+}
+
+// Supersede marks the action which isn't acknowledged before a newer action of the same state is sent, it isn't
+// resent anymore
+func Supersede(leafHubName, id string) error {
+	return updateUnacknowledged(leafHubName, id, ResultSuperseded)
+}
+
+func updateUnacknowledged(leafHubName, id, result string) error {
+	db := database.GetGorm()
+	if db == nil {
+		return fmt.Errorf("db is nil")
+	}
+	err := db.Model(&models.AuditAction{}).
+		Where("leaf_hub_name = ? AND id = ? AND result = ?", leafHubName, id, ResultSent).
+		Update("result", result).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark the action %s of the hub %s as %s - %w", id, leafHubName, result, err)
+	}
+	return nil
+}
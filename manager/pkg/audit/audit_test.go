@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+type fakeProducer struct {
+	events []cloudevents.Event
+	err    error
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return p.err
+}
+
+func (p *fakeProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func TestProducer(t *testing.T) {
+	var recorded []*models.AuditAction
+	record = func(action *models.AuditAction) error {
+		// copy the action, so that the recorded result isn't changed by the failure
+		copied := *action
+		recorded = append(recorded, &copied)
+		return nil
+	}
+	var failed []*models.AuditAction
+	recordFailure = func(action *models.AuditAction) error {
+		failed = append(failed, action)
+		return nil
+	}
+
+	fake := &fakeProducer{}
+	producer := NewProducer(fake, "resync")
+
+	// the initiator is the component
+	evt := utils.ToCloudEvent(constants.ResyncMsgKey, constants.CloudEventGlobalHubClusterName, "hub1", []byte("[]"))
+	require.NoError(t, producer.SendEvent(context.Background(), evt))
+	require.Len(t, recorded, 1)
+	require.NotEmpty(t, recorded[0].ID)
+	require.Equal(t, fake.events[0].ID(), recorded[0].ID)
+	require.Equal(t, "hub1", recorded[0].LeafHubName)
+	require.Equal(t, constants.ResyncMsgKey, recorded[0].EventType)
+	require.Equal(t, "resync", recorded[0].Initiator)
+	require.Equal(t, ResultSent, recorded[0].Result)
+	require.Len(t, recorded[0].PayloadHash, 64)
+	require.Empty(t, recorded[0].CorrelationID)
+
+	// the initiator is from the context, and the resync is correlated with the acknowledgement
+	evt.SetID("")
+	evt.SetExtension(constants.CloudEventExtensionKeyResyncId, "resync-uid")
+	ctx := WithInitiator(context.Background(), "GlobalHubResync/ns/resync")
+	require.NoError(t, producer.SendEvent(ctx, evt))
+	require.Len(t, recorded, 2)
+	require.Equal(t, "GlobalHubResync/ns/resync", recorded[1].Initiator)
+	require.Equal(t, "resync-uid", recorded[1].CorrelationID)
+	require.Equal(t, recorded[0].PayloadHash, recorded[1].PayloadHash)
+
+	require.Empty(t, failed)
+
+	// the action is recorded as sent before it's sent, then it's updated to failed
+	fake.err = errors.New("broker is down")
+	migrationEvt := utils.ToMigrationEvent("migration", constants.CloudEventGlobalHubClusterName, "hub2",
+		"migration-uid", "Initializing", time.Minute, []byte("{}"))
+	require.Error(t, producer.SendEvent(context.Background(), migrationEvt))
+	require.Len(t, recorded, 3)
+	require.Equal(t, ResultSent, recorded[2].Result)
+	require.Equal(t, MigrationCorrelationID("migration-uid", "Initializing"), recorded[2].CorrelationID)
+	require.Len(t, failed, 1)
+	require.Equal(t, recorded[2].ID, failed[0].ID)
+	require.Equal(t, ResultFailed, failed[0].Result)
+	require.Equal(t, "broker is down", failed[0].Message)
+}
+
+func TestWriteActions(t *testing.T) {
+	acknowledgedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	actions := []models.AuditAction{
+		{
+			ID: "id1", LeafHubName: "hub1", EventType: "Resync", Initiator: "resync", CorrelationID: "uid",
+			PayloadHash: "hash", PayloadSize: 2, Result: ResultAcknowledged,
+			CreatedAt: acknowledgedAt.Add(-time.Minute), AcknowledgedAt: &acknowledgedAt,
+		},
+		{
+			ID: "id2", LeafHubName: "hub2", EventType: "Resync", Initiator: "resync", PayloadHash: "hash",
+			PayloadSize: 2, Result: ResultFailed, Message: "broker, is down", CreatedAt: acknowledgedAt,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeActions(buf, "csv", actions))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, strings.Join(csvHeader, ","), lines[0])
+	require.Equal(t, "id1,hub1,Resync,resync,uid,hash,2,acknowledged,,2026-01-02T03:03:05Z,2026-01-02T03:04:05Z",
+		lines[1])
+	require.Contains(t, lines[2], `"broker, is down"`)
+
+	buf.Reset()
+	require.NoError(t, writeActions(buf, "json", actions))
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"correlationId":"uid"`)
+	require.NotContains(t, lines[1], "acknowledgedAt")
+}
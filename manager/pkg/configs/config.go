@@ -4,10 +4,12 @@
 package configs
 
 import (
+	"fmt"
 	"time"
 
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -18,12 +20,15 @@ type ManagerConfig struct {
 	SyncerConfig       *SyncerConfig
 	DatabaseConfig     *DatabaseConfig
 	TransportConfig    *transport.TransportInternalConfig
+	TracingConfig      *tracing.Config
 	StatisticsConfig   *statistics.StatisticsConfig
 	ElectionConfig     *commonobjects.LeaderElectionConfig
 	EnableInventoryAPI bool
 	WithACM            bool
 	LaunchJobNames     string
 	EnablePprof        bool
+	// ClusterConflictResolution resolves the cluster which is claimed by several managed hubs
+	ClusterConflictResolution string
 }
 
 type SyncerConfig struct {
@@ -38,6 +43,15 @@ type DatabaseConfig struct {
 	CACertPath                 string
 	MaxOpenConns               int
 	DataRetention              int
+	// the retention months for the event tables, history tables and the soft deleted records,
+	// fall back to the DataRetention if they aren't specified
+	EventRetention       int
+	HistoryRetention     int
+	SoftDeletedRetention int
+	// the read replicas of the database, the read-only queries fail over to the primary if they are unhealthy
+	ReplicaDatabaseURLs  []string
+	ReplicaCheckInterval time.Duration
+	ReplicaMaxLag        time.Duration
 }
 
 var enableInventoryAPI bool
@@ -49,3 +63,33 @@ func IsInventoryAPIEnabled() bool {
 func SetEnableInventoryAPI(enable bool) {
 	enableInventoryAPI = enable
 }
+
+// ClusterConflictResolution is how the cluster claimed by several managed hubs is persisted into the database
+type ClusterConflictResolution string
+
+const (
+	// ConflictResolutionNewest persists the cluster of the managed hub which imported it last
+	ConflictResolutionNewest ClusterConflictResolution = "newest"
+	// ConflictResolutionPinned keeps the cluster of the managed hub which claimed it first
+	ConflictResolutionPinned ClusterConflictResolution = "pinned"
+	// ConflictResolutionQuarantine freezes the cluster and marks it with the conflict error until the conflict is
+	// resolved
+	ConflictResolutionQuarantine ClusterConflictResolution = "quarantine"
+)
+
+var clusterConflictResolution = ConflictResolutionNewest
+
+func GetClusterConflictResolution() ClusterConflictResolution {
+	return clusterConflictResolution
+}
+
+func SetClusterConflictResolution(resolution string) error {
+	switch r := ClusterConflictResolution(resolution); r {
+	case ConflictResolutionNewest, ConflictResolutionPinned, ConflictResolutionQuarantine:
+		clusterConflictResolution = r
+		return nil
+	default:
+		return fmt.Errorf("invalid cluster conflict resolution %q, it must be one of newest, pinned and quarantine",
+			resolution)
+	}
+}
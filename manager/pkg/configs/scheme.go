@@ -20,6 +20,8 @@ import (
 	authv1beta1 "open-cluster-management.io/managed-serviceaccount/apis/authentication/v1beta1"
 
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	resyncv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/resync/v1alpha1"
 )
 
 func GetRuntimeScheme() *runtime.Scheme {
@@ -32,6 +34,8 @@ func GetRuntimeScheme() *runtime.Scheme {
 	utilruntime.Must(policyv1.AddToScheme(scheme))
 	utilruntime.Must(mchv1.AddToScheme(scheme))
 	utilruntime.Must(migrationv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(resyncv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(remediationv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(authv1beta1.AddToScheme(scheme))
 	utilruntime.Must(klusterletv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(addonv1alpha1.Install(scheme))
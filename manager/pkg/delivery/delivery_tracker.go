@@ -0,0 +1,281 @@
+package delivery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// the results of the spec events
+const (
+	ResultAcknowledged = "acknowledged"
+	ResultFailed       = "failed"
+	ResultExpired      = "expired"
+	ResultSuperseded   = "superseded"
+)
+
+var (
+	log = logger.DefaultZapLogger()
+
+	// defaultTimeout is the duration to wait for the acknowledgement of each attempt, the expiry time of the event
+	// is used if it's set
+	defaultTimeout = 2 * time.Minute
+	checkInterval  = 10 * time.Second
+
+	tracker *Tracker
+	// expire marks the audited action of the expired spec event
+	expire = audit.Expire
+	// supersede marks the audited action of the spec event which is superseded by a newer one
+	supersede = audit.Supersede
+
+	SpecDeliveryCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_spec_delivery_total",
+			Help: "The number of the spec events sent to the managed hubs by the result.",
+		},
+		[]string{
+			"type",   // The type of the spec event.
+			"result", // The result of the spec event: acknowledged, failed, expired or superseded.
+		},
+	)
+)
+
+// request is the spec event which isn't acknowledged by the managed hub
+type request struct {
+	event       cloudevents.Event
+	producer    transport.Producer
+	attempts    int
+	maxAttempts int
+	deadline    time.Time
+	// supersedeKey identifies the state carried by the event, the pending event is superseded by a newer one with
+	// the same key for the same hub
+	supersedeKey string
+}
+
+// Tracker tracks the spec events until they're acknowledged by the managed hubs, the event which isn't acknowledged
+// within the timeout is resent until the max attempts, then it's expired
+type Tracker struct {
+	mu       sync.Mutex
+	requests map[string]*request // the key is the hub and the correlation id
+}
+
+func AddDeliveryTrackerToManager(mgr ctrl.Manager) error {
+	if tracker != nil {
+		return nil
+	}
+	t := &Tracker{requests: map[string]*request{}}
+	if err := mgr.Add(t); err != nil {
+		return err
+	}
+	metrics.Registry.MustRegister(SpecDeliveryCounter)
+	tracker = t
+	return nil
+}
+
+func (t *Tracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.check(ctx, time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leader resends the spec events
+func (t *Tracker) NeedLeaderElection() bool {
+	return true
+}
+
+// track tracks the spec event until it's acknowledged. The pending events with the same supersede key for the hub
+// are dropped, so the older state isn't resent after the newer one, e.g. an "inactive" hub status update isn't resent
+// after the "active" one. Only the resent events are superseded, since the events sent once aren't reordered.
+func (t *Tracker) track(evt cloudevents.Event, producer transport.Producer, maxAttempts int, supersedeKey string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if maxAttempts > 1 {
+		for key, req := range t.requests {
+			if req.maxAttempts <= 1 || req.event.Subject() != evt.Subject() || req.supersedeKey != supersedeKey {
+				continue
+			}
+			delete(t.requests, key)
+			SpecDeliveryCounter.WithLabelValues(req.event.Type(), ResultSuperseded).Inc()
+			log.Debugw("the pending spec event is superseded", "type", req.event.Type(), "hub", req.event.Subject(),
+				"correlationId", req.event.ID(), "newCorrelationId", evt.ID())
+			if err := supersede(req.event.Subject(), req.event.ID()); err != nil {
+				log.Warnw("failed to supersede the audited action", "correlationId", req.event.ID(), "error", err)
+			}
+		}
+	}
+	t.requests[requestKey(evt.Subject(), evt.ID())] = &request{
+		event:        evt,
+		producer:     producer,
+		attempts:     1,
+		maxAttempts:  maxAttempts,
+		deadline:     deadlineOf(evt, time.Now()),
+		supersedeKey: supersedeKey,
+	}
+}
+
+// check resends the spec events which aren't acknowledged before the deadline
+func (t *Tracker) check(ctx context.Context, now time.Time) {
+	t.mu.Lock()
+	resending := map[string]*request{}
+	for key, req := range t.requests {
+		if now.Before(req.deadline) {
+			continue
+		}
+		if req.attempts >= req.maxAttempts || isExpired(req.event, now) {
+			delete(t.requests, key)
+			SpecDeliveryCounter.WithLabelValues(req.event.Type(), ResultExpired).Inc()
+			log.Warnw("the spec event isn't acknowledged by the managed hub", "type", req.event.Type(),
+				"hub", req.event.Subject(), "correlationId", req.event.ID(), "attempts", req.attempts)
+			if err := expire(req.event.Subject(), req.event.ID()); err != nil {
+				log.Warnw("failed to expire the audited action", "correlationId", req.event.ID(), "error", err)
+			}
+			continue
+		}
+		req.attempts++
+		req.deadline = deadlineOf(req.event, now)
+		resending[key] = req
+	}
+	t.mu.Unlock()
+
+	// resend without the lock, the acknowledgement might be received in the meantime. The resends bypass the audit,
+	// the audited action of the first attempt is updated by the acknowledgement
+	for _, req := range resending {
+		log.Infow("resend the spec event to the managed hub", "type", req.event.Type(), "hub",
+			req.event.Subject(), "correlationId", req.event.ID(), "attempt", req.attempts)
+		if err := req.producer.SendEvent(ctx, req.event); err != nil {
+			log.Warnw("failed to resend the spec event", "type", req.event.Type(), "hub", req.event.Subject(),
+				"error", err)
+		}
+	}
+}
+
+// acknowledge stops tracking the spec event, it returns false if the event isn't tracked, e.g. it's acknowledged
+// by the former attempt
+func (t *Tracker) acknowledge(leafHubName string, ack *specack.SpecAckBundle) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := requestKey(leafHubName, ack.CorrelationID)
+	if _, ok := t.requests[key]; !ok {
+		return false
+	}
+	delete(t.requests, key)
+	return true
+}
+
+// Acknowledge handles the acknowledgement of the spec event from the managed hub
+func Acknowledge(leafHubName string, ack *specack.SpecAckBundle) {
+	if tracker == nil || !tracker.acknowledge(leafHubName, ack) {
+		return
+	}
+	result := ResultAcknowledged
+	if !ack.Success {
+		result = ResultFailed
+	}
+	SpecDeliveryCounter.WithLabelValues(ack.EventType, result).Inc()
+}
+
+// Producer sets the correlation id of the spec events and tracks them until they're acknowledged, the events sent
+// to all the managed hubs aren't tracked
+type Producer struct {
+	transport.Producer
+	maxAttempts  int
+	supersedeKey func(cloudevents.Event) string
+}
+
+type ProducerOption func(*Producer)
+
+// WithSupersedeKey sets the key of the state carried by the events, the pending event of the hub is superseded by a
+// newer one with the same key. It's the event type and the target of the event by default.
+func WithSupersedeKey(supersedeKey func(cloudevents.Event) string) ProducerOption {
+	return func(p *Producer) {
+		p.supersedeKey = supersedeKey
+	}
+}
+
+// NewProducer returns the producer which resends the unacknowledged spec events until the max attempts, the events
+// of the syncers which aren't idempotent should be sent only once
+func NewProducer(producer transport.Producer, maxAttempts int, opts ...ProducerOption) *Producer {
+	p := &Producer{
+		Producer:     producer,
+		maxAttempts:  maxAttempts,
+		supersedeKey: defaultSupersedeKey,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Producer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if evt.ID() == "" {
+		evt.SetID(uuid.New().String())
+	}
+	evt.SetExtension(constants.CloudEventExtensionKeyCorrelationId, evt.ID())
+	if err := p.Producer.SendEvent(ctx, evt); err != nil {
+		return err
+	}
+	if tracker != nil && evt.Subject() != transport.Broadcast {
+		tracker.track(evt, p.Producer, p.maxAttempts, p.supersedeKey(evt))
+	}
+	return nil
+}
+
+// defaultSupersedeKey returns the type and the target of the event, e.g. the hub status updates of the different
+// active hubs sent to the same standby hub don't supersede each other
+func defaultSupersedeKey(evt cloudevents.Event) string {
+	target, err := cetypes.ToString(evt.Extensions()[constants.CloudEventExtensionKeyTarget])
+	if err != nil {
+		return evt.Type()
+	}
+	return evt.Type() + "/" + target
+}
+
+func requestKey(leafHubName, correlationID string) string {
+	return leafHubName + "/" + correlationID
+}
+
+// deadlineOf returns the deadline of the attempt, it's the expiry time of the event if it's set, since the managed
+// hub drops the expired event
+func deadlineOf(evt cloudevents.Event, now time.Time) time.Time {
+	if expireTime, ok := expiryOf(evt); ok {
+		return expireTime
+	}
+	return now.Add(defaultTimeout)
+}
+
+func isExpired(evt cloudevents.Event, now time.Time) bool {
+	expireTime, ok := expiryOf(evt)
+	return ok && !now.Before(expireTime)
+}
+
+func expiryOf(evt cloudevents.Event) (time.Time, bool) {
+	expireStr, err := cetypes.ToString(evt.Extensions()[constants.CloudEventExtensionKeyExpireTime])
+	if err != nil {
+		return time.Time{}, false
+	}
+	expireTime, err := time.Parse(time.RFC3339, expireStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expireTime, true
+}
@@ -0,0 +1,144 @@
+package delivery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+type fakeProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *fakeProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	expired := []string{}
+	expire = func(leafHubName, id string) error {
+		expired = append(expired, leafHubName+"/"+id)
+		return nil
+	}
+	tracker = &Tracker{requests: map[string]*request{}}
+	defer func() { tracker = nil }()
+
+	fake := &fakeProducer{}
+	producer := NewProducer(fake, 2)
+	ctx := context.Background()
+
+	// the acknowledged event isn't resent
+	acked := utils.ToCloudEvent(constants.HAConfigMsgKey, constants.CloudEventGlobalHubClusterName, "hub1", []byte("{}"))
+	require.NoError(t, producer.SendEvent(ctx, acked))
+	require.Len(t, fake.events, 1)
+	correlationID := fake.events[0].Extensions()[constants.CloudEventExtensionKeyCorrelationId]
+	require.Equal(t, fake.events[0].ID(), correlationID)
+	Acknowledge("hub1", &specack.SpecAckBundle{CorrelationID: fake.events[0].ID(), Success: true})
+
+	// the unacknowledged event is resent until the max attempts
+	lost := utils.ToCloudEvent(constants.HAConfigMsgKey, constants.CloudEventGlobalHubClusterName, "hub2", []byte("{}"))
+	require.NoError(t, producer.SendEvent(ctx, lost))
+	require.Len(t, fake.events, 2)
+	lostID := fake.events[1].ID()
+
+	// the broadcast event isn't tracked
+	broadcast := utils.ToCloudEvent(constants.HAConfigMsgKey, constants.CloudEventGlobalHubClusterName,
+		transport.Broadcast, []byte("{}"))
+	require.NoError(t, producer.SendEvent(ctx, broadcast))
+	require.Len(t, fake.events, 3)
+
+	now := time.Now()
+	tracker.check(ctx, now)
+	require.Len(t, fake.events, 3)
+
+	tracker.check(ctx, now.Add(defaultTimeout+time.Second))
+	require.Len(t, fake.events, 4)
+	require.Equal(t, lostID, fake.events[3].ID())
+	require.Equal(t, "hub2", fake.events[3].Subject())
+
+	tracker.check(ctx, now.Add(2*defaultTimeout+2*time.Second))
+	require.Len(t, fake.events, 4)
+	require.Equal(t, []string{"hub2/" + lostID}, expired)
+	require.Empty(t, tracker.requests)
+}
+
+func TestTrackerSupersede(t *testing.T) {
+	superseded := []string{}
+	supersede = func(leafHubName, id string) error {
+		superseded = append(superseded, leafHubName+"/"+id)
+		return nil
+	}
+	tracker = &Tracker{requests: map[string]*request{}}
+	defer func() { tracker = nil }()
+
+	fake := &fakeProducer{}
+	hubStatusProducer := NewProducer(fake, 3)
+	resyncProducer := NewProducer(fake, 3, WithSupersedeKey(func(evt cloudevents.Event) string {
+		return evt.Type() + "/" + string(evt.Data())
+	}))
+	onceProducer := NewProducer(fake, 1)
+	ctx := context.Background()
+	sendTo := func(producer *Producer, eventType, hub, target, payload string) string {
+		evt := utils.ToCloudEvent(eventType, constants.CloudEventGlobalHubClusterName, hub, []byte(payload))
+		if target != "" {
+			evt.SetExtension(constants.CloudEventExtensionKeyTarget, target)
+		}
+		require.NoError(t, producer.SendEvent(ctx, evt))
+		return fake.events[len(fake.events)-1].ID()
+	}
+	send := func(producer *Producer, eventType, hub, payload string) string {
+		return sendTo(producer, eventType, hub, "", payload)
+	}
+
+	// the newer hub status update supersedes the unacknowledged older one of the same active hub
+	inactive := sendTo(hubStatusProducer, constants.HubStatusUpdateMsgKey, "hub1", "active1", `{"status":"inactive"}`)
+	hub2 := sendTo(hubStatusProducer, constants.HubStatusUpdateMsgKey, "hub2", "active1", `{"status":"inactive"}`)
+	other := sendTo(hubStatusProducer, constants.HubStatusUpdateMsgKey, "hub1", "active2", `{"status":"inactive"}`)
+	active := sendTo(hubStatusProducer, constants.HubStatusUpdateMsgKey, "hub1", "active1", `{"status":"active"}`)
+	require.Equal(t, []string{"hub1/" + inactive}, superseded)
+
+	// the resync of the other event types doesn't supersede the pending one
+	resync1 := send(resyncProducer, constants.ResyncMsgKey, "hub1", `["managedcluster"]`)
+	resync2 := send(resyncProducer, constants.ResyncMsgKey, "hub1", `["policy"]`)
+	resync3 := send(resyncProducer, constants.ResyncMsgKey, "hub1", `["policy"]`)
+	require.Equal(t, []string{"hub1/" + inactive, "hub1/" + resync2}, superseded)
+
+	// the events sent once aren't superseded
+	send(onceProducer, constants.PolicyRemediationMsgKey, "hub1", `{"id":"1"}`)
+	send(onceProducer, constants.PolicyRemediationMsgKey, "hub1", `{"id":"2"}`)
+	require.Len(t, superseded, 2)
+
+	sent := len(fake.events)
+	tracker.check(ctx, time.Now().Add(defaultTimeout+time.Second))
+	resent := []string{}
+	for _, evt := range fake.events[sent:] {
+		resent = append(resent, evt.ID())
+	}
+	require.ElementsMatch(t, []string{hub2, other, active, resync1, resync3}, resent)
+}
+
+func TestDeadlineOf(t *testing.T) {
+	now := time.Now()
+	evt := cloudevents.NewEvent()
+	require.Equal(t, now.Add(defaultTimeout), deadlineOf(evt, now))
+	require.False(t, isExpired(evt, now))
+
+	expiry := now.Add(time.Minute).Truncate(time.Second)
+	evt.SetExtension(constants.CloudEventExtensionKeyExpireTime, expiry.Format(time.RFC3339))
+	require.True(t, expiry.Equal(deadlineOf(evt, now)))
+	require.False(t, isExpired(evt, now))
+	require.True(t, isExpired(evt, expiry))
+}
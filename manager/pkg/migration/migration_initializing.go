@@ -18,6 +18,7 @@ import (
 	"open-cluster-management.io/managed-serviceaccount/apis/authentication/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
 	migrationbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/migration"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -203,6 +204,9 @@ func (m *ClusterMigrationController) sendEventToSourceHub(ctx context.Context, f
 	eventType := string(enum.ManagedClusterMigrationType)
 	evt := utils.ToMigrationEvent(eventType, constants.CloudEventGlobalHubClusterName, fromHub,
 		migrationId, stage, getTimeout(stage), payloadBytes)
+	// the managed hubs impersonate the requester to apply the migration if the RBAC is enforced
+	utils.SetUserIdentity(&evt, migration.GetAnnotations())
+	ctx = audit.WithInitiator(ctx, audit.InitiatorOf("ManagedClusterMigration", migration))
 	if err := m.SendEvent(ctx, evt); err != nil {
 		return fmt.Errorf("failed to sync managedclustermigration event(%s) from source(%s) to destination(%s) - %w",
 			eventType, constants.CloudEventGlobalHubClusterName, fromHub, err)
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const dateFormat = "2006-01-02"
+
+var log = logger.ZapLogger("partition-archiver")
+
+// Manifest describes an archived partition
+type Manifest struct {
+	Partition string
+	URI       string
+	Rows      int64
+	// Checksum is the sha256 of the archived file
+	Checksum string
+}
+
+// Archiver exports the partition tables as the gzip compressed CSV files into the store, and restores them back
+type Archiver struct {
+	store       Store
+	databaseURL string
+	caCertPath  string
+}
+
+func NewArchiver(store Store, databaseURL, caCertPath string) *Archiver {
+	return &Archiver{
+		store:       store,
+		databaseURL: databaseURL,
+		caCertPath:  caCertPath,
+	}
+}
+
+func (a *Archiver) connect(ctx context.Context) (*pgx.Conn, error) {
+	cert, err := os.ReadFile(a.caCertPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read the database ca cert: %w", err)
+	}
+	return database.PostgresConnection(ctx, a.databaseURL, cert)
+}
+
+// ArchivePartition exports the partition of the table into the store, it returns nil if the partition doesn't exist
+func (a *Archiver) ArchivePartition(ctx context.Context, table, partition string) (*Manifest, error) {
+	conn, err := a.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	exists := false
+	if err = conn.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", partition).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check the partition %s: %w", partition, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	file, err := os.CreateTemp("", "archive-*.csv.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the temporary archive file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	hash := sha256.New()
+	gzipWriter := gzip.NewWriter(io.MultiWriter(file, hash))
+	tag, err := conn.PgConn().CopyTo(ctx, gzipWriter,
+		fmt.Sprintf("COPY %s TO STDOUT WITH (FORMAT csv, HEADER true)", sanitize(partition)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to export the partition %s: %w", partition, err)
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress the partition %s: %w", partition, err)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	uri, err := a.store.Put(ctx, fmt.Sprintf("%s/%s.csv.gz", table, partition), file)
+	if err != nil {
+		return nil, err
+	}
+	log.Infow("archive partition table", "table", partition, "uri", uri, "rows", tag.RowsAffected())
+	return &Manifest{
+		Partition: partition,
+		URI:       uri,
+		Rows:      tag.RowsAffected(),
+		Checksum:  hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// LatestManifest returns the latest manifest of the archived partition from the data retention job log
+func (a *Archiver) LatestManifest(ctx context.Context, partition string) (*Manifest, error) {
+	conn, err := a.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	manifest := &Manifest{Partition: partition}
+	err = conn.QueryRow(ctx, `SELECT archive_uri, archive_rows, archive_checksum FROM event.data_retention_job_log
+		WHERE archived_partition = $1 AND archive_uri <> '' ORDER BY end_at DESC LIMIT 1`, partition).
+		Scan(&manifest.URI, &manifest.Rows, &manifest.Checksum)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("the partition %s isn't archived", partition)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the manifest of the partition %s: %w", partition, err)
+	}
+	return manifest, nil
+}
+
+// RestorePartition loads the archived partition into a new table, and attaches it to the table as the partition for
+// the month starting from the given time
+func (a *Archiver) RestorePartition(ctx context.Context, table string, month time.Time, manifest *Manifest) error {
+	reader, err := a.store.Get(ctx, manifest.URI)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	hash := sha256.New()
+	gzipReader, err := gzip.NewReader(io.TeeReader(reader, hash))
+	if err != nil {
+		return fmt.Errorf("failed to decompress the archive %s: %w", manifest.URI, err)
+	}
+
+	conn, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err = tx.Exec(ctx, fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS)",
+		sanitize(manifest.Partition), sanitize(table))); err != nil {
+		return fmt.Errorf("failed to create the partition %s: %w", manifest.Partition, err)
+	}
+	tag, err := tx.Conn().PgConn().CopyFrom(ctx, gzipReader,
+		fmt.Sprintf("COPY %s FROM STDIN WITH (FORMAT csv, HEADER true)", sanitize(manifest.Partition)))
+	if err != nil {
+		return fmt.Errorf("failed to load the archive %s: %w", manifest.URI, err)
+	}
+	// drain the reader to verify the checksum of the whole archived file
+	if _, err = io.Copy(io.Discard, gzipReader); err != nil {
+		return fmt.Errorf("failed to read the archive %s: %w", manifest.URI, err)
+	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); manifest.Checksum != "" && checksum != manifest.Checksum {
+		return fmt.Errorf("the checksum %s of the archive %s doesn't match the manifest %s", checksum,
+			manifest.URI, manifest.Checksum)
+	}
+
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	if _, err = tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')",
+		sanitize(table), sanitize(manifest.Partition), start.Format(dateFormat),
+		start.AddDate(0, 1, 0).Format(dateFormat))); err != nil {
+		return fmt.Errorf("failed to attach the partition %s: %w", manifest.Partition, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	log.Infow("restore partition table", "table", manifest.Partition, "uri", manifest.URI,
+		"rows", tag.RowsAffected())
+	return nil
+}
+
+// sanitize quotes the "schema.table" name to be used in the sql statement
+func sanitize(name string) string {
+	return pgx.Identifier(strings.Split(name, ".")).Sanitize()
+}
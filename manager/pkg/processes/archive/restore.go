@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// RestoreCommand is the subcommand of the manager to restore an archived partition, e.g.
+	// "manager restore-archive --table history.local_compliance --month 2019-01"
+	RestoreCommand = "restore-archive"
+
+	databaseURLEnv = "DATABASE_URL"
+	// the suffix of the monthly partition tables, it's the same as the data retention job
+	partitionDateFormat = "2006_01"
+)
+
+// Restore re-attaches the archived month as a partition of the table, so that it can be queried for the
+// investigation. The archive location is read from the manifest of the data retention job log
+func Restore(ctx context.Context, args []string) error {
+	var table, month, databaseURL, caCertPath string
+	flags := pflag.NewFlagSet(RestoreCommand, pflag.ContinueOnError)
+	flags.StringVar(&table, "table", "", "The partitioned table to restore, e.g. event.local_policies")
+	flags.StringVar(&month, "month", "", "The archived month to restore, in the format of YYYY-MM")
+	flags.StringVar(&databaseURL, "database-url", os.Getenv(databaseURLEnv), "The URL of database server")
+	flags.StringVar(&caCertPath, "postgres-ca-path", "/postgres-credential/ca.crt",
+		"The path of CA certificate for the database server")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if table == "" || month == "" || databaseURL == "" {
+		return fmt.Errorf("the table, month and database-url are required to restore the archive")
+	}
+	monthTime, err := time.ParseInLocation("2006-01", month, time.Local)
+	if err != nil {
+		return fmt.Errorf("failed to parse the month %s: %w", month, err)
+	}
+
+	store, err := NewStoreFromEnv()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("the archive storage isn't configured")
+	}
+	archiver := NewArchiver(store, databaseURL, caCertPath)
+
+	partition := fmt.Sprintf("%s_%s", table, monthTime.Format(partitionDateFormat))
+	manifest, err := archiver.LatestManifest(ctx, partition)
+	if err != nil {
+		return err
+	}
+	return archiver.RestorePartition(ctx, table, monthTime, manifest)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// the environment variables to configure the archive storage, they are set by the operator on the manager
+	ArchivePathEnv       = "ARCHIVE_PATH"
+	ArchiveS3EndpointEnv = "ARCHIVE_S3_ENDPOINT"
+	ArchiveS3BucketEnv   = "ARCHIVE_S3_BUCKET"
+	ArchiveS3RegionEnv   = "ARCHIVE_S3_REGION"
+	ArchiveS3PrefixEnv   = "ARCHIVE_S3_PREFIX"
+	AccessKeyIDEnv       = "AWS_ACCESS_KEY_ID"
+	SecretAccessKeyEnv   = "AWS_SECRET_ACCESS_KEY" // #nosec G101
+
+	defaultS3Region = "us-east-1"
+	fileScheme      = "file"
+	s3Scheme        = "s3"
+)
+
+// Store saves and loads the archived partitions
+type Store interface {
+	// Put uploads the content of the file to the key, and returns the uri of the archived object
+	Put(ctx context.Context, key string, file *os.File) (string, error)
+	// Get opens the archived object by the uri returned from the Put
+	Get(ctx context.Context, uri string) (io.ReadCloser, error)
+}
+
+// NewStoreFromEnv returns the store configured by the environment variables, or nil if the archive isn't enabled
+func NewStoreFromEnv() (Store, error) {
+	if path := os.Getenv(ArchivePathEnv); path != "" {
+		return &fileStore{root: path}, nil
+	}
+	endpoint := os.Getenv(ArchiveS3EndpointEnv)
+	if endpoint == "" {
+		return nil, nil
+	}
+	bucket := os.Getenv(ArchiveS3BucketEnv)
+	if bucket == "" {
+		return nil, fmt.Errorf("the %s must be set for the s3 archive", ArchiveS3BucketEnv)
+	}
+	region := os.Getenv(ArchiveS3RegionEnv)
+	if region == "" {
+		region = defaultS3Region
+	}
+	client := s3.New(s3.Options{
+		Region:       region,
+		BaseEndpoint: aws.String(endpoint),
+		UsePathStyle: true,
+		Credentials: credentials.NewStaticCredentialsProvider(os.Getenv(AccessKeyIDEnv),
+			os.Getenv(SecretAccessKeyEnv), ""),
+	})
+	return &s3Store{
+		client: client,
+		bucket: bucket,
+		prefix: strings.Trim(os.Getenv(ArchiveS3PrefixEnv), "/"),
+	}, nil
+}
+
+// fileStore saves the archived partitions into a directory, which is usually mounted from a persistent volume
+type fileStore struct {
+	root string
+}
+
+func (s *fileStore) Put(ctx context.Context, key string, file *os.File) (string, error) {
+	target := filepath.Join(s.root, filepath.Clean("/"+key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create the archive directory: %w", err)
+	}
+	out, err := os.Create(target) // #nosec G304
+	if err != nil {
+		return "", fmt.Errorf("failed to create the archive file %s: %w", target, err)
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+	if _, err = io.Copy(out, file); err != nil {
+		return "", fmt.Errorf("failed to write the archive file %s: %w", target, err)
+	}
+	if err = out.Sync(); err != nil {
+		return "", fmt.Errorf("failed to sync the archive file %s: %w", target, err)
+	}
+	return (&url.URL{Scheme: fileScheme, Path: target}).String(), nil
+}
+
+func (s *fileStore) Get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the archive uri %s: %w", uri, err)
+	}
+	if u.Scheme != fileScheme {
+		return nil, fmt.Errorf("the archive uri %s isn't stored in the file system", uri)
+	}
+	path := filepath.Clean(u.Path)
+	if !strings.HasPrefix(path, filepath.Clean(s.root)+string(filepath.Separator)) {
+		return nil, fmt.Errorf("the archive uri %s is out of the archive path %s", uri, s.root)
+	}
+	return os.Open(path) // #nosec G304
+}
+
+// s3Store saves the archived partitions into an S3-compatible bucket
+type s3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, file *os.File) (string, error) {
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload the archive to s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return (&url.URL{Scheme: s3Scheme, Host: s.bucket, Path: "/" + key}).String(), nil
+}
+
+func (s *s3Store) Get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the archive uri %s: %w", uri, err)
+	}
+	if u.Scheme != s3Scheme {
+		return nil, fmt.Errorf("the archive uri %s isn't stored in the s3 bucket", uri)
+	}
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the archive %s: %w", uri, err)
+	}
+	return output.Body, nil
+}
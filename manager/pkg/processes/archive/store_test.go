@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(ArchivePathEnv, root)
+	store, err := NewStoreFromEnv()
+	if err != nil {
+		t.Fatalf("failed to create the store: %v", err)
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "archive-*.csv.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if _, err = file.WriteString("archived partition"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	uri, err := store.Put(ctx, "event.local_policies/event.local_policies_2024_01.csv.gz", file)
+	if err != nil {
+		t.Fatalf("failed to put the archive: %v", err)
+	}
+	expectedURI := "file://" + filepath.Join(root, "event.local_policies", "event.local_policies_2024_01.csv.gz")
+	if uri != expectedURI {
+		t.Errorf("expected uri %s, but got %s", expectedURI, uri)
+	}
+
+	reader, err := store.Get(ctx, uri)
+	if err != nil {
+		t.Fatalf("failed to get the archive: %v", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "archived partition" {
+		t.Errorf("unexpected archive content: %s", content)
+	}
+
+	// the archive out of the root path shouldn't be read
+	if _, err = store.Get(ctx, "file:///etc/passwd"); err == nil || !strings.Contains(err.Error(), "out of") {
+		t.Errorf("expected the out of archive path error, but got %v", err)
+	}
+}
+
+func TestNewStoreFromEnv(t *testing.T) {
+	store, err := NewStoreFromEnv()
+	if err != nil || store != nil {
+		t.Errorf("expected no store without the archive environment variables, got %v, %v", store, err)
+	}
+
+	t.Setenv(ArchiveS3EndpointEnv, "https://s3.example.com")
+	if _, err = NewStoreFromEnv(); err == nil {
+		t.Errorf("expected the error without the bucket")
+	}
+
+	t.Setenv(ArchiveS3BucketEnv, "global-hub")
+	t.Setenv(ArchiveS3PrefixEnv, "/archive/")
+	store, err = NewStoreFromEnv()
+	if err != nil {
+		t.Fatalf("failed to create the s3 store: %v", err)
+	}
+	s3Store, ok := store.(*s3Store)
+	if !ok || s3Store.bucket != "global-hub" || s3Store.prefix != "archive" {
+		t.Errorf("unexpected s3 store: %+v", store)
+	}
+}
@@ -2,6 +2,7 @@ package cronjob
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
@@ -43,21 +45,7 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone.
 	scheduler := gocron.NewScheduler(time.Local)
 
-	switch managerConfig.SchedulerInterval {
-	case EveryMonth:
-		scheduler = scheduler.Every(1).Month(1)
-	case EveryWeek:
-		scheduler = scheduler.Every(1).Week()
-	case EveryHour:
-		scheduler = scheduler.Every(1).Hour()
-	case EveryMinute:
-		scheduler = scheduler.Every(1).Minute()
-	case EverySecond:
-		scheduler = scheduler.Every(1).Second()
-	default:
-		scheduler = scheduler.Every(1).Day().At("00:00")
-	}
-	complianceHistoryJob, err := scheduler.
+	complianceHistoryJob, err := every(scheduler, managerConfig.SchedulerInterval).
 		Tag(task.LocalComplianceTaskName).
 		DoWithJobDetails(task.LocalComplianceHistory, ctx)
 	if err != nil {
@@ -65,21 +53,35 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	}
 	log.Infow("set SyncLocalCompliance job", "scheduleAt", complianceHistoryJob.ScheduledAtTime())
 
+	complianceScoreJob, err := every(scheduler, managerConfig.SchedulerInterval).
+		Tag(task.ComplianceScoreTaskName).
+		DoWithJobDetails(task.ComplianceScore, ctx)
+	if err != nil {
+		return err
+	}
+	log.Infow("set ComplianceScore job", "scheduleAt", complianceScoreJob.ScheduledAtTime())
+
+	retentionPolicy, err := getRetentionPolicy(managerConfig.DatabaseConfig)
+	if err != nil {
+		return err
+	}
 	dataRetentionJob1, err := scheduler.
 		Every(1).Month(1, 15).At("00:00").
 		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+		DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 	if err != nil {
 		return err
 	}
 	dataRetentionJob2, err := scheduler.
 		Every(1).MonthLastDay().At("00:00").
 		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+		DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 	if err != nil {
 		return err
 	}
-	log.Info("set DataRetention job", "scheduleAt1", dataRetentionJob1.ScheduledAtTime(),
+	log.Infow("set DataRetention job", "events", retentionPolicy.Events, "history", retentionPolicy.History,
+		"softDeleted", retentionPolicy.SoftDeleted, "archive", retentionPolicy.Archiver != nil,
+		"scheduleAt1", dataRetentionJob1.ScheduledAtTime(),
 		"scheduleAt2", dataRetentionJob2.ScheduledAtTime())
 
 	// register the metrics before starting the jobs
@@ -91,11 +93,58 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	))
 }
 
+// every schedules the next job by the interval, it runs at 0 o'clock every day by default
+func every(scheduler *gocron.Scheduler, interval string) *gocron.Scheduler {
+	switch interval {
+	case EveryMonth:
+		return scheduler.Every(1).Month(1)
+	case EveryWeek:
+		return scheduler.Every(1).Week()
+	case EveryHour:
+		return scheduler.Every(1).Hour()
+	case EveryMinute:
+		return scheduler.Every(1).Minute()
+	case EverySecond:
+		return scheduler.Every(1).Second()
+	default:
+		return scheduler.Every(1).Day().At("00:00")
+	}
+}
+
+// getRetentionPolicy returns the retention months for each kind of data, the unspecified ones fall back to the
+// data retention. The expired partitions are archived if the archive storage is configured on the manager
+func getRetentionPolicy(databaseConfig *configs.DatabaseConfig) (task.RetentionPolicy, error) {
+	policy := task.RetentionPolicy{
+		Events:      databaseConfig.EventRetention,
+		History:     databaseConfig.HistoryRetention,
+		SoftDeleted: databaseConfig.SoftDeletedRetention,
+	}
+	if policy.Events < 1 {
+		policy.Events = databaseConfig.DataRetention
+	}
+	if policy.History < 1 {
+		policy.History = databaseConfig.DataRetention
+	}
+	if policy.SoftDeleted < 1 {
+		policy.SoftDeleted = databaseConfig.DataRetention
+	}
+
+	store, err := archive.NewStoreFromEnv()
+	if err != nil {
+		return policy, fmt.Errorf("failed to init the archive storage: %w", err)
+	}
+	if store != nil {
+		policy.Archiver = archive.NewArchiver(store, databaseConfig.ProcessDatabaseURL, databaseConfig.CACertPath)
+	}
+	return policy, nil
+}
+
 func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 	log.Infow("start job scheduler")
 	// Set the status of the job to 0 (success) when the job is started.
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.RetentionTaskName).Set(0)
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceTaskName).Set(0)
+	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.ComplianceScoreTaskName).Set(0)
 	s.scheduler.StartAsync()
 
 	// Always run data-retention job on startup to ensure partition tables exist
@@ -118,7 +167,7 @@ func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 func (s *GlobalHubJobScheduler) ExecJobs() error {
 	for _, job := range s.launchJobs {
 		switch job {
-		case task.RetentionTaskName:
+		case task.RetentionTaskName, task.ComplianceScoreTaskName:
 			log.Infow("launch the job", "name", job)
 			if err := s.scheduler.RunByTag(job); err != nil {
 				return err
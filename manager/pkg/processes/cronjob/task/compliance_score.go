@@ -0,0 +1,291 @@
+package task
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+var (
+	ComplianceScoreTaskName = "compliance-score"
+	scoreLog                = logger.ZapLogger(ComplianceScoreTaskName)
+)
+
+// the scopes of the compliance scores
+const (
+	ScoreScopeCluster    = "cluster"
+	ScoreScopeHub        = "hub"
+	ScoreScopeStandard   = "standard"
+	ScoreScopeCategory   = "category"
+	ScoreScopeControl    = "control"
+	ScoreScopeClusterSet = "clusterset"
+	ScoreScopeGlobal     = "global"
+)
+
+// the policy is weighted by the highest severity of its templates, the ones without severity are treated as medium
+var (
+	severityWeights = map[string]int{
+		"low":      1,
+		"medium":   2,
+		"high":     3,
+		"critical": 4,
+	}
+	defaultSeverityWeight = severityWeights["medium"]
+)
+
+// complianceRecord is the compliance of a policy on the clusters, with the attributes of the policy and cluster. The
+// policies of the managed hubs with the minimal aggregation level are only reported by the counts of the clusters, so
+// the cluster of them is empty
+type complianceRecord struct {
+	LeafHubName string `gorm:"column:leaf_hub_name"`
+	ClusterName string `gorm:"column:cluster_name"`
+	Clusters    int    `gorm:"column:clusters"`
+	ClusterSet  string `gorm:"column:cluster_set"`
+	Standard    string `gorm:"column:standard"`
+	Category    string `gorm:"column:category"`
+	Control     string `gorm:"column:control"`
+	Severities  string `gorm:"column:severities"`
+	Compliance  string `gorm:"column:compliance"`
+}
+
+// complianceRecordSQL reads the compliance of the policies in both aggregation levels, the compliance of each cluster
+// with the full level, and the counts of the clusters in each compliance with the minimal level
+var complianceRecordSQL = `
+	WITH policies AS (
+		SELECT
+			policy_id,
+			COALESCE(policy_standard, '') AS standard,
+			COALESCE(policy_category, '') AS category,
+			COALESCE(policy_control, '') AS control,
+			COALESCE((
+				SELECT string_agg(t -> 'objectDefinition' -> 'spec' ->> 'severity', ',')
+				FROM jsonb_array_elements(payload -> 'spec' -> 'policy-templates') t
+			), '') AS severities
+		FROM local_spec.policies
+		WHERE deleted_at IS NULL
+	)
+	SELECT
+		c.leaf_hub_name,
+		c.cluster_name,
+		1 AS clusters,
+		c.compliance::text AS compliance,
+		COALESCE(mc.payload -> 'metadata' -> 'labels' ->> 'cluster.open-cluster-management.io/clusterset', '')
+			AS cluster_set,
+		p.standard,
+		p.category,
+		p.control,
+		p.severities
+	FROM
+		local_status.compliance c
+	JOIN
+		policies p ON p.policy_id = c.policy_id
+	LEFT JOIN
+		status.managed_clusters mc ON mc.leaf_hub_name = c.leaf_hub_name AND mc.cluster_name = c.cluster_name
+			AND mc.deleted_at IS NULL
+	UNION ALL
+	SELECT
+		a.leaf_hub_name,
+		'' AS cluster_name,
+		counts.clusters,
+		counts.compliance,
+		'' AS cluster_set,
+		p.standard,
+		p.category,
+		p.control,
+		p.severities
+	FROM
+		status.aggregated_compliance a
+	JOIN
+		policies p ON p.policy_id = a.policy_id
+	CROSS JOIN LATERAL (VALUES
+		('compliant', a.compliant_clusters),
+		('non_compliant', a.non_compliant_clusters),
+		('pending', a.pending_clusters),
+		('unknown', a.unknown_clusters)
+	) AS counts(compliance, clusters)
+	WHERE
+		counts.clusters > 0
+`
+
+func ComplianceScore(ctx context.Context, job gocron.Job) {
+	start := time.Now()
+	scoreLog.Infow("start running", "currentRun", job.LastRun().Format(TimeFormat))
+
+	var err error
+	defer func() {
+		if err != nil {
+			GlobalHubCronJobGaugeVec.WithLabelValues(ComplianceScoreTaskName).Set(1)
+		} else {
+			GlobalHubCronJobGaugeVec.WithLabelValues(ComplianceScoreTaskName).Set(0)
+		}
+	}()
+
+	err = snapshotComplianceScores(ctx, start)
+	if err != nil {
+		scoreLog.Errorw("failed to snapshot the compliance scores to history.compliance_scores", "error", err)
+		return
+	}
+	scoreLog.Infow("finish running", "nextRun", job.NextRun().Format(TimeFormat))
+}
+
+// snapshotComplianceScores scores the current compliance, the scores of the day are overridden by the latest run
+func snapshotComplianceScores(ctx context.Context, now time.Time) error {
+	// the compliance is read from the replicas, the scores are written into the primary
+	readDB := database.GetReadGorm().WithContext(ctx)
+	rows, err := readDB.Raw(complianceRecordSQL).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	scorer := newComplianceScorer(now)
+	for rows.Next() {
+		record := complianceRecord{}
+		if err := readDB.ScanRows(rows, &record); err != nil {
+			return err
+		}
+		scorer.add(record)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	scores := scorer.scores()
+	if len(scores) == 0 {
+		scoreLog.Info("no compliance to score")
+		return nil
+	}
+	err = database.GetGorm().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "score_date"}, {Name: "scope"}, {Name: "name"}, {Name: "leaf_hub_name"}},
+		UpdateAll: true,
+	}).CreateInBatches(scores, int(batchSize)).Error
+	if err != nil {
+		return err
+	}
+	scoreLog.Infow("the compliance scores have been snapshotted", "count", len(scores))
+	return nil
+}
+
+type scoreKey struct {
+	scope       string
+	name        string
+	leafHubName string
+}
+
+// complianceScorer aggregates the weighted compliance of the records into the scores of each scope
+type complianceScorer struct {
+	date   time.Time
+	keys   []scoreKey
+	scored map[scoreKey]*models.ComplianceScore
+}
+
+func newComplianceScorer(now time.Time) *complianceScorer {
+	return &complianceScorer{
+		date:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		scored: map[scoreKey]*models.ComplianceScore{},
+	}
+}
+
+func (s *complianceScorer) add(record complianceRecord) {
+	weight := policyWeight(record.Severities) * record.Clusters
+	keys := []scoreKey{
+		{scope: ScoreScopeGlobal, name: ScoreScopeGlobal},
+		{scope: ScoreScopeHub, name: record.LeafHubName, leafHubName: record.LeafHubName},
+	}
+	if record.ClusterName != "" {
+		keys = append(keys, scoreKey{
+			scope: ScoreScopeCluster, name: record.ClusterName, leafHubName: record.LeafHubName,
+		})
+	}
+	// the cluster sets are defined by each managed hub
+	if record.ClusterSet != "" {
+		keys = append(keys, scoreKey{
+			scope: ScoreScopeClusterSet, name: record.ClusterSet, leafHubName: record.LeafHubName,
+		})
+	}
+	for _, standard := range splitAnnotation(record.Standard) {
+		keys = append(keys, scoreKey{scope: ScoreScopeStandard, name: standard})
+	}
+	for _, category := range splitAnnotation(record.Category) {
+		keys = append(keys, scoreKey{scope: ScoreScopeCategory, name: category})
+	}
+	for _, control := range splitAnnotation(record.Control) {
+		keys = append(keys, scoreKey{scope: ScoreScopeControl, name: control})
+	}
+
+	for _, key := range keys {
+		score, ok := s.scored[key]
+		if !ok {
+			score = &models.ComplianceScore{
+				ScoreDate:   s.date,
+				Scope:       key.scope,
+				Name:        key.name,
+				LeafHubName: key.leafHubName,
+			}
+			s.scored[key] = score
+			s.keys = append(s.keys, key)
+		}
+		switch record.Compliance {
+		case "compliant":
+			score.Compliant += record.Clusters
+			score.WeightedCompliant += weight
+			score.WeightedTotal += weight
+		case "non_compliant":
+			score.NonCompliant += record.Clusters
+			score.WeightedTotal += weight
+		case "pending":
+			score.Pending += record.Clusters
+		default:
+			score.Unknown += record.Clusters
+		}
+	}
+}
+
+// scores returns the scores in the order of the scopes are added, the score is the weighted compliant percentage of
+// the compliant and non_compliant records, the pending and unknown ones aren't scored
+func (s *complianceScorer) scores() []models.ComplianceScore {
+	scores := make([]models.ComplianceScore, 0, len(s.keys))
+	for _, key := range s.keys {
+		score := s.scored[key]
+		score.Score = nil
+		if score.WeightedTotal > 0 {
+			value := math.Round(float64(score.WeightedCompliant)*10000/float64(score.WeightedTotal)) / 100
+			score.Score = &value
+		}
+		scores = append(scores, *score)
+	}
+	return scores
+}
+
+// policyWeight returns the weight of the highest severity in the comma separated severities
+func policyWeight(severities string) int {
+	weight := 0
+	for _, severity := range strings.Split(severities, ",") {
+		if w, ok := severityWeights[strings.ToLower(strings.TrimSpace(severity))]; ok && w > weight {
+			weight = w
+		}
+	}
+	if weight == 0 {
+		return defaultSeverityWeight
+	}
+	return weight
+}
+
+// splitAnnotation splits the comma separated policy annotation, e.g. "NIST SP 800-53, NIST CSF"
+func splitAnnotation(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
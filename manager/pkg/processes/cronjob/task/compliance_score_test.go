@@ -0,0 +1,101 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestComplianceScorer(t *testing.T) {
+	now := time.Date(2024, 7, 6, 15, 4, 5, 0, time.UTC)
+	scorer := newComplianceScorer(now)
+	records := []complianceRecord{
+		{
+			LeafHubName: "hub1", ClusterName: "cluster1", ClusterSet: "default", Standard: "NIST SP 800-53, NIST CSF",
+			Category: "CM Configuration Management", Severities: "low,critical", Compliance: "non_compliant",
+		},
+		{
+			LeafHubName: "hub1", ClusterName: "cluster2", ClusterSet: "default", Standard: "NIST SP 800-53",
+			Severities: "low", Compliance: "compliant",
+		},
+		{
+			LeafHubName: "hub2", ClusterName: "cluster1", Standard: "NIST CSF", Compliance: "compliant",
+		},
+		{
+			LeafHubName: "hub2", ClusterName: "cluster1", Standard: "NIST CSF", Compliance: "pending",
+		},
+		{
+			LeafHubName: "hub2", ClusterName: "cluster3", Compliance: "unknown",
+		},
+	}
+	for _, record := range records {
+		// each record of the full aggregation level is the compliance of a cluster
+		record.Clusters = 1
+		scorer.add(record)
+	}
+
+	scores := map[scoreKey]models.ComplianceScore{}
+	for _, score := range scorer.scores() {
+		assert.Equal(t, time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC), score.ScoreDate)
+		scores[scoreKey{scope: score.Scope, name: score.Name, leafHubName: score.LeafHubName}] = score
+	}
+	assert.Len(t, scores, 11)
+
+	scoreOf := func(scope, name, leafHubName string) models.ComplianceScore {
+		score, ok := scores[scoreKey{scope: scope, name: name, leafHubName: leafHubName}]
+		require.True(t, ok, "the score of %s/%s/%s isn't found", scope, name, leafHubName)
+		return score
+	}
+
+	// critical(4) non_compliant, low(1) compliant, medium(2) compliant: (1+2)/(4+1+2)
+	global := scoreOf(ScoreScopeGlobal, ScoreScopeGlobal, "")
+	require.NotNil(t, global.Score)
+	assert.Equal(t, 42.86, *global.Score)
+	assert.Equal(t, 3, global.WeightedCompliant)
+	assert.Equal(t, 7, global.WeightedTotal)
+	assert.Equal(t, []int{2, 1, 1, 1},
+		[]int{global.Compliant, global.NonCompliant, global.Pending, global.Unknown})
+
+	assert.Equal(t, 20.0, *scoreOf(ScoreScopeHub, "hub1", "hub1").Score)
+	assert.Equal(t, 100.0, *scoreOf(ScoreScopeHub, "hub2", "hub2").Score)
+	assert.Equal(t, 20.0, *scoreOf(ScoreScopeClusterSet, "default", "hub1").Score)
+	assert.Equal(t, 0.0, *scoreOf(ScoreScopeCluster, "cluster1", "hub1").Score)
+	assert.Equal(t, 100.0, *scoreOf(ScoreScopeCluster, "cluster1", "hub2").Score)
+	assert.Equal(t, 20.0, *scoreOf(ScoreScopeStandard, "NIST SP 800-53", "").Score)
+	assert.Equal(t, 33.33, *scoreOf(ScoreScopeStandard, "NIST CSF", "").Score)
+	assert.Equal(t, 0.0, *scoreOf(ScoreScopeCategory, "CM Configuration Management", "").Score)
+
+	// the cluster without the compliant or non_compliant policies isn't scored
+	unscored := scoreOf(ScoreScopeCluster, "cluster3", "hub2")
+	assert.Nil(t, unscored.Score)
+	assert.Equal(t, 1, unscored.Unknown)
+
+	// the counts of the minimal aggregation level are scored without the clusters
+	scorer.add(complianceRecord{LeafHubName: "hub3", Clusters: 3, Standard: "NIST CSF", Compliance: "compliant"})
+	scorer.add(complianceRecord{LeafHubName: "hub3", Clusters: 1, Standard: "NIST CSF", Compliance: "non_compliant"})
+	scorer.add(complianceRecord{LeafHubName: "hub3", Clusters: 2, Standard: "NIST CSF", Compliance: "pending"})
+	scores = map[scoreKey]models.ComplianceScore{}
+	for _, score := range scorer.scores() {
+		scores[scoreKey{scope: score.Scope, name: score.Name, leafHubName: score.LeafHubName}] = score
+	}
+	assert.Len(t, scores, 12)
+
+	hub3 := scoreOf(ScoreScopeHub, "hub3", "hub3")
+	assert.Equal(t, 75.0, *hub3.Score)
+	assert.Equal(t, []int{3, 1, 2, 0}, []int{hub3.Compliant, hub3.NonCompliant, hub3.Pending, hub3.Unknown})
+	// critical(4) and medium(2) non_compliant, medium(2) * (1 + 3) compliant: 8/14
+	assert.Equal(t, 57.14, *scoreOf(ScoreScopeStandard, "NIST CSF", "").Score)
+	// (1+2+6)/(4+1+2+8)
+	assert.Equal(t, 60.0, *scoreOf(ScoreScopeGlobal, ScoreScopeGlobal, "").Score)
+}
+
+func TestPolicyWeight(t *testing.T) {
+	assert.Equal(t, 2, policyWeight(""))
+	assert.Equal(t, 1, policyWeight("low"))
+	assert.Equal(t, 4, policyWeight("low, Critical"))
+	assert.Equal(t, 2, policyWeight("informational"))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-co-op/gocron"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/archive"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -19,7 +20,7 @@ var (
 	// The main tasks of this job are:
 	// 1. ensure current month partition exists (handles operator restart scenarios)
 	// 2. create partition tables for the next month
-	// 3. delete partition tables that are no longer needed (beyond retention period)
+	// 3. archive and delete partition tables that are no longer needed (beyond retention period)
 	// 4. completely delete the soft deleted records from database after retainedMonths
 	RetentionTaskName = "data-retention"
 
@@ -40,7 +41,9 @@ var (
 		"event.local_policies",
 		"event.local_root_policies",
 		"history.local_compliance",
+		"history.compliance_scores",
 		"event.managed_clusters",
+		"event.resource_events",
 	}
 	retentionLog = logger.ZapLogger(RetentionTaskName)
 
@@ -48,7 +51,27 @@ var (
 	dataRetentionMu sync.Mutex
 )
 
-func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
+// RetentionPolicy indicates how many months each kind of data will be kept in the database
+type RetentionPolicy struct {
+	// Events is the retention months of the "event.*" partition tables
+	Events int
+	// History is the retention months of the "history.*" partition tables
+	History int
+	// SoftDeleted is the retention months of the soft deleted records in the RetentionTables
+	SoftDeleted int
+	// Archiver exports the expired partitions before they are dropped, the partitions aren't archived if it's nil
+	Archiver *archive.Archiver
+}
+
+// partitionRetention returns the retention months of the partition table
+func (p RetentionPolicy) partitionRetention(tableName string) int {
+	if strings.HasPrefix(tableName, "history.") {
+		return p.History
+	}
+	return p.Events
+}
+
+func DataRetention(ctx context.Context, policy RetentionPolicy, job gocron.Job) {
 	dataRetentionMu.Lock()
 	defer dataRetentionMu.Unlock()
 
@@ -86,10 +109,11 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 
 	// Create next month partition and delete expired partitions
 	createMonth := currentMonth.AddDate(0, 1, 0)
-	deleteMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
 	for _, tableName := range PartitionTables {
-		err = updatePartitionTables(tableName, createMonth, deleteMonth)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, true); e != nil {
+		deleteMonth := currentMonth.AddDate(0, -(policy.partitionRetention(tableName) + 1), 0)
+		var manifest *archive.Manifest
+		manifest, err = updatePartitionTables(ctx, tableName, createMonth, deleteMonth, policy.Archiver)
+		if e := traceDataRetentionLog(tableName, currentMonth, err, true, manifest); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
 		if err != nil {
@@ -99,10 +123,10 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 	}
 
 	// delete the soft deleted records from database
-	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
+	minTime := currentMonth.AddDate(0, -policy.SoftDeleted, 0)
 	for _, tableName := range RetentionTables {
 		err = deleteExpiredRecords(tableName, minTime)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, false); e != nil {
+		if e := traceDataRetentionLog(tableName, currentMonth, err, false, nil); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
 		if err != nil {
@@ -138,23 +162,34 @@ func ensurePartitionExists(tableName string, createTime time.Time) error {
 	return nil
 }
 
-func updatePartitionTables(tableName string, createTime, deleteTime time.Time) error {
+func updatePartitionTables(ctx context.Context, tableName string, createTime, deleteTime time.Time,
+	archiver *archive.Archiver,
+) (*archive.Manifest, error) {
 	db := database.GetGorm()
 
 	// create the partition tables for the next month
 	err := ensurePartitionExists(tableName, createTime)
 	if err != nil {
-		return fmt.Errorf("failed to create partition table %s: %w", tableName, err)
+		return nil, fmt.Errorf("failed to create partition table %s: %w", tableName, err)
 	}
 
-	// delete the partition tables that are expired
+	// archive the expired partition table before deleting it, keep it if failed to archive
 	deletePartitionTableName := fmt.Sprintf("%s_%s", tableName, deleteTime.Format(PartitionDateFormat))
+	var manifest *archive.Manifest
+	if archiver != nil {
+		manifest, err = archiver.ArchivePartition(ctx, tableName, deletePartitionTableName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive partition table %s: %w", deletePartitionTableName, err)
+		}
+	}
+
+	// delete the partition tables that are expired
 	deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
 	if result := db.Exec(deletionSql); result.Error != nil {
-		return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
+		return manifest, fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
 	}
 	retentionLog.Info("delete partition table", "table", deletePartitionTableName)
-	return nil
+	return manifest, nil
 }
 
 func deleteExpiredRecords(tableName string, minDate time.Time) error {
@@ -168,7 +203,9 @@ func deleteExpiredRecords(tableName string, minDate time.Time) error {
 	return nil
 }
 
-func traceDataRetentionLog(tableName string, startTime time.Time, err error, partition bool) error {
+func traceDataRetentionLog(tableName string, startTime time.Time, err error, partition bool,
+	manifest *archive.Manifest,
+) error {
 	db := database.GetGorm()
 	dataRetentionLog := &models.DataRetentionJobLog{
 		Name:    tableName,
@@ -179,6 +216,12 @@ func traceDataRetentionLog(tableName string, startTime time.Time, err error, par
 	if err != nil {
 		dataRetentionLog.Error = err.Error()
 	}
+	if manifest != nil {
+		dataRetentionLog.ArchivedPartition = manifest.Partition
+		dataRetentionLog.ArchiveURI = manifest.URI
+		dataRetentionLog.ArchiveRows = manifest.Rows
+		dataRetentionLog.ArchiveChecksum = manifest.Checksum
+	}
 
 	if partition {
 		minPartition, maxPartition, err := getMinMaxPartitions(tableName)
@@ -186,6 +186,18 @@ func (h *HubManagement) cleanup(hubName string) error {
 		if e != nil {
 			return e
 		}
+		e = tx.Where(&models.LocalPolicyTemplateCompliance{
+			LeafHubName: hubName,
+		}).Delete(&models.LocalPolicyTemplateCompliance{}).Error
+		if e != nil {
+			return e
+		}
+		e = tx.Where(&models.AggregatedCompliance{
+			LeafHubName: hubName,
+		}).Delete(&models.AggregatedCompliance{}).Error
+		if e != nil {
+			return e
+		}
 
 		// inactive the hub status
 		return tx.Model(&models.LeafHubHeartbeat{}).Where(whereLeafHubName, hubName).
@@ -338,6 +350,8 @@ func (h *HubManagement) sendHubStatusUpdate(ctx context.Context, hubName, status
 	// Send message to standby hub
 	e := utils.ToCloudEvent(constants.HubStatusUpdateMsgKey, constants.CloudEventGlobalHubClusterName,
 		standbyHub, payloadBytes)
+	// the updates of the different active hubs don't supersede each other on the standby hub
+	e.SetExtension(constants.CloudEventExtensionKeyTarget, hubName)
 
 	if err := h.producer.SendEvent(ctx, e); err != nil {
 		return fmt.Errorf("failed to send hub status update to standby hub %s: %w", standbyHub, err)
@@ -0,0 +1,343 @@
+package remediation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	remediationbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	log = logger.DefaultZapLogger()
+
+	defaultTimeout = 5 * time.Minute
+	requeuePeriod  = 5 * time.Second
+)
+
+// RemediationController reconciles the PolicyRemediationRequest, it requests the managed hubs to remediate the local
+// policies as the requester, then tracks the results reported by them
+type RemediationController struct {
+	client.Client
+	transport.Producer
+}
+
+var remediationCtrl *RemediationController
+
+func AddRemediationToManager(mgr ctrl.Manager, producer transport.Producer) error {
+	if remediationCtrl != nil {
+		return nil
+	}
+	remediationController := &RemediationController{
+		Client:   mgr.GetClient(),
+		Producer: producer,
+	}
+	if err := remediationController.SetupWithManager(mgr); err != nil {
+		return err
+	}
+	remediationCtrl = remediationController
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RemediationController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("remediation-ctrl").
+		For(&remediationv1alpha1.PolicyRemediationRequest{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *RemediationController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	request := &remediationv1alpha1.PolicyRemediationRequest{}
+	if err := r.Get(ctx, req.NamespacedName, request); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !request.DeletionTimestamp.IsZero() {
+		removeReports(string(request.UID))
+		return ctrl.Result{}, nil
+	}
+
+	switch request.Status.Phase {
+	case "", remediationv1alpha1.PhasePending:
+		return r.start(ctx, request)
+	case remediationv1alpha1.PhaseRunning:
+		return r.track(ctx, request)
+	default:
+		removeReports(string(request.UID))
+		return ctrl.Result{}, nil
+	}
+}
+
+// start sends the remediation requests to the managed hubs, the request is failed if the requester is unknown
+func (r *RemediationController) start(ctx context.Context, request *remediationv1alpha1.PolicyRemediationRequest,
+) (ctrl.Result, error) {
+	if err := validate(request); err != nil {
+		return ctrl.Result{}, r.finish(ctx, request, remediationv1alpha1.PhaseFailed, metav1.Condition{
+			Type:    remediationv1alpha1.ConditionTypeRequested,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidRequest",
+			Message: err.Error(),
+		})
+	}
+
+	hubs := request.Spec.ManagedHubs
+	if len(hubs) == 0 {
+		var err error
+		if hubs, err = policyHubs(request.Spec.Policies); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list the managed hubs of the policies - %w", err)
+		}
+	}
+	if len(hubs) == 0 {
+		return ctrl.Result{}, r.finish(ctx, request, remediationv1alpha1.PhaseFailed, metav1.Condition{
+			Type:    remediationv1alpha1.ConditionTypeRequested,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoManagedHubs",
+			Message: "no managed hubs have the policies",
+		})
+	}
+
+	payloadBytes, err := json.Marshal(toBundle(request))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ctx = audit.WithInitiator(ctx, fmt.Sprintf("%s(%s)",
+		audit.InitiatorOf("PolicyRemediationRequest", request), requester(request)))
+	for _, hub := range hubs {
+		e := utils.ToCloudEvent(constants.PolicyRemediationMsgKey, constants.CloudEventGlobalHubClusterName, hub,
+			payloadBytes)
+		e.SetExtension(constants.CloudEventExtensionKeyRemediationId, string(request.UID))
+		if err := r.SendEvent(ctx, e); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to send the remediation request to the hub %s - %w", hub, err)
+		}
+	}
+	log.Infow("requested the managed hubs to remediate the policies", "name", request.Name, "hubs", hubs,
+		"action", request.Spec.Action, "user", requester(request))
+
+	now := metav1.Now()
+	request.Status.Phase = remediationv1alpha1.PhaseRunning
+	request.Status.StartTime = &now
+	request.Status.ManagedHubs = make([]remediationv1alpha1.ManagedHubRemediationStatus, 0, len(hubs))
+	for _, hub := range hubs {
+		request.Status.ManagedHubs = append(request.Status.ManagedHubs,
+			remediationv1alpha1.ManagedHubRemediationStatus{Name: hub})
+	}
+	meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+		Type:    remediationv1alpha1.ConditionTypeRequested,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RemediationRequested",
+		Message: fmt.Sprintf("requested %d managed hubs to remediate the policies", len(hubs)),
+	})
+	if err := r.Status().Update(ctx, request); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+}
+
+// track reflects the reports of the managed hubs into the status, and completes the remediation once all the managed
+// hubs are reported or the timeout is reached
+func (r *RemediationController) track(ctx context.Context, request *remediationv1alpha1.PolicyRemediationRequest,
+) (ctrl.Result, error) {
+	id := string(request.UID)
+	updated := false
+	for i := range request.Status.ManagedHubs {
+		hubStatus := &request.Status.ManagedHubs[i]
+		if hubStatus.ReportedTime != nil {
+			continue
+		}
+		if report := getReport(id, hubStatus.Name); report != nil {
+			applyReport(hubStatus, report)
+			updated = true
+		}
+	}
+
+	pending := pendingHubs(request.Status.ManagedHubs)
+	switch {
+	case len(pending) == 0:
+		phase, condition := remediationv1alpha1.PhaseCompleted, metav1.Condition{
+			Type:    remediationv1alpha1.ConditionTypeReported,
+			Status:  metav1.ConditionTrue,
+			Reason:  "RemediationCompleted",
+			Message: "all the managed hubs reported the remediation results",
+		}
+		if failed := failedHubs(request.Status.ManagedHubs); len(failed) > 0 {
+			phase = remediationv1alpha1.PhaseFailed
+			condition.Reason = "RemediationFailed"
+			condition.Message = fmt.Sprintf("failed to remediate the policies on the managed hubs: %s",
+				strings.Join(failed, ", "))
+		}
+		return ctrl.Result{}, r.finish(ctx, request, phase, condition)
+	case time.Since(request.Status.StartTime.Time) > timeout(request):
+		return ctrl.Result{}, r.finish(ctx, request, remediationv1alpha1.PhaseFailed, metav1.Condition{
+			Type:    remediationv1alpha1.ConditionTypeReported,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Timeout",
+			Message: fmt.Sprintf("the managed hubs are not reported in time: %s", strings.Join(pending, ", ")),
+		})
+	}
+
+	if updated {
+		if err := r.Status().Update(ctx, request); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+}
+
+func (r *RemediationController) finish(ctx context.Context, request *remediationv1alpha1.PolicyRemediationRequest,
+	phase string, condition metav1.Condition,
+) error {
+	now := metav1.Now()
+	request.Status.Phase = phase
+	request.Status.CompletionTime = &now
+	meta.SetStatusCondition(&request.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, request); err != nil {
+		return err
+	}
+	removeReports(string(request.UID))
+	log.Infow("finished the remediation", "name", request.Name, "phase", phase, "message", condition.Message)
+	return nil
+}
+
+// validate checks the request can be run by the managed hubs, the requester is set by the webhook
+func validate(request *remediationv1alpha1.PolicyRemediationRequest) error {
+	if request.Annotations[remediationv1alpha1.UserIdentityAnnotation] == "" {
+		return fmt.Errorf("the request has no user identity annotation %s",
+			remediationv1alpha1.UserIdentityAnnotation)
+	}
+	if request.Spec.Action == remediationv1alpha1.ActionCreateAutomation && request.Spec.Automation == nil {
+		return fmt.Errorf("the automation is required by the action %s", request.Spec.Action)
+	}
+	return nil
+}
+
+func toBundle(request *remediationv1alpha1.PolicyRemediationRequest) *remediationbundle.PolicyRemediationBundle {
+	bundle := &remediationbundle.PolicyRemediationBundle{
+		ID:           string(request.UID),
+		Action:       request.Spec.Action,
+		Clusters:     request.Spec.Clusters,
+		UserIdentity: request.Annotations[remediationv1alpha1.UserIdentityAnnotation],
+		UserGroups:   request.Annotations[remediationv1alpha1.UserGroupsAnnotation],
+	}
+	for _, policy := range request.Spec.Policies {
+		bundle.Policies = append(bundle.Policies, remediationbundle.PolicyRef{
+			Namespace: policy.Namespace,
+			Name:      policy.Name,
+		})
+	}
+	if request.Spec.EnforceDuration != nil {
+		bundle.EnforceDurationSeconds = int64(request.Spec.EnforceDuration.Seconds())
+	}
+	if request.Spec.Automation != nil {
+		bundle.Automation = &remediationbundle.AutomationSpec{
+			Mode:         request.Spec.Automation.Mode,
+			TemplateName: request.Spec.Automation.TemplateName,
+			TowerSecret:  request.Spec.Automation.TowerSecret,
+		}
+	}
+	return bundle
+}
+
+// requester returns the decoded user identity of the request, it's only used to record the request
+func requester(request *remediationv1alpha1.PolicyRemediationRequest) string {
+	decoded, err := base64.StdEncoding.DecodeString(request.Annotations[remediationv1alpha1.UserIdentityAnnotation])
+	if err != nil {
+		return ""
+	}
+	return string(decoded)
+}
+
+// policyHubs returns the managed hubs which have any of the local policies
+var policyHubs = func(policies []remediationv1alpha1.PolicyReference) ([]string, error) {
+	db := database.GetGorm()
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	existing := map[string]bool{}
+	for _, policy := range policies {
+		hubs := []string{}
+		err := db.Model(&models.LocalSpecPolicy{}).
+			Where("policy_name = ? AND payload->'metadata'->>'namespace' = ?", policy.Name, policy.Namespace).
+			Distinct().Pluck("leaf_hub_name", &hubs).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, hub := range hubs {
+			existing[hub] = true
+		}
+	}
+	hubs := make([]string, 0, len(existing))
+	for hub := range existing {
+		hubs = append(hubs, hub)
+	}
+	sort.Strings(hubs)
+	return hubs, nil
+}
+
+func applyReport(hubStatus *remediationv1alpha1.ManagedHubRemediationStatus, report *report) {
+	hubStatus.ReportedTime = &metav1.Time{Time: report.time}
+	hubStatus.Message = report.bundle.Error
+	hubStatus.Policies = nil
+	for _, result := range report.bundle.Policies {
+		hubStatus.Policies = append(hubStatus.Policies, remediationv1alpha1.PolicyRemediationStatus{
+			Namespace: result.Namespace,
+			Name:      result.Name,
+			Result:    result.Result,
+			Message:   result.Message,
+		})
+	}
+}
+
+func pendingHubs(hubStatuses []remediationv1alpha1.ManagedHubRemediationStatus) []string {
+	pending := []string{}
+	for _, hubStatus := range hubStatuses {
+		if hubStatus.ReportedTime == nil {
+			pending = append(pending, hubStatus.Name)
+		}
+	}
+	return pending
+}
+
+// failedHubs returns the managed hubs which rejected the request or failed to remediate any of the policies
+func failedHubs(hubStatuses []remediationv1alpha1.ManagedHubRemediationStatus) []string {
+	failed := []string{}
+	for _, hubStatus := range hubStatuses {
+		if hubStatus.Message != "" {
+			failed = append(failed, hubStatus.Name)
+			continue
+		}
+		for _, policy := range hubStatus.Policies {
+			if policy.Result == remediationv1alpha1.ResultFailed {
+				failed = append(failed, hubStatus.Name)
+				break
+			}
+		}
+	}
+	return failed
+}
+
+func timeout(request *remediationv1alpha1.PolicyRemediationRequest) time.Duration {
+	if request.Spec.Timeout != nil && request.Spec.Timeout.Duration > 0 {
+		return request.Spec.Timeout.Duration
+	}
+	return defaultTimeout
+}
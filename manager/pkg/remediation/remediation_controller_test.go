@@ -0,0 +1,174 @@
+package remediation
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	remediationbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type eventProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *eventProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *eventProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func newRequest(uid string, spec remediationv1alpha1.PolicyRemediationRequestSpec,
+) *remediationv1alpha1.PolicyRemediationRequest {
+	return &remediationv1alpha1.PolicyRemediationRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "remediation", Namespace: "default", UID: types.UID(uid),
+			Annotations: map[string]string{
+				remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func TestRemediationController(t *testing.T) {
+	originalPolicyHubs := policyHubs
+	policyHubs = func(policies []remediationv1alpha1.PolicyReference) ([]string, error) {
+		return []string{"hub1", "hub2"}, nil
+	}
+	defer func() { policyHubs = originalPolicyHubs }()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, remediationv1alpha1.AddToScheme(scheme))
+	request := newRequest("remediation-uid", remediationv1alpha1.PolicyRemediationRequestSpec{
+		Action:          remediationv1alpha1.ActionEnforce,
+		Policies:        []remediationv1alpha1.PolicyReference{{Namespace: "default", Name: "policy1"}},
+		Clusters:        []string{"cluster1"},
+		EnforceDuration: &metav1.Duration{Duration: 30 * time.Minute},
+	})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(request).
+		WithStatusSubresource(request).Build()
+	producer := &eventProducer{}
+	controller := &RemediationController{Client: fakeClient, Producer: producer}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "remediation", Namespace: "default"}}
+
+	// pending -> running: the remediation requests are sent to the managed hubs of the policies
+	result, err := controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, requeuePeriod, result.RequeueAfter)
+	require.Len(t, producer.events, 2)
+	assert.Equal(t, constants.PolicyRemediationMsgKey, producer.events[0].Type())
+	assert.Equal(t, "hub1", producer.events[0].Subject())
+	assert.Equal(t, "remediation-uid",
+		producer.events[0].Extensions()[constants.CloudEventExtensionKeyRemediationId])
+
+	bundle := &remediationbundle.PolicyRemediationBundle{}
+	require.NoError(t, producer.events[0].DataAs(bundle))
+	assert.Equal(t, remediationv1alpha1.ActionEnforce, bundle.Action)
+	assert.Equal(t, int64(1800), bundle.EnforceDurationSeconds)
+	assert.Equal(t, []string{"cluster1"}, bundle.Clusters)
+	assert.Equal(t, request.Annotations[remediationv1alpha1.UserIdentityAnnotation], bundle.UserIdentity)
+
+	current := &remediationv1alpha1.PolicyRemediationRequest{}
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, remediationv1alpha1.PhaseRunning, current.Status.Phase)
+	require.Len(t, current.Status.ManagedHubs, 2)
+
+	// running: only the hub1 is reported
+	SetReported("hub1", &remediationbundle.PolicyRemediationResultBundle{
+		ID: "remediation-uid",
+		Policies: []remediationbundle.PolicyRemediationResult{
+			{Namespace: "default", Name: "policy1", Result: remediationv1alpha1.ResultSucceeded},
+		},
+	})
+	_, err = controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, remediationv1alpha1.PhaseRunning, current.Status.Phase)
+	assert.NotNil(t, current.Status.ManagedHubs[0].ReportedTime)
+	assert.Nil(t, current.Status.ManagedHubs[1].ReportedTime)
+
+	// failed: the hub2 rejects the request
+	SetReported("hub2", &remediationbundle.PolicyRemediationResultBundle{
+		ID:    "remediation-uid",
+		Error: "users \"alice\" is forbidden",
+	})
+	_, err = controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, remediationv1alpha1.PhaseFailed, current.Status.Phase)
+	assert.NotNil(t, current.Status.CompletionTime)
+	assert.Equal(t, remediationv1alpha1.ResultSucceeded, current.Status.ManagedHubs[0].Policies[0].Result)
+	assert.Contains(t, current.Status.ManagedHubs[1].Message, "forbidden")
+	assert.Equal(t, "RemediationFailed", current.Status.Conditions[1].Reason)
+	assert.Nil(t, getReport("remediation-uid", "hub1"))
+}
+
+func TestRemediationControllerInvalidRequest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, remediationv1alpha1.AddToScheme(scheme))
+
+	cases := []struct {
+		name    string
+		request *remediationv1alpha1.PolicyRemediationRequest
+		message string
+	}{
+		{
+			name: "no user identity",
+			request: func() *remediationv1alpha1.PolicyRemediationRequest {
+				request := newRequest("no-identity-uid", remediationv1alpha1.PolicyRemediationRequestSpec{
+					Action:      remediationv1alpha1.ActionReevaluate,
+					Policies:    []remediationv1alpha1.PolicyReference{{Namespace: "default", Name: "policy1"}},
+					ManagedHubs: []string{"hub1"},
+				})
+				request.Annotations = nil
+				return request
+			}(),
+			message: "no user identity",
+		},
+		{
+			name: "no automation",
+			request: newRequest("no-automation-uid", remediationv1alpha1.PolicyRemediationRequestSpec{
+				Action:      remediationv1alpha1.ActionCreateAutomation,
+				Policies:    []remediationv1alpha1.PolicyReference{{Namespace: "default", Name: "policy1"}},
+				ManagedHubs: []string{"hub1"},
+			}),
+			message: "the automation is required",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.request).
+				WithStatusSubresource(tc.request).Build()
+			producer := &eventProducer{}
+			controller := &RemediationController{Client: fakeClient, Producer: producer}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "remediation", Namespace: "default"}}
+
+			_, err := controller.Reconcile(context.TODO(), req)
+			require.NoError(t, err)
+			assert.Empty(t, producer.events)
+
+			current := &remediationv1alpha1.PolicyRemediationRequest{}
+			require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+			assert.Equal(t, remediationv1alpha1.PhaseFailed, current.Status.Phase)
+			assert.Equal(t, "InvalidRequest", current.Status.Conditions[0].Reason)
+			assert.Contains(t, current.Status.Conditions[0].Message, tc.message)
+		})
+	}
+}
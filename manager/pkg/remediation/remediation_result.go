@@ -0,0 +1,43 @@
+package remediation
+
+import (
+	"sync"
+	"time"
+
+	remediationbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+)
+
+// report is the remediation result reported by the managed hub
+type report struct {
+	time   time.Time
+	bundle *remediationbundle.PolicyRemediationResultBundle
+}
+
+var (
+	// remediation id -> hub name -> report
+	reports   = map[string]map[string]*report{}
+	reportsMu sync.RWMutex
+)
+
+// SetReported caches the results reported by the managed hub, the controller reflects them into the status of the
+// PolicyRemediationRequest in the next reconciliation
+func SetReported(hubName string, bundle *remediationbundle.PolicyRemediationResultBundle) {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	if _, ok := reports[bundle.ID]; !ok {
+		reports[bundle.ID] = map[string]*report{}
+	}
+	reports[bundle.ID][hubName] = &report{time: time.Now(), bundle: bundle}
+}
+
+func getReport(id, hubName string) *report {
+	reportsMu.RLock()
+	defer reportsMu.RUnlock()
+	return reports[id][hubName]
+}
+
+func removeReports(id string) {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	delete(reports, id)
+}
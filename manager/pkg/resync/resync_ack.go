@@ -0,0 +1,43 @@
+package resync
+
+import (
+	"sync"
+	"time"
+
+	resyncbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/resync"
+)
+
+// acknowledgement is the resync result reported by the managed hub
+type acknowledgement struct {
+	time   time.Time
+	bundle *resyncbundle.ResyncAckBundle
+}
+
+var (
+	// resync id -> hub name -> acknowledgement
+	acknowledgements   = map[string]map[string]*acknowledgement{}
+	acknowledgementsMu sync.RWMutex
+)
+
+// SetAcknowledged caches the acknowledgement of the managed hub, the controller reflects it into the status of the
+// GlobalHubResync in the next reconciliation
+func SetAcknowledged(hubName string, bundle *resyncbundle.ResyncAckBundle) {
+	acknowledgementsMu.Lock()
+	defer acknowledgementsMu.Unlock()
+	if _, ok := acknowledgements[bundle.ID]; !ok {
+		acknowledgements[bundle.ID] = map[string]*acknowledgement{}
+	}
+	acknowledgements[bundle.ID][hubName] = &acknowledgement{time: time.Now(), bundle: bundle}
+}
+
+func getAcknowledgement(id, hubName string) *acknowledgement {
+	acknowledgementsMu.RLock()
+	defer acknowledgementsMu.RUnlock()
+	return acknowledgements[id][hubName]
+}
+
+func removeAcknowledgements(id string) {
+	acknowledgementsMu.Lock()
+	defer acknowledgementsMu.Unlock()
+	delete(acknowledgements, id)
+}
@@ -0,0 +1,344 @@
+package resync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	resyncv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/resync/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	log = logger.DefaultZapLogger()
+
+	defaultTimeout = 5 * time.Minute
+	// the rows are counted after the resynced bundles are handled, the settle duration is the time to wait for the
+	// handlers once all the managed hubs are acknowledged
+	settleDuration = 10 * time.Second
+	requeuePeriod  = 5 * time.Second
+)
+
+// notSyncedMessage is reported for the event types which aren't synced by the managed hub, e.g. the CRD of the
+// resource isn't installed, it doesn't fail the resync
+const notSyncedMessage = "the event type isn't synced by the managed hub"
+
+// ResyncController reconciles the GlobalHubResync, it requests the managed hubs to resync the event types, then
+// tracks the acknowledgements and reports the row count deltas
+type ResyncController struct {
+	client.Client
+	transport.Producer
+}
+
+var resyncCtrl *ResyncController
+
+func AddResyncToManager(mgr ctrl.Manager, producer transport.Producer) error {
+	if resyncCtrl != nil {
+		return nil
+	}
+	resyncController := &ResyncController{
+		Client:   mgr.GetClient(),
+		Producer: producer,
+	}
+	if err := resyncController.SetupWithManager(mgr); err != nil {
+		return err
+	}
+	resyncCtrl = resyncController
+	return nil
+}
+
+// RequestResync requests the managed hub to resync the event type, e.g. the status deltas of the event type are lost
+func RequestResync(ctx context.Context, leafHubName, eventType string) error {
+	if resyncCtrl == nil {
+		return fmt.Errorf("the resync controller isn't initialized")
+	}
+	payloadBytes, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return err
+	}
+	e := utils.ToCloudEvent(constants.ResyncMsgKey, constants.CloudEventGlobalHubClusterName, leafHubName, payloadBytes)
+	if err := resyncCtrl.SendEvent(ctx, e); err != nil {
+		return fmt.Errorf("failed to send the resync request to the hub %s - %w", leafHubName, err)
+	}
+	log.Infow("requested the managed hub to resync", "hub", leafHubName, "eventType", enum.ShortenEventType(eventType))
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ResyncController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("resync-ctrl").
+		For(&resyncv1alpha1.GlobalHubResync{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *ResyncController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	resync := &resyncv1alpha1.GlobalHubResync{}
+	if err := r.Get(ctx, req.NamespacedName, resync); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !resync.DeletionTimestamp.IsZero() {
+		removeAcknowledgements(string(resync.UID))
+		return ctrl.Result{}, nil
+	}
+
+	switch resync.Status.Phase {
+	case "", resyncv1alpha1.PhasePending:
+		return r.start(ctx, resync)
+	case resyncv1alpha1.PhaseRunning:
+		return r.track(ctx, resync)
+	default:
+		removeAcknowledgements(string(resync.UID))
+		return ctrl.Result{}, nil
+	}
+}
+
+// start records the row counts of the managed hubs, and sends the resync requests to them
+func (r *ResyncController) start(ctx context.Context, resync *resyncv1alpha1.GlobalHubResync) (ctrl.Result, error) {
+	hubs := resync.Spec.ManagedHubs
+	if len(hubs) == 0 {
+		err := database.GetGorm().Model(&models.LeafHubHeartbeat{}).
+			Where("status = ?", constants.HubStatusActive).Order("leaf_hub_name").
+			Pluck("leaf_hub_name", &hubs).Error
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list the active managed hubs - %w", err)
+		}
+	}
+	if len(hubs) == 0 {
+		return ctrl.Result{}, r.finish(ctx, resync, resyncv1alpha1.PhaseFailed, metav1.Condition{
+			Type:    resyncv1alpha1.ConditionTypeRequested,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoManagedHubs",
+			Message: "no active managed hubs to resync",
+		})
+	}
+
+	eventTypes := normalizeEventTypes(resync.Spec.EventTypes)
+	payloadBytes, err := json.Marshal(eventTypes)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hubStatuses := make([]resyncv1alpha1.ManagedHubResyncStatus, 0, len(hubs))
+	for _, hub := range hubs {
+		hubStatus := resyncv1alpha1.ManagedHubResyncStatus{Name: hub}
+		for _, eventType := range eventTypes {
+			rowCount, err := countRows(hub, eventType)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to count the rows of %s for the hub %s - %w",
+					enum.ShortenEventType(eventType), hub, err)
+			}
+			hubStatus.EventTypes = append(hubStatus.EventTypes, resyncv1alpha1.EventTypeResyncStatus{
+				EventType: enum.ShortenEventType(eventType),
+				RowCount:  rowCount,
+			})
+		}
+		hubStatuses = append(hubStatuses, hubStatus)
+	}
+
+	ctx = audit.WithInitiator(ctx, audit.InitiatorOf("GlobalHubResync", resync))
+	for _, hub := range hubs {
+		e := utils.ToCloudEvent(constants.ResyncMsgKey, constants.CloudEventGlobalHubClusterName, hub, payloadBytes)
+		e.SetExtension(constants.CloudEventExtensionKeyResyncId, string(resync.UID))
+		if err := r.SendEvent(ctx, e); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to send the resync request to the hub %s - %w", hub, err)
+		}
+	}
+	log.Infow("requested the managed hubs to resync", "name", resync.Name, "hubs", hubs, "eventTypes", eventTypes)
+
+	now := metav1.Now()
+	resync.Status.Phase = resyncv1alpha1.PhaseRunning
+	resync.Status.StartTime = &now
+	resync.Status.ManagedHubs = hubStatuses
+	meta.SetStatusCondition(&resync.Status.Conditions, metav1.Condition{
+		Type:    resyncv1alpha1.ConditionTypeRequested,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResyncRequested",
+		Message: fmt.Sprintf("requested %d managed hubs to resync", len(hubs)),
+	})
+	if err := r.Status().Update(ctx, resync); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+}
+
+// track reflects the acknowledgements into the status, and completes the resync once all the managed hubs are
+// acknowledged or the timeout is reached
+func (r *ResyncController) track(ctx context.Context, resync *resyncv1alpha1.GlobalHubResync) (ctrl.Result, error) {
+	id := string(resync.UID)
+	updated := false
+	for i := range resync.Status.ManagedHubs {
+		hubStatus := &resync.Status.ManagedHubs[i]
+		if hubStatus.AcknowledgedTime != nil {
+			continue
+		}
+		if ack := getAcknowledgement(id, hubStatus.Name); ack != nil {
+			applyAcknowledgement(hubStatus, ack)
+			updated = true
+		}
+	}
+
+	pending, lastAcknowledged := pendingHubs(resync.Status.ManagedHubs)
+	switch {
+	case len(pending) == 0 && time.Since(lastAcknowledged) >= settleDuration:
+		if err := updateRowCountDeltas(resync.Status.ManagedHubs); err != nil {
+			return ctrl.Result{}, err
+		}
+		phase, condition := resyncv1alpha1.PhaseCompleted, metav1.Condition{
+			Type:    resyncv1alpha1.ConditionTypeAcknowledged,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ResyncCompleted",
+			Message: "all the managed hubs are resynced",
+		}
+		if failed := failedHubs(resync.Status.ManagedHubs); len(failed) > 0 {
+			phase = resyncv1alpha1.PhaseFailed
+			condition.Reason = "ResyncFailed"
+			condition.Message = fmt.Sprintf("failed to resync the event types on the managed hubs: %s",
+				strings.Join(failed, ", "))
+		}
+		return ctrl.Result{}, r.finish(ctx, resync, phase, condition)
+	case len(pending) > 0 && time.Since(resync.Status.StartTime.Time) > timeout(resync):
+		return ctrl.Result{}, r.finish(ctx, resync, resyncv1alpha1.PhaseFailed, metav1.Condition{
+			Type:    resyncv1alpha1.ConditionTypeAcknowledged,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Timeout",
+			Message: fmt.Sprintf("the managed hubs are not acknowledged in time: %s", strings.Join(pending, ", ")),
+		})
+	}
+
+	if updated {
+		if err := r.Status().Update(ctx, resync); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+}
+
+func (r *ResyncController) finish(ctx context.Context, resync *resyncv1alpha1.GlobalHubResync, phase string,
+	condition metav1.Condition,
+) error {
+	now := metav1.Now()
+	resync.Status.Phase = phase
+	resync.Status.CompletionTime = &now
+	meta.SetStatusCondition(&resync.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, resync); err != nil {
+		return err
+	}
+	removeAcknowledgements(string(resync.UID))
+	log.Infow("finished the resync", "name", resync.Name, "phase", phase, "message", condition.Message)
+	return nil
+}
+
+// normalizeEventTypes completes the short event types with the prefix, and removes the duplicated ones
+func normalizeEventTypes(eventTypes []string) []string {
+	normalized := make([]string, 0, len(eventTypes))
+	existing := map[string]bool{}
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" {
+			continue
+		}
+		if !strings.HasPrefix(eventType, enum.EventTypePrefix) {
+			eventType = enum.EventTypePrefix + eventType
+		}
+		if existing[eventType] {
+			continue
+		}
+		existing[eventType] = true
+		normalized = append(normalized, eventType)
+	}
+	return normalized
+}
+
+func applyAcknowledgement(hubStatus *resyncv1alpha1.ManagedHubResyncStatus, ack *acknowledgement) {
+	hubStatus.AcknowledgedTime = &metav1.Time{Time: ack.time}
+	for _, resynced := range ack.bundle.EventTypes {
+		for i := range hubStatus.EventTypes {
+			status := &hubStatus.EventTypes[i]
+			if status.EventType != enum.ShortenEventType(resynced.EventType) {
+				continue
+			}
+			status.ResyncedObjects = resynced.Objects
+			switch {
+			case resynced.Error != "":
+				status.Message = resynced.Error
+			case !resynced.Registered:
+				status.Message = notSyncedMessage
+			}
+		}
+	}
+}
+
+// pendingHubs returns the managed hubs which aren't acknowledged, and the latest acknowledged time
+func pendingHubs(hubStatuses []resyncv1alpha1.ManagedHubResyncStatus) ([]string, time.Time) {
+	pending := []string{}
+	lastAcknowledged := time.Time{}
+	for _, hubStatus := range hubStatuses {
+		if hubStatus.AcknowledgedTime == nil {
+			pending = append(pending, hubStatus.Name)
+			continue
+		}
+		if hubStatus.AcknowledgedTime.After(lastAcknowledged) {
+			lastAcknowledged = hubStatus.AcknowledgedTime.Time
+		}
+	}
+	return pending, lastAcknowledged
+}
+
+// failedHubs returns the managed hubs which failed to resync any of the registered event types
+func failedHubs(hubStatuses []resyncv1alpha1.ManagedHubResyncStatus) []string {
+	failed := []string{}
+	for _, hubStatus := range hubStatuses {
+		for _, status := range hubStatus.EventTypes {
+			if status.Message != "" && status.Message != notSyncedMessage {
+				failed = append(failed, hubStatus.Name)
+				break
+			}
+		}
+	}
+	return failed
+}
+
+func updateRowCountDeltas(hubStatuses []resyncv1alpha1.ManagedHubResyncStatus) error {
+	for i := range hubStatuses {
+		for j := range hubStatuses[i].EventTypes {
+			status := &hubStatuses[i].EventTypes[j]
+			if status.RowCount == nil {
+				continue
+			}
+			rowCount, err := countRows(hubStatuses[i].Name, enum.EventTypePrefix+status.EventType)
+			if err != nil {
+				return fmt.Errorf("failed to count the rows of %s for the hub %s - %w", status.EventType,
+					hubStatuses[i].Name, err)
+			}
+			if rowCount == nil {
+				continue
+			}
+			delta := *rowCount - *status.RowCount
+			status.RowCountDelta = &delta
+		}
+	}
+	return nil
+}
+
+func timeout(resync *resyncv1alpha1.GlobalHubResync) time.Duration {
+	if resync.Spec.Timeout != nil && resync.Spec.Timeout.Duration > 0 {
+		return resync.Spec.Timeout.Duration
+	}
+	return defaultTimeout
+}
@@ -0,0 +1,148 @@
+package resync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	resyncv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/resync/v1alpha1"
+	resyncbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/resync"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type eventProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *eventProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *eventProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func TestNormalizeEventTypes(t *testing.T) {
+	assert.Equal(t, []string{
+		string(enum.ManagedClusterType),
+		string(enum.LocalPolicySpecType),
+	}, normalizeEventTypes([]string{"managedcluster", string(enum.ManagedClusterType), " policy.localspec", ""}))
+}
+
+func TestResyncController(t *testing.T) {
+	rows := map[string]int64{"hub1": 2, "hub2": 5}
+	originalCountRows, originalSettleDuration := countRows, settleDuration
+	countRows = func(leafHubName, eventType string) (*int64, error) {
+		if eventType != string(enum.ManagedClusterType) {
+			return nil, nil
+		}
+		count := rows[leafHubName]
+		return &count, nil
+	}
+	settleDuration = 0
+	defer func() {
+		countRows, settleDuration = originalCountRows, originalSettleDuration
+	}()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, resyncv1alpha1.AddToScheme(scheme))
+	resync := &resyncv1alpha1.GlobalHubResync{
+		ObjectMeta: metav1.ObjectMeta{Name: "resync", Namespace: "default", UID: types.UID("resync-uid")},
+		Spec: resyncv1alpha1.GlobalHubResyncSpec{
+			ManagedHubs: []string{"hub1", "hub2"},
+			EventTypes:  []string{"managedcluster", "placement.localplacement"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resync).
+		WithStatusSubresource(resync).Build()
+	producer := &eventProducer{}
+	controller := &ResyncController{Client: fakeClient, Producer: producer}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "resync", Namespace: "default"}}
+
+	// pending -> running: the resync requests are sent to the managed hubs
+	result, err := controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, requeuePeriod, result.RequeueAfter)
+	require.Len(t, producer.events, 2)
+	assert.Equal(t, constants.ResyncMsgKey, producer.events[0].Type())
+	assert.Equal(t, "hub1", producer.events[0].Subject())
+	assert.Equal(t, "resync-uid", producer.events[0].Extensions()[constants.CloudEventExtensionKeyResyncId])
+
+	current := &resyncv1alpha1.GlobalHubResync{}
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, resyncv1alpha1.PhaseRunning, current.Status.Phase)
+	require.Len(t, current.Status.ManagedHubs, 2)
+	assert.Equal(t, int64(2), *current.Status.ManagedHubs[0].EventTypes[0].RowCount)
+
+	// running: only the hub1 is acknowledged
+	SetAcknowledged("hub1", &resyncbundle.ResyncAckBundle{ID: "resync-uid", EventTypes: []resyncbundle.ResyncedEventType{
+		{EventType: string(enum.ManagedClusterType), Objects: 3, Registered: true},
+		{EventType: string(enum.LocalPlacementType), Registered: false},
+	}})
+	_, err = controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, resyncv1alpha1.PhaseRunning, current.Status.Phase)
+	assert.NotNil(t, current.Status.ManagedHubs[0].AcknowledgedTime)
+	assert.Nil(t, current.Status.ManagedHubs[1].AcknowledgedTime)
+
+	// completed: the row count deltas are reported once all the hubs are acknowledged
+	rows["hub1"], rows["hub2"] = 3, 4
+	SetAcknowledged("hub2", &resyncbundle.ResyncAckBundle{ID: "resync-uid", EventTypes: []resyncbundle.ResyncedEventType{
+		{EventType: string(enum.ManagedClusterType), Objects: 4, Registered: true},
+		{EventType: string(enum.LocalPlacementType), Registered: true},
+	}})
+	_, err = controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, resyncv1alpha1.PhaseCompleted, current.Status.Phase)
+	assert.NotNil(t, current.Status.CompletionTime)
+	assert.Equal(t, int64(1), *current.Status.ManagedHubs[0].EventTypes[0].RowCountDelta)
+	assert.Equal(t, int64(-1), *current.Status.ManagedHubs[1].EventTypes[0].RowCountDelta)
+	assert.Equal(t, 3, current.Status.ManagedHubs[0].EventTypes[0].ResyncedObjects)
+	assert.Equal(t, notSyncedMessage, current.Status.ManagedHubs[0].EventTypes[1].Message)
+	assert.Nil(t, getAcknowledgement("resync-uid", "hub1"))
+}
+
+func TestResyncControllerTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, resyncv1alpha1.AddToScheme(scheme))
+	startTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	resync := &resyncv1alpha1.GlobalHubResync{
+		ObjectMeta: metav1.ObjectMeta{Name: "resync", Namespace: "default", UID: types.UID("timeout-uid")},
+		Spec: resyncv1alpha1.GlobalHubResyncSpec{
+			EventTypes: []string{"managedcluster"},
+			Timeout:    &metav1.Duration{Duration: time.Minute},
+		},
+		Status: resyncv1alpha1.GlobalHubResyncStatus{
+			Phase:       resyncv1alpha1.PhaseRunning,
+			StartTime:   &startTime,
+			ManagedHubs: []resyncv1alpha1.ManagedHubResyncStatus{{Name: "hub1"}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resync).
+		WithStatusSubresource(resync).Build()
+	controller := &ResyncController{Client: fakeClient, Producer: &eventProducer{}}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "resync", Namespace: "default"}}
+
+	_, err := controller.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+
+	current := &resyncv1alpha1.GlobalHubResync{}
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, current))
+	assert.Equal(t, resyncv1alpha1.PhaseFailed, current.Status.Phase)
+	assert.Equal(t, "Timeout", current.Status.Conditions[0].Reason)
+	assert.Contains(t, current.Status.Conditions[0].Message, "hub1")
+}
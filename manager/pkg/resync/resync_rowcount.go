@@ -0,0 +1,55 @@
+package resync
+
+import (
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// rowCounter locates the rows of an event type in the database, the kind is the value of the kind column if the
+// table is shared by several event types
+type rowCounter struct {
+	model any
+	kind  string
+}
+
+var rowCounters = map[enum.EventType]rowCounter{
+	enum.HubClusterInfoType:          {model: &models.LeafHub{}},
+	enum.ManagedClusterType:          {model: &models.ManagedCluster{}},
+	enum.ManagedClusterAddOnType:     {model: &models.ManagedClusterAddOn{}},
+	enum.LocalPolicySpecType:         {model: &models.LocalSpecPolicy{}},
+	enum.LocalComplianceType:         {model: &models.LocalStatusCompliance{}},
+	enum.LocalCompleteComplianceType: {model: &models.LocalStatusCompliance{}},
+	enum.MiniComplianceType:          {model: &models.AggregatedCompliance{}},
+	enum.LocalTemplateComplianceType: {model: &models.LocalPolicyTemplateCompliance{}},
+	enum.ApplicationType:             {model: &models.Application{}},
+	enum.ApplicationSetType:          {model: &models.ApplicationSet{}},
+
+	enum.LocalPlacementType:           {model: &models.LocalPlacement{}, kind: "Placement"},
+	enum.LocalPlacementDecisionType:   {model: &models.LocalPlacement{}, kind: "PlacementDecision"},
+	enum.ManagedClusterSetType:        {model: &models.LocalPlacement{}, kind: "ManagedClusterSet"},
+	enum.ManagedClusterSetBindingType: {model: &models.LocalPlacement{}, kind: "ManagedClusterSetBinding"},
+
+	enum.ClusterDeploymentType: {model: &models.ClusterProvisioning{}, kind: "ClusterDeployment"},
+	enum.ClusterInstanceType:   {model: &models.ClusterProvisioning{}, kind: "ClusterInstance"},
+	enum.ClusterCuratorType:    {model: &models.ClusterProvisioning{}, kind: "ClusterCurator"},
+}
+
+// countRows returns the number of the rows of the managed hub for the event type, it returns nil if the rows of the
+// event type can't be counted, e.g. the events are appended into the partitioned tables. The rows are counted on the
+// replicas, so the delta might lag behind the primary by the replay lag of the replicas.
+var countRows = func(leafHubName, eventType string) (*int64, error) {
+	counter, ok := rowCounters[enum.EventType(eventType)]
+	if !ok {
+		return nil, nil
+	}
+	tx := database.GetReadGorm().Model(counter.model).Where("leaf_hub_name = ?", leafHubName)
+	if counter.kind != "" {
+		tx = tx.Where("kind = ?", counter.kind)
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
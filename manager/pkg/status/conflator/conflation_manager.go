@@ -25,6 +25,8 @@ type ConflationManager struct {
 	lock          sync.Mutex
 	statistics    *statistics.Statistics
 	Requster      transport.Requester
+	// requestComplete is used by the hybrid elements to request the complete bundle once the deltas are lost
+	requestComplete CompleteRequester
 }
 
 // NewConflationManager creates a new instance of ConflationManager.
@@ -53,6 +55,12 @@ func (cm *ConflationManager) Register(registration *ConflationRegistration) {
 	cm.statistics.Register(registration.eventType)
 }
 
+// SetCompleteRequester sets the function to request the complete bundle from the managed hub, it must be set before
+// the bundles are inserted.
+func (cm *ConflationManager) SetCompleteRequester(requestComplete CompleteRequester) {
+	cm.requestComplete = requestComplete
+}
+
 // Insert function inserts the bundle to the appropriate conflation unit.
 func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 	// validate the event
@@ -88,7 +96,8 @@ func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUn
 		return conflationUnit
 	}
 	// otherwise, need to create conflation unit
-	conflationUnit := newConflationUnit(leafHubName, cm.readyQueue, cm.registrations, cm.statistics)
+	conflationUnit := newConflationUnit(leafHubName, cm.readyQueue, cm.registrations, cm.statistics,
+		cm.requestComplete)
 	cm.conflationUnits[leafHubName] = conflationUnit
 	cm.statistics.IncrementNumberOfConflations()
 	return conflationUnit
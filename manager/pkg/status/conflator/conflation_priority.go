@@ -16,6 +16,22 @@ const (
 	LocalReplicatedPolicyEventPriority ConflationPriority = iota
 	SecurityAlertCountsPriority        ConflationPriority = iota
 	ManagedClusterMigrationPriority    ConflationPriority = iota
+	ApplicationPriority                ConflationPriority = iota
+	ApplicationSetPriority             ConflationPriority = iota
+	LocalPlacementPriority             ConflationPriority = iota
+	LocalPlacementDecisionPriority     ConflationPriority = iota
+	ManagedClusterSetPriority          ConflationPriority = iota
+	ManagedClusterSetBindingPriority   ConflationPriority = iota
+	ManagedClusterAddOnPriority        ConflationPriority = iota
+	ClusterDeploymentPriority          ConflationPriority = iota
+	ClusterInstancePriority            ConflationPriority = iota
+	ClusterCuratorPriority             ConflationPriority = iota
+	ResyncAckPriority                  ConflationPriority = iota
+	AntiEntropyDigestPriority          ConflationPriority = iota
+	SpecAckPriority                    ConflationPriority = iota
+	LocalTemplateCompliancePriority    ConflationPriority = iota
+	PolicyRemediationResultPriority    ConflationPriority = iota
+	ResourceEventPriority              ConflationPriority = iota
 
 	// enable global resource
 	CompliancePriority         ConflationPriority = iota
@@ -1,13 +1,17 @@
 package conflator
 
 import (
+	"context"
 	"errors"
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -25,6 +29,7 @@ type ConflationUnit struct {
 
 func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	registrations map[string]*ConflationRegistration, statistics *statistics.Statistics,
+	requestComplete CompleteRequester,
 ) *ConflationUnit {
 	conflationUnit := &ConflationUnit{
 		name:                 name,
@@ -49,7 +54,7 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 
 		if registration.syncMode == enum.HybridStateMode {
 			log.Infow("registering hybrid element", "type", enum.ShortenEventType(registration.eventType))
-			conflationUnit.ElementPriorityQueue[registration.priority] = NewHybridElement(registration)
+			conflationUnit.ElementPriorityQueue[registration.priority] = NewHybridElement(name, registration, requestComplete)
 		}
 		conflationUnit.eventTypeToPriority[registration.eventType] = registration.priority
 	}
@@ -62,16 +67,22 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
+	_, span := tracing.StartEventSpan(context.Background(), event, "conflate", trace.SpanKindInternal,
+		attribute.String("conflation.version", eventMetadata.Version().String()))
+	defer span.End()
+
 	priority := cu.eventTypeToPriority[event.Type()]
 	conflationElement := cu.ElementPriorityQueue[priority]
 	if conflationElement == nil {
 		log.Debugw("the conflationElement hasn't been registered to conflation unit", "eventType", event.Type())
+		span.AddEvent("unregistered")
 		return
 	}
 
 	if !conflationElement.Predicate(eventMetadata.Version()) {
 		log.Infow("the conflationElement predication is false")
 		utils.PrettyPrint(event)
+		span.AddEvent("dropped the outdated event")
 		return
 	}
 
@@ -1,7 +1,9 @@
 package conflator
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
@@ -15,21 +17,38 @@ type ElementState struct {
 	HandlerLock           sync.Mutex
 }
 
+// CompleteRequester requests the managed hub to send the complete bundle of the event type
+type CompleteRequester func(ctx context.Context, leafHubName, eventType string) error
+
+// completeRequestInterval is the minimal interval to request the complete bundle again if it isn't received
+var completeRequestInterval = 5 * time.Minute
+
 type hybridElement struct {
+	leafHubName     string
 	eventType       string
 	syncMode        enum.EventSyncMode
 	handlerFunction EventHandleFunc
 	elementState    *ElementState
+
+	// the delta bundle carries the version of the previous bundle as the dependency version, the gap is detected if
+	// it isn't the last received version, then the complete bundle is requested
+	lastReceivedVersion *version.Version
+	requestComplete     CompleteRequester
+	completeRequestedAt time.Time
 }
 
-func NewHybridElement(registration *ConflationRegistration) *hybridElement {
+func NewHybridElement(leafHubName string, registration *ConflationRegistration,
+	requestComplete CompleteRequester,
+) *hybridElement {
 	return &hybridElement{
+		leafHubName:     leafHubName,
 		eventType:       registration.eventType,
 		syncMode:        registration.syncMode,
 		handlerFunction: registration.handleFunc,
 		elementState: &ElementState{
 			LastProcessedVersion: version.NewVersion(),
 		},
+		requestComplete: requestComplete,
 	}
 }
 
@@ -49,6 +68,7 @@ func (e *hybridElement) SyncMode() enum.EventSyncMode {
 func (e *hybridElement) Predicate(eventVersion *version.Version) bool {
 	if eventVersion.InitGen() {
 		e.elementState.LastProcessedVersion = version.NewVersion()
+		e.lastReceivedVersion = nil
 		log.Infow("resetting stream element version", "type", enum.ShortenEventType(e.eventType),
 			"version", eventVersion)
 	}
@@ -57,9 +77,41 @@ func (e *hybridElement) Predicate(eventVersion *version.Version) bool {
 }
 
 func (e *hybridElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
+	e.checkDeltaChain(metadata)
 	cu.readyQueue.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu, e.elementState)
 }
 
+// checkDeltaChain requests the complete bundle if the delta bundle doesn't depend on the last received bundle, e.g.
+// the previous deltas are lost. The delta is still handled, the complete bundle corrects the missing changes.
+func (e *hybridElement) checkDeltaChain(metadata ConflationMetadata) {
+	dependencyVersion := metadata.DependencyVersion()
+	lastReceivedVersion := e.lastReceivedVersion
+	e.lastReceivedVersion = metadata.Version()
+
+	// the complete bundle doesn't depend on the previous bundles
+	if dependencyVersion == nil {
+		e.completeRequestedAt = time.Time{}
+		return
+	}
+	// the chain is unknown after the manager restarts, the bundles are consumed from the committed offset
+	if lastReceivedVersion == nil || dependencyVersion.Equals(lastReceivedVersion) {
+		return
+	}
+
+	log.Warnw("detected the gap in the delta bundles", "LH", e.leafHubName, "type", enum.ShortenEventType(e.eventType),
+		"version", metadata.Version(), "dependency", dependencyVersion, "lastReceived", lastReceivedVersion)
+	if e.requestComplete == nil || time.Since(e.completeRequestedAt) < completeRequestInterval {
+		return
+	}
+	e.completeRequestedAt = time.Now()
+	go func() {
+		if err := e.requestComplete(context.Background(), e.leafHubName, e.eventType); err != nil {
+			log.Errorw("failed to request the complete bundle", "LH", e.leafHubName,
+				"type", enum.ShortenEventType(e.eventType), "error", err)
+		}
+	}()
+}
+
 // Success is to update the conflation element state after processing the event
 func (e *hybridElement) PostProcess(metadata ConflationMetadata, err error) {
 	if err != nil {
@@ -0,0 +1,70 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestHybridElementDeltaChain(t *testing.T) {
+	requested := make(chan string, 10)
+	element := NewHybridElement("hub1",
+		NewConflationRegistration(ManagedClustersPriority, enum.HybridStateMode, string(enum.ManagedClusterType), nil),
+		func(ctx context.Context, leafHubName, eventType string) error {
+			requested <- leafHubName + "/" + eventType
+			return nil
+		})
+
+	receive := func(eventVersion, dependencyVersion string) {
+		evt := cloudevents.NewEvent()
+		evt.SetType(string(enum.ManagedClusterType))
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		if dependencyVersion != "" {
+			evt.SetExtension(version.ExtDependencyVersion, dependencyVersion)
+		}
+		eventMetadata := metadata.NewThresholdMetadata("", 3, &evt)
+		require.NotNil(t, eventMetadata)
+		require.True(t, element.Predicate(eventMetadata.Version()))
+		element.checkDeltaChain(eventMetadata)
+	}
+
+	// the chain is unknown for the first delta, e.g. the manager restarts
+	receive("3.10", "3.9")
+	// the complete bundle and the deltas depending on the previous bundles
+	receive("3.12", "")
+	receive("3.12", "")
+	receive("4.13", "3.12")
+	receive("5.14", "4.13")
+	assert.Empty(t, requested)
+
+	// the delta 6.15 is lost
+	receive("7.16", "6.15")
+	select {
+	case r := <-requested:
+		assert.Equal(t, "hub1/"+string(enum.ManagedClusterType), r)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the complete bundle isn't requested")
+	}
+
+	// the complete bundle isn't requested again until the interval is passed
+	receive("9.18", "8.17")
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, requested)
+
+	// the complete bundle is received, the next gap is requested again
+	receive("10.19", "")
+	receive("12.21", "11.20")
+	select {
+	case <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the complete bundle isn't requested")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
@@ -12,6 +14,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 )
 
 // NewWorker creates a new instance of DBWorker.
@@ -73,6 +76,11 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 		log.Error(err)
 		return
 	}
+
+	ctx, span := tracing.StartEventSpan(ctx, job.Event, "process", trace.SpanKindInternal,
+		attribute.Int("worker.id", int(worker.workerID)))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// deprecated: previous full bundle handling
 	if job.ElementState == nil {
 		worker.fullBundleHandle(ctx, job)
@@ -88,7 +96,7 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 	err = wait.PollUntilContextTimeout(
 		ctx, 5*time.Second, 1*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
-			err := job.Handle(ctx, job.Event)
+			err := handle(ctx, job)
 			if err != nil {
 				// TODO: This is to handle the expired array bundles from 1.5 to 1.6 upgrade.
 				// It will be removed after the upgrade.
@@ -122,12 +130,20 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 	}
 }
 
+// handle invokes the DB handler of the event within the span of the attempt, so the retries are traced separately
+func handle(ctx context.Context, job *conflator.ConflationJob) error {
+	ctx, span := tracing.Tracer().Start(ctx, "handle "+enum.ShortenEventType(job.Event.Type()))
+	err := job.Handle(ctx, job.Event)
+	tracing.EndSpan(span, err)
+	return err
+}
+
 func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.ConflationJob) {
 	startTime := time.Now()
 	// handle the event until it's metadata is marked as processed
 	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
-			err := job.Handle(ctx, job.Event) // db connection released to pool when done
+			err := handle(ctx, job) // db connection released to pool when done
 			if err != nil {
 				job.Metadata.MarkAsUnprocessed()
 				log.Warnf("failed to handle event (%s): %v", job.Event.Type(), err)
@@ -0,0 +1,62 @@
+package antientropy
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/antientropy"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	antientropybundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/antientropy"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+type digestHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterAntiEntropyDigestHandler compares the bucket hashes of the managed hubs with the database, and requests the
+// managed hubs to repair the mismatched buckets
+func RegisterAntiEntropyDigestHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.AntiEntropyDigestType)
+	h := &digestHandler{
+		log:           logger.ZapLogger(strings.ReplaceAll(eventType, enum.EventTypePrefix, "")),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode, // each digest is for a different event type, handle them one by one
+		eventPriority: conflator.AntiEntropyDigestPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *digestHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	digest := &antientropybundle.DigestBundle{}
+	if err := evt.DataAs(digest); err != nil {
+		h.log.Warnw("failed to unmarshal the digest bundle", "LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	repair, err := antientropy.Compare(evt.Source(), digest)
+	if err != nil {
+		return err
+	}
+	if repair == nil {
+		h.log.Debugw("the buckets are matched", "LH", evt.Source(), "type", enum.ShortenEventType(digest.EventType))
+		return nil
+	}
+	return antientropy.RequestRepair(ctx, evt.Source(), repair)
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	// the application is deployed into the cluster where the argo cd is running, which is the managed hub
+	inClusterServer = "https://kubernetes.default.svc"
+	inClusterName   = "in-cluster"
+)
+
+func RegisterApplicationHandler(conflationManager *conflator.ConflationManager) {
+	generic.RegisterBundleHandler(conflationManager, enum.ApplicationType, conflator.ApplicationPriority,
+		func(leafHubName string, obj *unstructured.Unstructured) (models.Application, error) {
+			payload, err := json.Marshal(obj)
+			return models.Application{
+				LeafHubName:    leafHubName,
+				ID:             string(obj.GetUID()),
+				ClusterName:    ResolveClusterName(leafHubName, obj),
+				ApplicationSet: applicationSetName(obj),
+				Payload:        payload,
+			}, err
+		})
+}
+
+func RegisterApplicationSetHandler(conflationManager *conflator.ConflationManager) {
+	generic.RegisterBundleHandler(conflationManager, enum.ApplicationSetType, conflator.ApplicationSetPriority,
+		func(leafHubName string, obj *unstructured.Unstructured) (models.ApplicationSet, error) {
+			payload, err := json.Marshal(obj)
+			return models.ApplicationSet{
+				LeafHubName: leafHubName,
+				ID:          string(obj.GetUID()),
+				Payload:     payload,
+			}, err
+		})
+}
+
+// ResolveClusterName returns the destination cluster of the application. The argo cd cluster name is the managed
+// cluster name when the cluster is imported by the GitOpsCluster, the in-cluster destination is the managed hub itself,
+// otherwise the host of the destination server is used
+func ResolveClusterName(leafHubName string, app *unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "name")
+	server, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "server")
+
+	if name == inClusterName || (name == "" && strings.TrimSuffix(server, "/") == inClusterServer) {
+		return leafHubName
+	}
+	if name != "" {
+		return name
+	}
+	if u, err := url.Parse(server); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return server
+}
+
+// applicationSetName returns the name of the applicationset which generates the application
+func applicationSetName(app *unstructured.Unstructured) string {
+	for _, owner := range app.GetOwnerReferences() {
+		if owner.Kind == "ApplicationSet" {
+			return owner.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResolveClusterName(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination map[string]any
+		want        string
+	}{
+		{
+			name:        "destination name",
+			destination: map[string]any{"name": "cluster1", "namespace": "guestbook"},
+			want:        "cluster1",
+		},
+		{
+			name:        "in-cluster name",
+			destination: map[string]any{"name": "in-cluster"},
+			want:        "hub1",
+		},
+		{
+			name:        "in-cluster server",
+			destination: map[string]any{"server": "https://kubernetes.default.svc"},
+			want:        "hub1",
+		},
+		{
+			name:        "external server",
+			destination: map[string]any{"server": "https://api.cluster2.example.com:6443"},
+			want:        "api.cluster2.example.com",
+		},
+		{
+			name:        "no destination",
+			destination: map[string]any{},
+			want:        "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{"destination": tt.destination},
+			}}
+			assert.Equal(t, tt.want, ResolveClusterName("hub1", app))
+		})
+	}
+}
+
+func TestApplicationSetName(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name": "guestbook-cluster1",
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1", "kind": "ApplicationSet", "name": "guestbook", "uid": "1",
+			}},
+		},
+	}}
+	assert.Equal(t, "guestbook", applicationSetName(app))
+
+	app.SetOwnerReferences(nil)
+	assert.Equal(t, "", applicationSetName(app))
+}
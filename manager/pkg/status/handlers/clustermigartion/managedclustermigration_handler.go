@@ -13,6 +13,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
@@ -116,6 +117,12 @@ func (k *managedClusterMigrationHandler) handle(ctx context.Context, evt *cloude
 		return nil
 	}
 
+	if err := audit.Acknowledge(hubClusterName, audit.MigrationCorrelationID(migrationId, stage),
+		bundle.ErrMessage); err != nil {
+		log.Warnf("failed to acknowledge the audited migration, id: %s, hub: %s, stage: %s, error: %v",
+			migrationId, hubClusterName, stage, err)
+	}
+
 	if bundle.ErrMessage != "" {
 		migration.SetErrorMessage(migrationId, hubClusterName, stage, bundle.ErrMessage)
 		migration.SetClusterErrorDetailMap(migrationId, hubClusterName, stage, bundle.ClusterErrors)
@@ -0,0 +1,256 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const BundleBatchSize = 50
+
+// bundleHandler persists the objects of the generic bundles into the table of the model T, the objects are upserted
+// by their uids, and the deleted ones are removed by the id or the namespaced name. The rows can be keyed by the
+// namespaced name only if the table doesn't have the uids.
+type bundleHandler[T any] struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+	toModel       func(leafHubName string, obj *unstructured.Unstructured) (T, error)
+	options       bundleHandlerOptions
+}
+
+type bundleHandlerOptions struct {
+	kind            string
+	namespaceColumn string
+	nameColumn      string
+	keyedByName     bool
+	onConflict      clause.OnConflict
+	deleteFunc      func(tx *gorm.DB) error
+}
+
+type BundleHandlerOption func(*bundleHandlerOptions)
+
+// WithKind scopes the rows of the handler by the kind column, for the tables shared by several kinds of resources
+func WithKind(kind string) BundleHandlerOption {
+	return func(o *bundleHandlerOptions) {
+		o.kind = kind
+	}
+}
+
+// WithNamespacedNameKey keys the rows by the namespace and name columns instead of the id, for the tables without the
+// uids of the objects, e.g. the addons are keyed by the cluster and the addon name
+func WithNamespacedNameKey(namespaceColumn, nameColumn string) BundleHandlerOption {
+	return func(o *bundleHandlerOptions) {
+		o.namespaceColumn = namespaceColumn
+		o.nameColumn = nameColumn
+		o.keyedByName = true
+	}
+}
+
+// WithOnConflict overrides the upsert of the objects, all the columns are updated by default
+func WithOnConflict(onConflict clause.OnConflict) BundleHandlerOption {
+	return func(o *bundleHandlerOptions) {
+		o.onConflict = onConflict
+	}
+}
+
+// WithDeleteFunc overrides the deletion of the rows scoped by the tx, e.g. to soft delete them
+func WithDeleteFunc(deleteFunc func(tx *gorm.DB) error) BundleHandlerOption {
+	return func(o *bundleHandlerOptions) {
+		o.deleteFunc = deleteFunc
+	}
+}
+
+// RegisterBundleHandler registers the handler of the event type, which converts the objects of the bundle into the
+// rows of the model T by the toModel
+func RegisterBundleHandler[T any](conflationManager *conflator.ConflationManager, eventType enum.EventType,
+	priority conflator.ConflationPriority, toModel func(leafHubName string, obj *unstructured.Unstructured) (T, error),
+	opts ...BundleHandlerOption,
+) {
+	h := &bundleHandler[T]{
+		log:           logger.ZapLogger(strings.ReplaceAll(string(eventType), enum.EventTypePrefix, "")),
+		eventType:     string(eventType),
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: priority,
+		toModel:       toModel,
+		options: bundleHandlerOptions{
+			namespaceColumn: "namespace",
+			nameColumn:      "name",
+			onConflict:      clause.OnConflict{UpdateAll: true},
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.options)
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *bundleHandler[T]) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw(startMessage, "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	var bundle genericbundle.GenericBundle[unstructured.Unstructured]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal the bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	for _, objs := range [][]unstructured.Unstructured{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.insertOrUpdate(objs, leafHubName); err != nil {
+			return err
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		tx := h.scope(leafHubName)
+		if deleted.ID != "" && !h.options.keyedByName {
+			tx = tx.Where("id = ?", deleted.ID)
+		} else if deleted.Name != "" {
+			tx = h.byName(tx, deleted.Namespace, deleted.Name)
+		} else {
+			h.log.Warnw("delete event without ID or Name/Namespace", "LH", leafHubName)
+			continue
+		}
+		if err := h.delete(tx); err != nil {
+			return fmt.Errorf("failed to delete %s %s/%s - %w", h.name(), deleted.Namespace, deleted.Name, err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		// delete the objects that are not in the managed hub
+		deleteUnresynced := h.deleteUnresyncedById
+		if h.options.keyedByName {
+			deleteUnresynced = h.deleteUnresyncedByName
+		}
+		if err := deleteUnresynced(&bundle, leafHubName); err != nil {
+			return err
+		}
+	}
+
+	h.log.Debugw(finishMessage, "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+func (h *bundleHandler[T]) insertOrUpdate(objs []unstructured.Unstructured, leafHubName string) error {
+	batch := make([]T, 0, len(objs))
+	for i := range objs {
+		if objs[i].GetUID() == "" && !h.options.keyedByName {
+			h.log.Warnw("object has no uid, skip", "namespace", objs[i].GetNamespace(), "name", objs[i].GetName())
+			continue
+		}
+		model, err := h.toModel(leafHubName, &objs[i])
+		if err != nil {
+			return fmt.Errorf("failed to convert %s %s/%s - %w", h.name(), objs[i].GetNamespace(),
+				objs[i].GetName(), err)
+		}
+		batch = append(batch, model)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := database.GetGorm().Clauses(h.options.onConflict).CreateInBatches(batch, BundleBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert or update %s - %w", h.name(), err)
+	}
+	return nil
+}
+
+// scope returns the rows of the managed hub handled by the handler
+func (h *bundleHandler[T]) scope(leafHubName string) *gorm.DB {
+	tx := database.GetGorm().Model(new(T)).Where("leaf_hub_name = ?", leafHubName)
+	if h.options.kind != "" {
+		tx = tx.Where("kind = ?", h.options.kind)
+	}
+	return tx
+}
+
+// byName scopes the rows by the namespaced name, the namespace is empty for the cluster scoped objects, e.g. the
+// managed cluster set
+func (h *bundleHandler[T]) byName(tx *gorm.DB, namespace, name string) *gorm.DB {
+	return tx.Where(fmt.Sprintf("COALESCE(%s, '') = ? AND %s = ?", h.options.namespaceColumn, h.options.nameColumn),
+		namespace, name)
+}
+
+// deleteUnresyncedById deletes the rows which are not in the managed hub by their ids
+func (h *bundleHandler[T]) deleteUnresyncedById(bundle *genericbundle.GenericBundle[unstructured.Unstructured],
+	leafHubName string,
+) error {
+	var ids []string
+	if err := h.scope(leafHubName).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed to get the existing ids of %s - %w", h.name(), err)
+	}
+	deletingIds := []string{}
+	for _, id := range ids {
+		if bundle.FoundMetadataById(id) == nil {
+			deletingIds = append(deletingIds, id)
+		}
+	}
+	if len(deletingIds) > 0 {
+		if err := h.delete(h.scope(leafHubName).Where("id IN ?", deletingIds)); err != nil {
+			return fmt.Errorf("failed to delete the resynced %s - %w", h.name(), err)
+		}
+		h.log.Debugw("deleted the objects", "LH", leafHubName, "count", len(deletingIds))
+	}
+	return nil
+}
+
+// deleteUnresyncedByName deletes the rows keyed by the namespaced name which are not in the managed hub
+func (h *bundleHandler[T]) deleteUnresyncedByName(bundle *genericbundle.GenericBundle[unstructured.Unstructured],
+	leafHubName string,
+) error {
+	var existing []genericbundle.ObjectMetadata
+	err := h.scope(leafHubName).Select(fmt.Sprintf("%s AS namespace, %s AS name", h.options.namespaceColumn,
+		h.options.nameColumn)).Scan(&existing).Error
+	if err != nil {
+		return fmt.Errorf("failed to get the existing %s - %w", h.name(), err)
+	}
+	resynced := map[string]bool{}
+	for _, metadata := range bundle.ResyncMetadata {
+		resynced[metadata.Namespace+"/"+metadata.Name] = true
+	}
+	for _, row := range existing {
+		if resynced[row.Namespace+"/"+row.Name] {
+			continue
+		}
+		if err = h.delete(h.byName(h.scope(leafHubName), row.Namespace, row.Name)); err != nil {
+			return fmt.Errorf("failed to delete the resynced %s %s/%s - %w", h.name(), row.Namespace, row.Name, err)
+		}
+	}
+	return nil
+}
+
+func (h *bundleHandler[T]) delete(tx *gorm.DB) error {
+	if h.options.deleteFunc != nil {
+		return h.options.deleteFunc(tx)
+	}
+	return tx.Delete(new(T)).Error
+}
+
+func (h *bundleHandler[T]) name() string {
+	if h.options.kind != "" {
+		return h.options.kind
+	}
+	return enum.ShortenEventType(h.eventType)
+}
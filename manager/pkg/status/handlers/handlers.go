@@ -4,11 +4,20 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/antientropy"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/application"
 	clustermigration "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/clustermigartion"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedcluster"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedclusteraddon"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedhub"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/placement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/policy"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/provisioning"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/remediationresult"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/resourceevent"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/resyncack"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/security"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/specack"
 )
 
 func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager) {
@@ -19,17 +28,45 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager) {
 	// managed cluster
 	managedcluster.RegisterManagedClusterHandler(mgr.GetClient(), cmr)
 	managedcluster.RegisterManagedClusterEventHandler(cmr)
+	managedclusteraddon.RegisterManagedClusterAddOnHandler(cmr)
+
+	// the events of the involved objects declared by the agents, e.g. ClusterDeployment and BareMetalHost
+	resourceevent.RegisterResourceEventHandler(cmr)
+
+	// placement, placement decision and managed cluster set
+	placement.RegisterPlacementHandlers(cmr)
+
+	// cluster provisioning: clusterdeployment, clusterinstance and clustercurator
+	provisioning.RegisterProvisioningHandlers(cmr)
 
 	// managed cluster migration
 	clustermigration.RegisterManagedClusterMigrationHandler(mgr, cmr)
 
+	// the acknowledgement of the global hub resync
+	resyncack.RegisterResyncAckHandler(cmr)
+
+	// the results of the policy remediation
+	remediationresult.RegisterRemediationResultHandler(cmr)
+
+	// the bucket hashes of the anti-entropy
+	antientropy.RegisterAntiEntropyDigestHandler(cmr)
+
+	// the acknowledgements of the spec events
+	specack.RegisterSpecAckHandler(cmr)
+
 	// local policy
 	policy.RegisterLocalPolicySpecHandler(cmr)
 	policy.RegisterLocalPolicyComplianceHandler(cmr)
 	policy.RegisterLocalPolicyCompleteHandler(cmr)
+	policy.RegisterLocalTemplateComplianceHandler(cmr)
 	policy.RegisterLocalRootPolicyEventHandler(cmr)
 	policy.RegisterLocalReplicatedPolicyEventHandler(cmr)
+	policy.RegisterPolicyMiniComplianceHandler(cmr)
 
 	// security
 	security.RegisterSecurityAlertCountsHandler(cmr)
+
+	// argo cd application
+	application.RegisterApplicationHandler(cmr)
+	application.RegisterApplicationSetHandler(cmr)
 }
@@ -0,0 +1,292 @@
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	conflictEventReason     = "ClusterIdentityConflict"
+	conflictEventController = "global-hub-manager"
+)
+
+// conflictResolver detects the cluster which is claimed by several managed hubs, e.g. the cluster is imported to
+// another hub after a failed migration or a restored backup. The claims are recorded in the
+// status.managed_cluster_conflicts, and the cluster is persisted by the configured resolution.
+type conflictResolver struct {
+	client client.Client
+}
+
+// filter returns the clusters of the managed hub which can be persisted, the claims of the conflicted clusters are
+// recorded, and the quarantined clusters are marked with the conflict error. It must be called in the transaction
+// which persists the clusters, the owners are locked until the clusters are persisted, so that the managed hubs
+// claiming the same cluster are resolved one by one
+func (r *conflictResolver) filter(ctx context.Context, db *gorm.DB, leafHubName string,
+	clusters []models.ManagedCluster,
+) ([]models.ManagedCluster, error) {
+	if len(clusters) == 0 {
+		return clusters, nil
+	}
+	ids := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.ClusterID)
+	}
+
+	var owners []models.ManagedCluster
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("cluster_id IN ?", ids).Order("cluster_id").
+		Find(&owners).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the owners of the clusters - %w", err)
+	}
+	ownerOf := map[string]models.ManagedCluster{}
+	for _, owner := range owners {
+		ownerOf[owner.ClusterID] = owner
+	}
+
+	var claims []models.ManagedClusterConflict
+	if err := db.Where("cluster_id IN ? AND resolved_at IS NULL", ids).Find(&claims).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the conflicts of the clusters - %w", err)
+	}
+	conflicted := map[string]bool{}
+	for _, claim := range claims {
+		conflicted[claim.ClusterID] = true
+	}
+
+	resolution := configs.GetClusterConflictResolution()
+	persisting := make([]models.ManagedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		owner, owned := ownerOf[cluster.ClusterID]
+		if !owned || (owner.LeafHubName == leafHubName && !conflicted[cluster.ClusterID]) {
+			persisting = append(persisting, cluster)
+			continue
+		}
+		clusterName := clusterNameOf(cluster)
+
+		if !conflicted[cluster.ClusterID] {
+			planned, err := r.isPlannedMove(ctx, clusterName, owner.LeafHubName, leafHubName)
+			if err != nil {
+				return nil, err
+			}
+			if planned {
+				log.Infow("the cluster is migrated to the managed hub", "cluster", clusterName, "from",
+					owner.LeafHubName, "to", leafHubName)
+				persisting = append(persisting, cluster)
+				continue
+			}
+			// record the claim of the owner once the conflict is detected
+			if err := recordClaim(db, owner, clusterName, resolution); err != nil {
+				return nil, err
+			}
+			if err := raiseConflictEvent(db, cluster, clusterName, owner.LeafHubName, resolution); err != nil {
+				log.Warnw("failed to raise the conflict event", "cluster", clusterName, "error", err)
+			}
+			log.Warnw("the cluster is claimed by several managed hubs", "cluster", clusterName, "id",
+				cluster.ClusterID, "owner", owner.LeafHubName, "claimant", leafHubName, "resolution", resolution)
+		}
+		if err := recordClaim(db, cluster, clusterName, resolution); err != nil {
+			return nil, err
+		}
+
+		switch resolution {
+		case configs.ConflictResolutionNewest:
+			if owner.LeafHubName == leafHubName || isNewer(cluster, owner) {
+				persisting = append(persisting, cluster)
+			}
+		case configs.ConflictResolutionPinned:
+			if owner.LeafHubName == leafHubName {
+				persisting = append(persisting, cluster)
+			}
+		case configs.ConflictResolutionQuarantine:
+			if owner.Error != database.ErrorConflict {
+				err := db.Model(&models.ManagedCluster{}).Where("cluster_id = ?", cluster.ClusterID).
+					Update("error", database.ErrorConflict).Error
+				if err != nil {
+					return nil, fmt.Errorf("failed to quarantine the cluster %s - %w", clusterName, err)
+				}
+			}
+		}
+	}
+	return persisting, nil
+}
+
+// resolve resolves the claims of the managed hub once the clusters are deleted from it, the conflict is resolved if
+// only one claim is left, and the cluster of the left claim is persisted
+func (r *conflictResolver) resolve(db *gorm.DB, leafHubName string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := time.Now()
+	result := db.Model(&models.ManagedClusterConflict{}).
+		Where("leaf_hub_name = ? AND cluster_id IN ? AND resolved_at IS NULL", leafHubName, ids).
+		Update("resolved_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to resolve the claims of the managed hub %s - %w", leafHubName, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	for _, id := range ids {
+		var claims []models.ManagedClusterConflict
+		if err := db.Where("cluster_id = ? AND resolved_at IS NULL", id).Find(&claims).Error; err != nil {
+			return fmt.Errorf("failed to get the claims of the cluster %s - %w", id, err)
+		}
+		if len(claims) != 1 {
+			continue
+		}
+		left := claims[0]
+		err := db.Unscoped().Clauses(clause.OnConflict{UpdateAll: true}).Create(&models.ManagedCluster{
+			ClusterID:   left.ClusterID,
+			LeafHubName: left.LeafHubName,
+			Payload:     left.Payload,
+			Error:       database.ErrorNone,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to persist the cluster %s of the managed hub %s - %w", left.ClusterName,
+				left.LeafHubName, err)
+		}
+		err = db.Model(&models.ManagedClusterConflict{}).
+			Where("cluster_id = ? AND leaf_hub_name = ?", left.ClusterID, left.LeafHubName).
+			Update("resolved_at", now).Error
+		if err != nil {
+			return fmt.Errorf("failed to resolve the claim of the cluster %s - %w", left.ClusterName, err)
+		}
+		log.Infow("the cluster conflict is resolved", "cluster", left.ClusterName, "owner", left.LeafHubName)
+	}
+	return nil
+}
+
+// isPlannedMove returns true if the cluster is moved between the managed hubs by the ManagedClusterMigration
+func (r *conflictResolver) isPlannedMove(ctx context.Context, clusterName, owner, claimant string) (bool, error) {
+	if r.client == nil {
+		return false, nil
+	}
+	migrations := &migrationv1alpha1.ManagedClusterMigrationList{}
+	if err := r.client.List(ctx, migrations); err != nil {
+		return false, fmt.Errorf("failed to list the managed cluster migrations - %w", err)
+	}
+	return isMigrated(migrations.Items, clusterName, owner, claimant), nil
+}
+
+// isMigrated returns true if the cluster is migrated from the owner to the claimant, or it's rolled back from the
+// claimant to the owner
+func isMigrated(migrations []migrationv1alpha1.ManagedClusterMigration, clusterName, owner, claimant string) bool {
+	for _, mcm := range migrations {
+		clusters := migration.GetClusterList(string(mcm.UID))
+		if len(clusters) == 0 {
+			clusters = mcm.Spec.IncludedManagedClusters
+		}
+		if !slices.Contains(clusters, clusterName) {
+			continue
+		}
+		switch mcm.Status.Phase {
+		case migrationv1alpha1.PhaseFailed, migrationv1alpha1.PhaseRollbacking:
+			if mcm.Spec.From == claimant && mcm.Spec.To == owner {
+				return true
+			}
+		default:
+			if mcm.Spec.From == owner && mcm.Spec.To == claimant {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// activeClaims returns the ids of the clusters claimed by the managed hub which are still in conflict
+func (r *conflictResolver) activeClaims(db *gorm.DB, leafHubName string) ([]string, error) {
+	var ids []string
+	err := db.Model(&models.ManagedClusterConflict{}).
+		Where("leaf_hub_name = ? AND resolved_at IS NULL", leafHubName).Pluck("cluster_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the claims of the managed hub %s - %w", leafHubName, err)
+	}
+	return ids, nil
+}
+
+func clusterNameOf(cluster models.ManagedCluster) string {
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(cluster.Payload, obj); err != nil {
+		return ""
+	}
+	return obj.Name
+}
+
+// importedAt returns the time when the cluster is imported to the managed hub, which is the creation time of the
+// managed cluster on the hub
+func importedAt(cluster models.ManagedCluster) time.Time {
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(cluster.Payload, obj); err != nil {
+		return time.Time{}
+	}
+	return obj.CreationTimestamp.Time
+}
+
+// isNewer returns true if the cluster is imported to its managed hub after the owner, the owner is kept if the
+// import time of the cluster is unknown
+func isNewer(cluster, owner models.ManagedCluster) bool {
+	clusterImportedAt := importedAt(cluster)
+	if clusterImportedAt.IsZero() {
+		return false
+	}
+	return clusterImportedAt.After(importedAt(owner))
+}
+
+func recordClaim(db *gorm.DB, cluster models.ManagedCluster, clusterName string,
+	resolution configs.ClusterConflictResolution,
+) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cluster_id"}, {Name: "leaf_hub_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cluster_name", "resolution", "payload", "updated_at", "resolved_at"}),
+	}).Create(&models.ManagedClusterConflict{
+		ClusterID:   cluster.ClusterID,
+		LeafHubName: cluster.LeafHubName,
+		ClusterName: clusterName,
+		Resolution:  string(resolution),
+		Payload:     cluster.Payload,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record the claim of the cluster %s from the managed hub %s - %w", clusterName,
+			cluster.LeafHubName, err)
+	}
+	return nil
+}
+
+// raiseConflictEvent records the warning event of the conflicted cluster into the event.managed_clusters
+func raiseConflictEvent(db *gorm.DB, cluster models.ManagedCluster, clusterName, owner string,
+	resolution configs.ClusterConflictResolution,
+) error {
+	err := db.Create(&models.ManagedClusterEvent{
+		EventNamespace: clusterName,
+		EventName:      fmt.Sprintf("%s.%x", clusterName, time.Now().UnixNano()),
+		ClusterName:    clusterName,
+		ClusterID:      cluster.ClusterID,
+		LeafHubName:    cluster.LeafHubName,
+		Message: fmt.Sprintf("The cluster is claimed by the managed hubs %s and %s, it's resolved by %s",
+			owner, cluster.LeafHubName, resolution),
+		Reason:              conflictEventReason,
+		ReportingController: conflictEventController,
+		ReportingInstance:   conflictEventController,
+		EventType:           corev1.EventTypeWarning,
+		CreatedAt:           time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record the conflict event of the cluster %s - %w", clusterName, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package managedcluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestIsMigrated(t *testing.T) {
+	migrations := []migrationv1alpha1.ManagedClusterMigration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "registering", UID: "uid-registering"},
+			Spec: migrationv1alpha1.ManagedClusterMigrationSpec{
+				IncludedManagedClusters: []string{"cluster1"},
+				From:                    "hub1",
+				To:                      "hub2",
+			},
+			Status: migrationv1alpha1.ManagedClusterMigrationStatus{Phase: migrationv1alpha1.PhaseRegistering},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed", UID: "uid-failed"},
+			Spec: migrationv1alpha1.ManagedClusterMigrationSpec{
+				IncludedManagedClusters: []string{"cluster2"},
+				From:                    "hub1",
+				To:                      "hub2",
+			},
+			Status: migrationv1alpha1.ManagedClusterMigrationStatus{Phase: migrationv1alpha1.PhaseFailed},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		cluster  string
+		owner    string
+		claimant string
+		expected bool
+	}{
+		{"migrated to the target hub", "cluster1", "hub1", "hub2", true},
+		{"claimed by the source hub after migrated", "cluster1", "hub2", "hub1", false},
+		{"claimed by another hub", "cluster1", "hub1", "hub3", false},
+		{"not in the migration", "cluster3", "hub1", "hub2", false},
+		{"rolled back to the source hub", "cluster2", "hub2", "hub1", true},
+		{"claimed by the target hub after failed", "cluster2", "hub1", "hub2", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, isMigrated(migrations, c.cluster, c.owner, c.claimant))
+		})
+	}
+}
+
+func TestClusterNameOf(t *testing.T) {
+	require.Equal(t, "cluster1", clusterNameOf(models.ManagedCluster{
+		Payload: []byte(`{"metadata":{"name":"cluster1"}}`),
+	}))
+	require.Equal(t, "", clusterNameOf(models.ManagedCluster{Payload: []byte(`invalid`)}))
+}
+
+func TestIsNewer(t *testing.T) {
+	older := models.ManagedCluster{
+		LeafHubName: "hub1",
+		Payload:     []byte(`{"metadata":{"name":"cluster1","creationTimestamp":"2026-01-01T00:00:00Z"}}`),
+	}
+	newer := models.ManagedCluster{
+		LeafHubName: "hub2",
+		Payload:     []byte(`{"metadata":{"name":"cluster1","creationTimestamp":"2026-02-01T00:00:00Z"}}`),
+	}
+	unknown := models.ManagedCluster{
+		LeafHubName: "hub3",
+		Payload:     []byte(`{"metadata":{"name":"cluster1"}}`),
+	}
+
+	require.True(t, isNewer(newer, older))
+	// the owner is kept even if it's reported after the newer cluster
+	require.False(t, isNewer(older, newer))
+	require.False(t, isNewer(older, older))
+	require.False(t, isNewer(unknown, older))
+	require.True(t, isNewer(older, unknown))
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
 	"github.com/stolostron/multicloud-operators-foundation/pkg/klusterlet/clusterclaim"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
@@ -36,6 +38,7 @@ type managedClusterHandler struct {
 	eventSyncMode enum.EventSyncMode
 	eventPriority conflator.ConflationPriority
 	requester     transport.Requester
+	conflicts     *conflictResolver
 }
 
 func RegisterManagedClusterHandler(c client.Client, conflationManager *conflator.ConflationManager) {
@@ -45,6 +48,7 @@ func RegisterManagedClusterHandler(c client.Client, conflationManager *conflator
 		eventSyncMode: enum.HybridStateMode,
 		eventPriority: conflator.ManagedClustersPriority,
 		requester:     conflationManager.Requster,
+		conflicts:     &conflictResolver{client: c},
 	}
 	conflationManager.Register(conflator.NewConflationRegistration(
 		h.eventPriority,
@@ -75,7 +79,7 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 	}
 
 	for _, data := range operations {
-		if err := h.insertOrUpdate(data, leafHubName); err != nil {
+		if err := h.insertOrUpdate(ctx, data, leafHubName); err != nil {
 			return fmt.Errorf("failed to process managed clusters - %w", err)
 		}
 	}
@@ -89,6 +93,9 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 				if err != nil {
 					return fmt.Errorf("failed deleting managed clusters - %w", err)
 				}
+				if err = h.conflicts.resolve(db, leafHubName, []string{deleted.ID}); err != nil {
+					return err
+				}
 			} else if deleted.Name != "" {
 				// if the cluster is deleted, we need to delete it by name and namespace
 				err = db.Where("leaf_hub_name = ?", leafHubName).Where("cluster_name = ?", deleted.Name).
@@ -130,6 +137,20 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 			}
 			log.Debugw("deleted managed clusters", "LH", leafHubName, "count", len(deletingIds))
 		}
+
+		// the claims of the conflicted clusters which are not in the managed hub anymore
+		claimedIds, err := h.conflicts.activeClaims(db, leafHubName)
+		if err != nil {
+			return err
+		}
+		for _, id := range claimedIds {
+			if bundle.FoundMetadataById(id) == nil && !slices.Contains(deletingIds, id) {
+				deletingIds = append(deletingIds, id)
+			}
+		}
+		if err = h.conflicts.resolve(db, leafHubName, deletingIds); err != nil {
+			return err
+		}
 		return nil
 	}
 
@@ -315,7 +336,9 @@ func GetK8SCluster(ctx context.Context,
 	return k8sCluster
 }
 
-func (h *managedClusterHandler) insertOrUpdate(objs []clusterv1.ManagedCluster, leafHubName string) error {
+func (h *managedClusterHandler) insertOrUpdate(ctx context.Context, objs []clusterv1.ManagedCluster,
+	leafHubName string,
+) error {
 	if len(objs) == 0 {
 		return nil
 	}
@@ -343,12 +366,21 @@ func (h *managedClusterHandler) insertOrUpdate(objs []clusterv1.ManagedCluster,
 		})
 	}
 
-	db := database.GetGorm()
-	err := db.Unscoped().Clauses(clause.OnConflict{
-		UpdateAll: true,
-	}).CreateInBatches(batchClusters, BatchSize).Error
-	if err != nil {
-		return fmt.Errorf("failed to insert or update clusters: %w", err)
-	}
-	return nil
+	// the owners of the clusters are locked by the filter until the clusters are persisted
+	return database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		persisting, err := h.conflicts.filter(ctx, tx, leafHubName, batchClusters)
+		if err != nil {
+			return err
+		}
+		if len(persisting) == 0 {
+			return nil
+		}
+		err = tx.Unscoped().Clauses(clause.OnConflict{
+			UpdateAll: true,
+		}).CreateInBatches(persisting, BatchSize).Error
+		if err != nil {
+			return fmt.Errorf("failed to insert or update clusters: %w", err)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,66 @@
+package managedclusteraddon
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// RegisterManagedClusterAddOnHandler persists the health of the addons, the rows are keyed by the cluster and the
+// addon name
+func RegisterManagedClusterAddOnHandler(conflationManager *conflator.ConflationManager) {
+	generic.RegisterBundleHandler(conflationManager, enum.ManagedClusterAddOnType,
+		conflator.ManagedClusterAddOnPriority,
+		func(leafHubName string, obj *unstructured.Unstructured) (models.ManagedClusterAddOn, error) {
+			addon := &addonv1alpha1.ManagedClusterAddOn{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, addon); err != nil {
+				return models.ManagedClusterAddOn{}, err
+			}
+			return toModel(leafHubName, addon)
+		},
+		generic.WithNamespacedNameKey("cluster_name", "addon_name"),
+	)
+}
+
+// toModel converts the addon into the row, the message is from the first unhealthy condition
+func toModel(leafHubName string, addon *addonv1alpha1.ManagedClusterAddOn) (models.ManagedClusterAddOn, error) {
+	conditions, err := json.Marshal(addon.Status.Conditions)
+	if err != nil {
+		return models.ManagedClusterAddOn{}, err
+	}
+	row := models.ManagedClusterAddOn{
+		LeafHubName: leafHubName,
+		ClusterName: addon.Namespace,
+		AddonName:   addon.Name,
+		Available:   conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable),
+		Degraded:    conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded),
+		Progressing: conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionProgressing),
+		Conditions:  conditions,
+	}
+
+	available := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+	degraded := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded)
+	switch {
+	case available != nil && available.Status != metav1.ConditionTrue:
+		row.Message = available.Message
+	case degraded != nil && degraded.Status == metav1.ConditionTrue:
+		row.Message = degraded.Message
+	}
+	return row, nil
+}
+
+func conditionStatus(conditions []metav1.Condition, conditionType string) string {
+	if cond := meta.FindStatusCondition(conditions, conditionType); cond != nil {
+		return string(cond.Status)
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package managedclusteraddon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestToModel(t *testing.T) {
+	tests := []struct {
+		name            string
+		conditions      []metav1.Condition
+		wantAvailable   string
+		wantDegraded    string
+		wantProgressing string
+		wantMessage     string
+	}{
+		{
+			name: "healthy addon",
+			conditions: []metav1.Condition{
+				{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue, Message: "ok"},
+				{Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: metav1.ConditionFalse},
+			},
+			wantAvailable: "True",
+			wantDegraded:  "False",
+		},
+		{
+			name: "unavailable addon",
+			conditions: []metav1.Condition{
+				{
+					Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionUnknown,
+					Message: "lease not updated",
+				},
+				{Type: addonv1alpha1.ManagedClusterAddOnConditionProgressing, Status: metav1.ConditionFalse},
+			},
+			wantAvailable:   "Unknown",
+			wantProgressing: "False",
+			wantMessage:     "lease not updated",
+		},
+		{
+			name: "degraded addon",
+			conditions: []metav1.Condition{
+				{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+				{
+					Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: metav1.ConditionTrue,
+					Message: "pod crashloop",
+				},
+			},
+			wantAvailable: "True",
+			wantDegraded:  "True",
+			wantMessage:   "pod crashloop",
+		},
+		{
+			name: "no conditions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addon := &addonv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "work-manager", Namespace: "cluster1"},
+				Status:     addonv1alpha1.ManagedClusterAddOnStatus{Conditions: tt.conditions},
+			}
+			row, err := toModel("hub1", addon)
+			require.NoError(t, err)
+			assert.Equal(t, "hub1", row.LeafHubName)
+			assert.Equal(t, "cluster1", row.ClusterName)
+			assert.Equal(t, "work-manager", row.AddonName)
+			assert.Equal(t, tt.wantAvailable, row.Available)
+			assert.Equal(t, tt.wantDegraded, row.Degraded)
+			assert.Equal(t, tt.wantProgressing, row.Progressing)
+			assert.Equal(t, tt.wantMessage, row.Message)
+		})
+	}
+}
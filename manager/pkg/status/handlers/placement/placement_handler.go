@@ -0,0 +1,39 @@
+package placement
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// RegisterPlacementHandlers registers the handlers of the placement resources, each kind of them is persisted into
+// the local_spec.placements with its kind
+func RegisterPlacementHandlers(conflationManager *conflator.ConflationManager) {
+	for _, r := range []struct {
+		eventType enum.EventType
+		priority  conflator.ConflationPriority
+		kind      string
+	}{
+		{enum.LocalPlacementType, conflator.LocalPlacementPriority, "Placement"},
+		{enum.LocalPlacementDecisionType, conflator.LocalPlacementDecisionPriority, "PlacementDecision"},
+		{enum.ManagedClusterSetType, conflator.ManagedClusterSetPriority, "ManagedClusterSet"},
+		{enum.ManagedClusterSetBindingType, conflator.ManagedClusterSetBindingPriority, "ManagedClusterSetBinding"},
+	} {
+		kind := r.kind
+		generic.RegisterBundleHandler(conflationManager, r.eventType, r.priority,
+			func(leafHubName string, obj *unstructured.Unstructured) (models.LocalPlacement, error) {
+				payload, err := json.Marshal(obj)
+				return models.LocalPlacement{
+					LeafHubName: leafHubName,
+					ID:          string(obj.GetUID()),
+					Kind:        kind,
+					Payload:     payload,
+				}, err
+			}, generic.WithKind(kind))
+	}
+}
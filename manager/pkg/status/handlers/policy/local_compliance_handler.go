@@ -60,6 +60,17 @@ func (h *localPolicyComplianceHandler) handleCompliance(ctx context.Context, evt
 	}
 
 	db := database.GetGorm()
+	// the managed hub is switched to the full level, remove the compliance counts of the minimal level, so that they
+	// aren't counted twice by the status.policy_compliance_summary
+	ret := db.Where("leaf_hub_name = ?", leafHub).Delete(&models.AggregatedCompliance{})
+	if ret.Error != nil {
+		return fmt.Errorf("failed to delete the minimal compliance of leaf hub %s - %w", leafHub, ret.Error)
+	}
+	if ret.RowsAffected > 0 {
+		log.Infow("deleted the minimal compliance of the minimal aggregation level", "LH", leafHub,
+			"count", ret.RowsAffected)
+	}
+
 	// policyID: { compliance: (cluster1, cluster2), nonCompliance: (cluster3, cluster4), unknowns: (cluster5) }
 	allComplianceClustersFromDB, err := getLocalComplianceClusterSets(db, "leaf_hub_name = ?", leafHub)
 	if err != nil {
@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/common"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+type localTemplateComplianceHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterLocalTemplateComplianceHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.LocalTemplateComplianceType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &localTemplateComplianceHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.LocalTemplateCompliancePriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+// handleEvent replaces the template compliances of the managed hub with the bundle, since the bundle holds the full
+// state of the replicated policies
+func (h *localTemplateComplianceHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHub := evt.Source()
+	h.log.Debugw(startMessage, "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	data := grc.PolicyTemplateComplianceBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	compliances, err := h.toModels(leafHub, data)
+	if err != nil {
+		return err
+	}
+
+	db := database.GetGorm()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var existing []models.LocalPolicyTemplateCompliance
+		err := tx.Select("policy_id", "cluster_name", "template_kind", "template_name").
+			Where("leaf_hub_name = ?", leafHub).Find(&existing).Error
+		if err != nil {
+			return fmt.Errorf("failed to get the template compliances of the hub %s - %w", leafHub, err)
+		}
+
+		if len(compliances) > 0 {
+			err = tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(compliances, 100).Error
+			if err != nil {
+				return fmt.Errorf("failed to upsert the template compliances of the hub %s - %w", leafHub, err)
+			}
+		}
+
+		// delete the templates which aren't in the bundle, e.g. the policy is deleted or the template is removed
+		reported := map[string]bool{}
+		for _, c := range compliances {
+			reported[templateKey(c)] = true
+		}
+		for _, c := range existing {
+			if reported[templateKey(c)] {
+				continue
+			}
+			err = tx.Where(&models.LocalPolicyTemplateCompliance{
+				PolicyID:     c.PolicyID,
+				ClusterName:  c.ClusterName,
+				LeafHubName:  leafHub,
+				TemplateKind: c.TemplateKind,
+				TemplateName: c.TemplateName,
+			}).Delete(&models.LocalPolicyTemplateCompliance{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to delete the template compliance %s - %w", templateKey(c), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	h.log.Debugw(finishMessage, "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+func (h *localTemplateComplianceHandler) toModels(leafHub string, data grc.PolicyTemplateComplianceBundle,
+) ([]models.LocalPolicyTemplateCompliance, error) {
+	compliances := []models.LocalPolicyTemplateCompliance{}
+	for _, policy := range data {
+		for _, template := range policy.Templates {
+			relatedObjects, err := json.Marshal(template.RelatedObjects)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the related objects of the template %s - %w",
+					template.TemplateName, err)
+			}
+			compliances = append(compliances, models.LocalPolicyTemplateCompliance{
+				PolicyID:       policy.PolicyID,
+				ClusterName:    policy.ClusterName,
+				LeafHubName:    leafHub,
+				TemplateName:   template.TemplateName,
+				TemplateKind:   template.TemplateKind,
+				ClusterID:      policy.ClusterID,
+				Compliance:     common.GetDatabaseCompliance(template.Compliance, h.log),
+				Message:        template.Message,
+				RelatedObjects: relatedObjects,
+				LastTimestamp:  template.LastTimestamp,
+			})
+		}
+	}
+	return compliances, nil
+}
+
+func templateKey(c models.LocalPolicyTemplateCompliance) string {
+	return c.PolicyID + "/" + c.ClusterName + "/" + c.TemplateKind + "/" + c.TemplateName
+}
@@ -83,6 +83,10 @@ func (h *policyMiniComplianceHandler) handleEvent(ctx context.Context, evt *clou
 			LeafHubName:          leafHub,
 			AppliedClusters:      minPolicyCompliance.AppliedClusters,
 			NonCompliantClusters: minPolicyCompliance.NonCompliantClusters,
+			CompliantClusters:    minPolicyCompliance.CompliantClusters,
+			PendingClusters:      minPolicyCompliance.PendingClusters,
+			UnknownClusters:      minPolicyCompliance.UnknownClusters,
+			RemediationAction:    string(minPolicyCompliance.RemediationAction),
 		}).Error
 		if err != nil {
 			return fmt.Errorf("failed to InsertUpdate minimal compliance of policy '%s', leaf hub '%s' in db - %w",
@@ -109,6 +113,17 @@ func (h *policyMiniComplianceHandler) handleEvent(ctx context.Context, evt *clou
 		}
 	}
 
+	// the managed hub is switched to the minimal level, remove the compliance of the full level, so that it isn't
+	// counted twice by the status.policy_compliance_summary
+	ret := db.Where("leaf_hub_name = ?", leafHub).Delete(&models.LocalStatusCompliance{})
+	if ret.Error != nil {
+		return fmt.Errorf("failed to delete the local compliance of leaf hub '%s' from db - %w", leafHub, ret.Error)
+	}
+	if ret.RowsAffected > 0 {
+		h.log.Infow("deleted the local compliance of the full aggregation level", "LH", leafHub,
+			"count", ret.RowsAffected)
+	}
+
 	h.log.Debugw(finishMessage, "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
 	return nil
 }
@@ -0,0 +1,87 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func RegisterProvisioningHandlers(conflationManager *conflator.ConflationManager) {
+	register(conflationManager, "ClusterDeployment", enum.ClusterDeploymentType,
+		conflator.ClusterDeploymentPriority, clusterDeploymentStatus)
+	register(conflationManager, "ClusterInstance", enum.ClusterInstanceType,
+		conflator.ClusterInstancePriority, clusterInstanceStatus)
+	register(conflationManager, "ClusterCurator", enum.ClusterCuratorType,
+		conflator.ClusterCuratorPriority, clusterCuratorStatus)
+}
+
+// register persists the lifecycle of the clusters derived from one kind of the provisioning resources
+func register(conflationManager *conflator.ConflationManager, kind string, eventType enum.EventType,
+	priority conflator.ConflationPriority, statusFunc func(obj *provisioningObject) provisioningStatus,
+) {
+	generic.RegisterBundleHandler(conflationManager, eventType, priority,
+		func(leafHubName string, obj *unstructured.Unstructured) (models.ClusterProvisioning, error) {
+			return toModel(leafHubName, kind, obj, statusFunc)
+		},
+		generic.WithKind(kind),
+		// the start time isn't in the resource once the curation is finished, so keep the existing one until the
+		// operation is changed
+		generic.WithOnConflict(clause.OnConflict{
+			Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "id"}},
+			DoUpdates: append(clause.AssignmentColumns([]string{
+				"kind", "cluster_name", "operation", "phase", "message", "completed_at", "failed_at", "payload",
+				"updated_at", "deleted_at",
+			}), clause.Assignment{
+				Column: clause.Column{Name: "started_at"},
+				Value: gorm.Expr("CASE WHEN cluster_provisioning.operation = excluded.operation " +
+					"THEN COALESCE(cluster_provisioning.started_at, excluded.started_at) ELSE excluded.started_at END"),
+			}),
+		}),
+		generic.WithDeleteFunc(markDeleted),
+	)
+}
+
+func toModel(leafHubName, kind string, obj *unstructured.Unstructured,
+	statusFunc func(obj *provisioningObject) provisioningStatus,
+) (models.ClusterProvisioning, error) {
+	o, err := toProvisioningObject(obj)
+	if err != nil {
+		return models.ClusterProvisioning{}, err
+	}
+	payload, err := json.Marshal(obj)
+	if err != nil {
+		return models.ClusterProvisioning{}, err
+	}
+	status := statusFunc(o)
+	return models.ClusterProvisioning{
+		LeafHubName: leafHubName,
+		ID:          string(obj.GetUID()),
+		Kind:        kind,
+		ClusterName: status.ClusterName,
+		Operation:   status.Operation,
+		Phase:       status.Phase,
+		Message:     status.Message,
+		StartedAt:   status.StartedAt,
+		CompletedAt: status.CompletedAt,
+		FailedAt:    status.FailedAt,
+		Payload:     payload,
+	}, nil
+}
+
+// markDeleted soft deletes the rows, the destroy operation is completed once the resource is deleted
+func markDeleted(tx *gorm.DB) error {
+	now := time.Now().UTC()
+	return tx.Updates(map[string]any{
+		"phase":        gorm.Expr("CASE WHEN operation = ? THEN ? ELSE phase END", OperationDestroy, PhaseCompleted),
+		"completed_at": gorm.Expr("CASE WHEN operation = ? THEN ? ELSE completed_at END", OperationDestroy, now),
+		"deleted_at":   now,
+	}).Error
+}
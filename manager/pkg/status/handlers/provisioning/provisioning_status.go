@@ -0,0 +1,225 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	OperationInstall = "Install"
+	OperationDestroy = "Destroy"
+
+	PhasePending    = "Pending"
+	PhaseInProgress = "InProgress"
+	PhaseCompleted  = "Completed"
+	PhaseFailed     = "Failed"
+
+	// the conditions of the hive ClusterDeployment, the ClusterInstall ones are copied from the AgentClusterInstall
+	provisionFailedCondition       = "ProvisionFailed"
+	provisionStoppedCondition      = "ProvisionStopped"
+	clusterInstallFailedCondition  = "ClusterInstallFailed"
+	clusterInstallStoppedCondition = "ClusterInstallStopped"
+	deprovisionLaunchErrCondition  = "DeprovisionLaunchError"
+
+	// the conditions of the siteconfig ClusterInstance
+	clusterInstanceValidatedCondition   = "ClusterInstanceValidated"
+	renderedTemplatesValidatedCondition = "RenderedTemplatesValidated"
+	renderedTemplatesAppliedCondition   = "RenderedTemplatesApplied"
+	provisionedCondition                = "Provisioned"
+	provisionedReasonCompleted          = "Completed"
+	provisionedReasonInProgress         = "InProgress"
+	failedReason                        = "Failed"
+	timedOutReason                      = "TimedOut"
+
+	// the condition of the ClusterCurator, it's False while the curation job is running
+	curatorJobCondition = "clustercurator-job"
+	curatorJobFailed    = "Job_failed"
+)
+
+// provisioningStatus is the lifecycle of a cluster derived from the provisioning resource
+type provisioningStatus struct {
+	ClusterName string
+	Operation   string
+	Phase       string
+	Message     string
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	FailedAt    *time.Time
+}
+
+// provisioningObject contains the fields of the slimmed ClusterDeployment, ClusterInstance and ClusterCurator
+type provisioningObject struct {
+	Metadata struct {
+		Name              string       `json:"name"`
+		CreationTimestamp metav1.Time  `json:"creationTimestamp"`
+		DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		ClusterName     string `json:"clusterName,omitempty"`
+		Installed       bool   `json:"installed,omitempty"`
+		DesiredCuration string `json:"desiredCuration,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		InstalledTimestamp *metav1.Time       `json:"installedTimestamp,omitempty"`
+		Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status"`
+}
+
+func toProvisioningObject(obj *unstructured.Unstructured) (*provisioningObject, error) {
+	payload, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	o := &provisioningObject{}
+	return o, json.Unmarshal(payload, o)
+}
+
+// clusterDeploymentStatus: the cluster is installed once the spec.installed is true, and the provision is failed only
+// when hive stops retrying it
+func clusterDeploymentStatus(obj *provisioningObject) provisioningStatus {
+	status := provisioningStatus{
+		ClusterName: obj.Metadata.Name,
+		Operation:   OperationInstall,
+		Phase:       PhaseInProgress,
+		StartedAt:   timeOf(&obj.Metadata.CreationTimestamp),
+	}
+	conditions := obj.Status.Conditions
+
+	if obj.Metadata.DeletionTimestamp != nil {
+		status.Operation = OperationDestroy
+		status.StartedAt = timeOf(obj.Metadata.DeletionTimestamp)
+		if cond := trueCondition(conditions, deprovisionLaunchErrCondition); cond != nil {
+			status.Message = cond.Message
+		}
+		return status
+	}
+
+	if obj.Spec.Installed {
+		status.Phase = PhaseCompleted
+		status.CompletedAt = timeOf(obj.Status.InstalledTimestamp)
+		return status
+	}
+
+	if cond := trueCondition(conditions, provisionStoppedCondition, clusterInstallStoppedCondition); cond != nil {
+		status.Phase = PhaseFailed
+		status.Message = cond.Message
+		status.FailedAt = timeOf(&cond.LastTransitionTime)
+		return status
+	}
+
+	// hive is still retrying the failed provision
+	if cond := trueCondition(conditions, provisionFailedCondition, clusterInstallFailedCondition); cond != nil {
+		status.Message = cond.Message
+	}
+	return status
+}
+
+// clusterInstanceStatus: the templates of the ClusterInstance are validated and applied first, then the Provisioned
+// condition tracks the installation of the cluster
+func clusterInstanceStatus(obj *provisioningObject) provisioningStatus {
+	status := provisioningStatus{
+		ClusterName: obj.Spec.ClusterName,
+		Operation:   OperationInstall,
+		Phase:       PhasePending,
+		StartedAt:   timeOf(&obj.Metadata.CreationTimestamp),
+	}
+	if status.ClusterName == "" {
+		status.ClusterName = obj.Metadata.Name
+	}
+	conditions := obj.Status.Conditions
+
+	if obj.Metadata.DeletionTimestamp != nil {
+		status.Operation = OperationDestroy
+		status.Phase = PhaseInProgress
+		status.StartedAt = timeOf(obj.Metadata.DeletionTimestamp)
+		return status
+	}
+
+	for _, condType := range []string{
+		clusterInstanceValidatedCondition, renderedTemplatesValidatedCondition, renderedTemplatesAppliedCondition,
+	} {
+		cond := meta.FindStatusCondition(conditions, condType)
+		if cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == failedReason {
+			status.Phase = PhaseFailed
+			status.Message = cond.Message
+			status.FailedAt = timeOf(&cond.LastTransitionTime)
+			return status
+		}
+	}
+
+	cond := meta.FindStatusCondition(conditions, provisionedCondition)
+	if cond == nil {
+		return status
+	}
+	switch cond.Reason {
+	case provisionedReasonCompleted:
+		status.Phase = PhaseCompleted
+		status.CompletedAt = timeOf(&cond.LastTransitionTime)
+	case failedReason, timedOutReason:
+		status.Phase = PhaseFailed
+		status.Message = cond.Message
+		status.FailedAt = timeOf(&cond.LastTransitionTime)
+	case provisionedReasonInProgress:
+		status.Phase = PhaseInProgress
+		status.Message = cond.Message
+	}
+	return status
+}
+
+// clusterCuratorStatus: the operation is the desired curation, the curation job condition is False while it's running
+// and True once it's finished or failed
+func clusterCuratorStatus(obj *provisioningObject) provisioningStatus {
+	status := provisioningStatus{
+		ClusterName: obj.Metadata.Name,
+		Operation:   capitalize(obj.Spec.DesiredCuration),
+		Phase:       PhasePending,
+	}
+
+	cond := meta.FindStatusCondition(obj.Status.Conditions, curatorJobCondition)
+	if cond == nil {
+		return status
+	}
+	switch {
+	case cond.Status == metav1.ConditionFalse:
+		status.Phase = PhaseInProgress
+		status.StartedAt = timeOf(&cond.LastTransitionTime)
+	case cond.Reason == curatorJobFailed:
+		status.Phase = PhaseFailed
+		status.Message = cond.Message
+		status.FailedAt = timeOf(&cond.LastTransitionTime)
+	default:
+		status.Phase = PhaseCompleted
+		status.CompletedAt = timeOf(&cond.LastTransitionTime)
+	}
+	return status
+}
+
+// trueCondition returns the first condition of the types whose status is True
+func trueCondition(conditions []metav1.Condition, condTypes ...string) *metav1.Condition {
+	for _, condType := range condTypes {
+		if cond := meta.FindStatusCondition(conditions, condType); cond != nil && cond.Status == metav1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
+
+func timeOf(t *metav1.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,141 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	created  = time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	finished = time.Date(2026, 10, 1, 11, 30, 0, 0, time.UTC)
+)
+
+func condition(condType, status, reason, message string) any {
+	return map[string]any{
+		"type": condType, "status": status, "reason": reason, "message": message,
+		"lastTransitionTime": finished.Format(time.RFC3339),
+	}
+}
+
+func newObject(t *testing.T, spec map[string]any, conditions ...any) *provisioningObject {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "sno1", "namespace": "sno1"},
+		"spec":     spec,
+		"status":   map[string]any{"conditions": conditions},
+	}}
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+	o, err := toProvisioningObject(obj)
+	require.NoError(t, err)
+	return o
+}
+
+func TestClusterDeploymentStatus(t *testing.T) {
+	t.Run("installing", func(t *testing.T) {
+		status := clusterDeploymentStatus(newObject(t, map[string]any{},
+			condition(provisionFailedCondition, "True", "InstallFailed", "retrying")))
+		assert.Equal(t, OperationInstall, status.Operation)
+		assert.Equal(t, PhaseInProgress, status.Phase)
+		assert.Equal(t, "retrying", status.Message)
+		assert.Equal(t, created, *status.StartedAt)
+	})
+
+	t.Run("installed", func(t *testing.T) {
+		obj := newObject(t, map[string]any{"installed": true})
+		obj.Status.InstalledTimestamp = &metav1.Time{Time: finished}
+		status := clusterDeploymentStatus(obj)
+		assert.Equal(t, PhaseCompleted, status.Phase)
+		assert.Equal(t, finished, *status.CompletedAt)
+		assert.Equal(t, 90*time.Minute, status.CompletedAt.Sub(*status.StartedAt))
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		status := clusterDeploymentStatus(newObject(t, map[string]any{},
+			condition(clusterInstallStoppedCondition, "True", "ClusterInstallStopped", "install failed")))
+		assert.Equal(t, PhaseFailed, status.Phase)
+		assert.Equal(t, "install failed", status.Message)
+		assert.Equal(t, finished, *status.FailedAt)
+	})
+
+	t.Run("destroying", func(t *testing.T) {
+		obj := newObject(t, map[string]any{"installed": true})
+		obj.Metadata.DeletionTimestamp = &metav1.Time{Time: finished}
+		status := clusterDeploymentStatus(obj)
+		assert.Equal(t, OperationDestroy, status.Operation)
+		assert.Equal(t, PhaseInProgress, status.Phase)
+		assert.Equal(t, finished, *status.StartedAt)
+	})
+}
+
+func TestClusterInstanceStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []any
+		phase      string
+	}{
+		{"pending", nil, PhasePending},
+		{
+			"invalid",
+			[]any{condition(clusterInstanceValidatedCondition, "False", failedReason, "missing the nodes")},
+			PhaseFailed,
+		},
+		{"provisioning", []any{condition(provisionedCondition, "False", provisionedReasonInProgress, "")}, PhaseInProgress},
+		{"provisioned", []any{condition(provisionedCondition, "True", provisionedReasonCompleted, "")}, PhaseCompleted},
+		{"timed out", []any{condition(provisionedCondition, "False", timedOutReason, "timed out")}, PhaseFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := clusterInstanceStatus(newObject(t, map[string]any{"clusterName": "sno1-cluster"},
+				tt.conditions...))
+			assert.Equal(t, "sno1-cluster", status.ClusterName)
+			assert.Equal(t, OperationInstall, status.Operation)
+			assert.Equal(t, tt.phase, status.Phase)
+		})
+	}
+}
+
+func TestClusterCuratorStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []any
+		phase       string
+		started     bool
+		completed   bool
+		failedAtSet bool
+	}{
+		{name: "pending", phase: PhasePending},
+		{
+			name:       "running",
+			conditions: []any{condition(curatorJobCondition, "False", "Job_has_started", "curator-job-abcde")},
+			phase:      PhaseInProgress,
+			started:    true,
+		},
+		{
+			name:       "finished",
+			conditions: []any{condition(curatorJobCondition, "True", "Job_has_finished", "")},
+			phase:      PhaseCompleted,
+			completed:  true,
+		},
+		{
+			name:        "failed",
+			conditions:  []any{condition(curatorJobCondition, "True", curatorJobFailed, "prehook failed")},
+			phase:       PhaseFailed,
+			failedAtSet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := clusterCuratorStatus(newObject(t, map[string]any{"desiredCuration": "upgrade"},
+				tt.conditions...))
+			assert.Equal(t, "Upgrade", status.Operation)
+			assert.Equal(t, tt.phase, status.Phase)
+			assert.Equal(t, tt.started, status.StartedAt != nil)
+			assert.Equal(t, tt.completed, status.CompletedAt != nil)
+			assert.Equal(t, tt.failedAtSet, status.FailedAt != nil)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package remediationresult
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/remediation"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
+	remediationbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/remediation"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+type remediationResultHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterRemediationResultHandler handles the results of the remediation requested by the PolicyRemediationRequest
+func RegisterRemediationResultHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.PolicyRemediationResultType)
+	h := &remediationResultHandler{
+		log:           logger.ZapLogger(strings.ReplaceAll(eventType, enum.EventTypePrefix, "")),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode, // each result is for a different remediation, handle them one by one
+		eventPriority: conflator.PolicyRemediationResultPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *remediationResultHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	bundle := &remediationbundle.PolicyRemediationResultBundle{}
+	if err := evt.DataAs(bundle); err != nil {
+		h.log.Warnw("failed to unmarshal the remediation result bundle", "LH", evt.Source(), "version", version,
+			"error", err)
+		return nil
+	}
+	if bundle.ID == "" {
+		h.log.Warnw("the remediation result bundle has no id", "LH", evt.Source(), "version", version)
+		return nil
+	}
+
+	remediation.SetReported(evt.Source(), bundle)
+
+	errMessages := []string{}
+	if bundle.Error != "" {
+		errMessages = append(errMessages, bundle.Error)
+	}
+	for _, policy := range bundle.Policies {
+		if policy.Result == remediationv1alpha1.ResultFailed {
+			errMessages = append(errMessages, policy.Namespace+"/"+policy.Name+": "+policy.Message)
+		}
+	}
+	if err := audit.Acknowledge(evt.Source(), bundle.ID, strings.Join(errMessages, "; ")); err != nil {
+		h.log.Warnw("failed to acknowledge the audited remediation", "LH", evt.Source(), "id", bundle.ID,
+			"error", err)
+	}
+	h.log.Infow("the managed hub reported the remediation results", "LH", evt.Source(), "id", bundle.ID)
+	return nil
+}
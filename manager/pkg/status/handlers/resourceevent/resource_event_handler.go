@@ -0,0 +1,110 @@
+package resourceevent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const BatchSize = 100
+
+// the columns of the unique constraint of the event.resource_events
+var conflictColumns = []clause.Column{
+	{Name: "leaf_hub_name"}, {Name: "event_namespace"}, {Name: "event_name"}, {Name: "created_at"},
+}
+
+type resourceEventHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterResourceEventHandler handles the events of the involved objects declared by the agents, they're stored in
+// the event.resource_events with the GVK of the involved objects
+func RegisterResourceEventHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ResourceEventType)
+	h := &resourceEventHandler{
+		log:           logger.ZapLogger(strings.ReplaceAll(eventType, enum.EventTypePrefix, "")),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.ResourceEventPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *resourceEventHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	resourceEvents := event.ResourceEventBundle{}
+	if evt.Extensions()[constants.CloudEventExtensionSendMode] == string(constants.EventSendModeSingle) {
+		singleEvent := &models.ResourceEvent{}
+		if err := evt.DataAs(singleEvent); err != nil {
+			return err
+		}
+		resourceEvents = append(resourceEvents, singleEvent)
+	} else if err := evt.DataAs(&resourceEvents); err != nil {
+		h.log.Warnw("failed to unmarshal bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	resourceEvents = dedupResourceEvents(resourceEvents, leafHubName)
+	if len(resourceEvents) == 0 {
+		h.log.Debugw("empty resource event payload", "LH", leafHubName, "version", version)
+		return nil
+	}
+
+	err := database.GetGorm().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   conflictColumns,
+		DoNothing: true,
+	}).CreateInBatches(resourceEvents, BatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed handling the resource events - %w", err)
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+// dedupResourceEvents sets the leaf hub of the events, and keeps the last one of the events with the same unique key,
+// since a batch insert can't affect the same row twice
+func dedupResourceEvents(resourceEvents event.ResourceEventBundle, leafHubName string) event.ResourceEventBundle {
+	index := map[string]int{}
+	deduped := make(event.ResourceEventBundle, 0, len(resourceEvents))
+	for _, resourceEvent := range resourceEvents {
+		if resourceEvent == nil {
+			continue
+		}
+		resourceEvent.LeafHubName = leafHubName
+		key := fmt.Sprintf("%s/%s/%s", resourceEvent.EventNamespace, resourceEvent.EventName,
+			resourceEvent.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"))
+		if i, ok := index[key]; ok {
+			deduped[i] = resourceEvent
+			continue
+		}
+		index[key] = len(deduped)
+		deduped = append(deduped, resourceEvent)
+	}
+	return deduped
+}
@@ -0,0 +1,29 @@
+package resourceevent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestDedupResourceEvents(t *testing.T) {
+	now := time.Now()
+	resourceEvents := event.ResourceEventBundle{
+		{EventNamespace: "ns1", EventName: "event1", Count: 1, CreatedAt: now},
+		nil,
+		{EventNamespace: "ns1", EventName: "event2", Count: 1, CreatedAt: now},
+		{EventNamespace: "ns1", EventName: "event1", Count: 2, CreatedAt: now},
+		{EventNamespace: "ns1", EventName: "event1", Count: 3, CreatedAt: now.Add(time.Second)},
+	}
+
+	deduped := dedupResourceEvents(resourceEvents, "hub1")
+	assert.Equal(t, []*models.ResourceEvent{
+		{LeafHubName: "hub1", EventNamespace: "ns1", EventName: "event1", Count: 2, CreatedAt: now},
+		{LeafHubName: "hub1", EventNamespace: "ns1", EventName: "event2", Count: 1, CreatedAt: now},
+		{LeafHubName: "hub1", EventNamespace: "ns1", EventName: "event1", Count: 3, CreatedAt: now.Add(time.Second)},
+	}, []*models.ResourceEvent(deduped))
+}
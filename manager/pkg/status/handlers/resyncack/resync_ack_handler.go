@@ -0,0 +1,70 @@
+package resyncack
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/resync"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	resyncbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/resync"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+type resyncAckHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterResyncAckHandler handles the acknowledgements of the resync requested by the GlobalHubResync
+func RegisterResyncAckHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ResyncAckType)
+	h := &resyncAckHandler{
+		log:           logger.ZapLogger(strings.ReplaceAll(eventType, enum.EventTypePrefix, "")),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode, // each acknowledgement is for a different resync, handle them one by one
+		eventPriority: conflator.ResyncAckPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *resyncAckHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	bundle := &resyncbundle.ResyncAckBundle{}
+	if err := evt.DataAs(bundle); err != nil {
+		h.log.Warnw("failed to unmarshal the resync ack bundle", "LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+	if bundle.ID == "" {
+		h.log.Warnw("the resync ack bundle has no id", "LH", evt.Source(), "version", version)
+		return nil
+	}
+
+	resync.SetAcknowledged(evt.Source(), bundle)
+
+	errMessages := []string{}
+	for _, eventType := range bundle.EventTypes {
+		if eventType.Error != "" {
+			errMessages = append(errMessages, eventType.EventType+": "+eventType.Error)
+		}
+	}
+	if err := audit.Acknowledge(evt.Source(), bundle.ID, strings.Join(errMessages, "; ")); err != nil {
+		h.log.Warnw("failed to acknowledge the audited resync", "LH", evt.Source(), "id", bundle.ID, "error", err)
+	}
+	h.log.Infow("the managed hub acknowledged the resync", "LH", evt.Source(), "id", bundle.ID)
+	return nil
+}
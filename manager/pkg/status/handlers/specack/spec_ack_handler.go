@@ -0,0 +1,72 @@
+package specack
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/delivery"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/specack"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+type specAckHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterSpecAckHandler handles the acknowledgements of the spec events sent to the managed hubs
+func RegisterSpecAckHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SpecAckType)
+	h := &specAckHandler{
+		log:           logger.ZapLogger(strings.ReplaceAll(eventType, enum.EventTypePrefix, "")),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode, // each acknowledgement is for a different spec event
+		eventPriority: conflator.SpecAckPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *specAckHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	ack := &specack.SpecAckBundle{}
+	if err := evt.DataAs(ack); err != nil {
+		h.log.Warnw("failed to unmarshal the spec ack bundle", "LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+	if ack.CorrelationID == "" {
+		h.log.Warnw("the spec ack bundle has no correlation id", "LH", evt.Source(), "version", version)
+		return nil
+	}
+
+	delivery.Acknowledge(evt.Source(), ack)
+	if ack.Denied {
+		if err := audit.Deny(evt.Source(), ack); err != nil {
+			h.log.Warnw("failed to record the denied action", "LH", evt.Source(), "correlationId",
+				ack.CorrelationID, "error", err)
+		}
+	} else if err := audit.Acknowledge(evt.Source(), ack.CorrelationID, ack.Error); err != nil {
+		h.log.Warnw("failed to acknowledge the audited action", "LH", evt.Source(), "correlationId",
+			ack.CorrelationID, "error", err)
+	}
+	if !ack.Success {
+		h.log.Warnw("the managed hub failed to handle the spec event", "LH", evt.Source(), "type", ack.EventType,
+			"correlationId", ack.CorrelationID, "error", ack.Error)
+	}
+	return nil
+}
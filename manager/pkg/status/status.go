@@ -6,6 +6,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/resync"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/dispatcher"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers"
@@ -34,6 +35,8 @@ func AddStatusSyncers(
 
 	// manage all Conflation Units and handlers
 	conflationManager := conflator.NewConflationManager(stats, requester)
+	// the hybrid bundles are resynced once the gaps of the deltas are detected
+	conflationManager.SetCompleteRequester(resync.RequestResync)
 	handlers.RegisterHandlers(mgr, conflationManager)
 
 	// start consume message from transport to conflation manager
@@ -117,6 +117,11 @@ type MulticlusterGlobalHubSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	// +optional
 	InstallAgentOnLocal bool `json:"installAgentOnLocal"`
+	// Tracing specifies the OpenTelemetry collector to export the traces of the status events, they are traced from
+	// the agents through the transport to the database. The traces aren't exported if it isn't specified
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tracing *TracingSpec `json:"tracing,omitempty"`
 
 	// // FeatureGates represents a list of configurable feature gates.
 	// // +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -124,6 +129,24 @@ type MulticlusterGlobalHubSpec struct {
 	// FeatureGates []FeatureGate `json:"featureGates,omitempty"`
 }
 
+// TracingSpec defines the OTLP exporter of the traces
+type TracingSpec struct {
+	// Endpoint is the OTLP gRPC endpoint of the collector, such as "otel-collector.observability.svc:4317"
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Insecure disables the TLS of the connection to the collector
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// SamplingPercentage is the percentage of the traces sampled to export, the default value is 100
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SamplingPercentage int32 `json:"samplingPercentage,omitempty"`
+}
+
 type FeatureGate struct {
 	// ImportClusterInHosted enables importing managed hub clusters in hosted mode.
 	// +kubebuilder:validation:Enum=ImportClusterInHosted
@@ -200,11 +223,74 @@ type PostgresSpec struct {
 	// +kubebuilder:default:="18m"
 	Retention string `json:"retention,omitempty"`
 
+	// RetentionPolicy overrides the retention for the specific kinds of data in the database.
+	// Each value is a duration string in the same format as the retention, such as "3m" or "7y".
+	// The unset values fall back to the retention.
+	// +optional
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// Archive specifies where to export the expired partitions before they are dropped from the database.
+	// The partitions aren't archived if it isn't specified
+	// +optional
+	Archive *ArchiveSpec `json:"archive,omitempty"`
+
 	// StorageSize specifies the size for storage
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
 }
 
+// ArchiveSpec defines the storage of the archived partitions. The partitions are exported as gzip compressed
+// CSV files. Only one of the S3 and PersistentVolumeClaim should be specified
+type ArchiveSpec struct {
+	// S3 specifies an S3-compatible bucket to store the archived partitions
+	// +optional
+	S3 *S3ArchiveSpec `json:"s3,omitempty"`
+
+	// PersistentVolumeClaim is the name of an existing claim in the global hub namespace,
+	// it's mounted to the manager to store the archived partitions
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+}
+
+// S3ArchiveSpec defines an S3-compatible bucket
+type S3ArchiveSpec struct {
+	// Endpoint is the URL of the S3-compatible service, such as "https://s3.us-east-1.amazonaws.com"
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// Region is the region of the bucket, the default value is "us-east-1"
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Prefix is prepended to the keys of the archived objects
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecret is the name of the secret in the global hub namespace,
+	// it contains the "access_key_id" and "secret_access_key" of the bucket
+	// +kubebuilder:validation:Required
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// RetentionPolicy defines how long to keep each kind of data in the database
+type RetentionPolicy struct {
+	// Events is the retention for the event tables, such as "event.local_policies" and "event.managed_clusters"
+	// +optional
+	Events string `json:"events,omitempty"`
+
+	// History is the retention for the history tables, such as "history.local_compliance"
+	// +optional
+	History string `json:"history,omitempty"`
+
+	// SoftDeleted is the retention for the soft deleted records, such as the deleted managed clusters and policies
+	// +optional
+	SoftDeleted string `json:"softDeleted,omitempty"`
+}
+
 // KafkaSpec defines the desired state of kafka
 type KafkaSpec struct {
 	// KafkaTopics specify the desired topics
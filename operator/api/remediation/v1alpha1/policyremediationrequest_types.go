@@ -0,0 +1,219 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Remediation Actions
+const (
+	// ActionEnforce switches the remediationAction of the policies to enforce for the enforce duration
+	ActionEnforce = "Enforce"
+	// ActionReevaluate triggers the policy propagator to reprocess the policies
+	ActionReevaluate = "Reevaluate"
+	// ActionCreateAutomation creates the PolicyAutomation to run the ansible job for the policies
+	ActionCreateAutomation = "CreateAutomation"
+)
+
+// The base64 encoded requester of the remediation, they're set by the global hub webhook once the request is created,
+// and the managed hubs impersonate the requester to run the action
+const (
+	UserIdentityAnnotation = "open-cluster-management.io/user-identity"
+	UserGroupsAnnotation   = "open-cluster-management.io/user-group"
+)
+
+// Remediation Phases
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseCompleted = "Completed"
+	PhaseFailed    = "Failed"
+)
+
+// Remediation Condition Types
+const (
+	ConditionTypeRequested = "RemediationRequested"
+	ConditionTypeReported  = "RemediationReported"
+)
+
+// Remediation Results of the policies
+const (
+	ResultSucceeded = "Succeeded"
+	ResultFailed    = "Failed"
+	ResultSkipped   = "Skipped"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName={prr}
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="The remediation action"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="The overall status of the remediation"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// PolicyRemediationRequest is a global hub resource that requests the managed hubs to remediate their local
+// policies, the managed hubs run the action as the user who creates the request and report the results back
+type PolicyRemediationRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of policyremediationrequest
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec PolicyRemediationRequestSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of policyremediationrequest
+	Status PolicyRemediationRequestStatus `json:"status,omitempty"`
+}
+
+// PolicyRemediationRequestSpec defines the desired state of policyremediationrequest
+type PolicyRemediationRequestSpec struct {
+	// Action is the remediation action to run for the policies
+	// +kubebuilder:validation:Enum=Enforce;Reevaluate;CreateAutomation
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Action string `json:"action"`
+
+	// Policies are the local policies to remediate on the managed hubs
+	// +kubebuilder:validation:MinItems=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Policies []PolicyReference `json:"policies"`
+
+	// ManagedHubs are the managed hubs to remediate the policies, all the managed hubs which have the policies are
+	// remediated if it's empty
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ManagedHubs []string `json:"managedHubs,omitempty"`
+
+	// Clusters are the managed clusters of the managed hubs, the policies are only remediated if they're propagated
+	// to any of the clusters, and the clusters are passed to the ansible job of the automation as target_clusters
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Clusters []string `json:"clusters,omitempty"`
+
+	// EnforceDuration is the time window to enforce the policies for the Enforce action, the remediationAction of
+	// the policies are reverted after it, the default value is 1h
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	EnforceDuration *metav1.Duration `json:"enforceDuration,omitempty"`
+
+	// Automation is the PolicyAutomation to create for the CreateAutomation action
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Automation *AutomationSpec `json:"automation,omitempty"`
+
+	// Timeout is the duration to wait for the results of the managed hubs, the default value is 5m
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PolicyReference is the namespaced name of a local policy on the managed hub
+type PolicyReference struct {
+	// Namespace is the namespace of the policy
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the policy
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// AutomationSpec is the ansible job run by the PolicyAutomation of the policy
+type AutomationSpec struct {
+	// Mode specifies how often the automation is initiated, the default value is once
+	// +kubebuilder:validation:Enum=once;everyEvent;disabled
+	// +kubebuilder:default=once
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// TemplateName is the name of the ansible template to run in the ansible automation platform
+	// +kubebuilder:validation:MinLength=1
+	TemplateName string `json:"templateName"`
+
+	// TowerSecret is the name of the secret on the managed hub that contains the ansible automation platform
+	// credential, it must be in the namespace of the policy
+	// +kubebuilder:validation:MinLength=1
+	TowerSecret string `json:"towerSecret"`
+}
+
+// PolicyRemediationRequestStatus defines the observed state of policyremediationrequest
+type PolicyRemediationRequestStatus struct {
+	// Phase represents the current phase of the remediation
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Phase string `json:"phase,omitempty"`
+
+	// StartTime is the time when the remediation is requested to the managed hubs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the remediation is completed or failed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ManagedHubs are the remediation results of the managed hubs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ManagedHubs []ManagedHubRemediationStatus `json:"managedHubs,omitempty"`
+
+	// Conditions represents the latest available observations of the current state
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ManagedHubRemediationStatus is the remediation result of a managed hub
+type ManagedHubRemediationStatus struct {
+	// Name is the name of the managed hub
+	Name string `json:"name"`
+
+	// ReportedTime is the time when the managed hub reported the results
+	// +optional
+	ReportedTime *metav1.Time `json:"reportedTime,omitempty"`
+
+	// Message is the reason why the managed hub rejected the request, e.g. the user isn't authorized
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Policies are the remediation results of the policies
+	// +optional
+	Policies []PolicyRemediationStatus `json:"policies,omitempty"`
+}
+
+// PolicyRemediationStatus is the remediation result of a policy on a managed hub
+type PolicyRemediationStatus struct {
+	// Namespace is the namespace of the policy
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the policy
+	Name string `json:"name"`
+
+	// Result is the result of the remediation action
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Skipped
+	Result string `json:"result"`
+
+	// Message is the detail of the result, e.g. the error of the action
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// PolicyRemediationRequestList contains a list of policyremediationrequest
+type PolicyRemediationRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyRemediationRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyRemediationRequest{}, &PolicyRemediationRequestList{})
+}
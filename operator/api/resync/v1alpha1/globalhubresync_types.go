@@ -0,0 +1,142 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resync Phases
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseCompleted = "Completed"
+	PhaseFailed    = "Failed"
+)
+
+// Resync Condition Types
+const (
+	ConditionTypeRequested    = "ResyncRequested"
+	ConditionTypeAcknowledged = "ResyncAcknowledged"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName={ghr}
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="The overall status of the resync"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// GlobalHubResync is a global hub resource that requests the managed hubs to resync the selected event types, it
+// reports the acknowledgements of the managed hubs and the row count deltas of the database
+type GlobalHubResync struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalhubresync
+	Spec GlobalHubResyncSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalhubresync
+	Status GlobalHubResyncStatus `json:"status,omitempty"`
+}
+
+// GlobalHubResyncSpec defines the desired state of globalhubresync
+type GlobalHubResyncSpec struct {
+	// ManagedHubs are the managed hubs to resync, all the active managed hubs are resynced if it's empty
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ManagedHubs []string `json:"managedHubs,omitempty"`
+
+	// EventTypes are the event types to resync, e.g. managedcluster, policy.localspec or managedhub.info
+	// +kubebuilder:validation:MinItems=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	EventTypes []string `json:"eventTypes"`
+
+	// Timeout is the duration to wait for the acknowledgements of the managed hubs, the default value is 5m
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GlobalHubResyncStatus defines the observed state of globalhubresync
+type GlobalHubResyncStatus struct {
+	// Phase represents the current phase of the resync
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Phase string `json:"phase,omitempty"`
+
+	// StartTime is the time when the resync is requested to the managed hubs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the resync is completed or failed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ManagedHubs are the resync results of the managed hubs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ManagedHubs []ManagedHubResyncStatus `json:"managedHubs,omitempty"`
+
+	// Conditions represents the latest available observations of the current state
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ManagedHubResyncStatus is the resync result of a managed hub
+type ManagedHubResyncStatus struct {
+	// Name is the name of the managed hub
+	Name string `json:"name"`
+
+	// AcknowledgedTime is the time when the managed hub acknowledged that the event types are resynced
+	// +optional
+	AcknowledgedTime *metav1.Time `json:"acknowledgedTime,omitempty"`
+
+	// EventTypes are the resync results of the event types
+	// +optional
+	EventTypes []EventTypeResyncStatus `json:"eventTypes,omitempty"`
+}
+
+// EventTypeResyncStatus is the resync result of an event type on a managed hub
+type EventTypeResyncStatus struct {
+	// EventType is the resynced event type
+	EventType string `json:"eventType"`
+
+	// ResyncedObjects is the number of the objects resent by the managed hub
+	// +optional
+	ResyncedObjects int `json:"resyncedObjects,omitempty"`
+
+	// RowCount is the number of the rows of the managed hub in the database before the resync
+	// +optional
+	RowCount *int64 `json:"rowCount,omitempty"`
+
+	// RowCountDelta is the change of the rows of the managed hub in the database after the resync
+	// +optional
+	RowCountDelta *int64 `json:"rowCountDelta,omitempty"`
+
+	// Message is the reason why the event type isn't resynced
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubResyncList contains a list of globalhubresync
+type GlobalHubResyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubResync `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubResync{}, &GlobalHubResyncList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Tenant Condition Types
+const (
+	ConditionTypeDatabaseReady = "DatabaseReady"
+	ConditionTypeGrafanaReady  = "GrafanaReady"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName={ght}
+// +kubebuilder:printcolumn:name="DatabaseUser",type="string",JSONPath=".status.databaseUser",description="The database user of the tenant"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// GlobalHubTenant is a global hub resource that scopes the users and groups to a set of the managed hubs, they can
+// only query the data of these managed hubs from the database and grafana
+type GlobalHubTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalhubtenant
+	Spec GlobalHubTenantSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalhubtenant
+	Status GlobalHubTenantStatus `json:"status,omitempty"`
+}
+
+// GlobalHubTenantSpec defines the desired state of globalhubtenant
+// +kubebuilder:validation:XValidation:rule="has(self.managedHubs) || has(self.managedHubSelector)",message="at least one of managedHubs or managedHubSelector must be specified"
+type GlobalHubTenantSpec struct {
+	// Users are the users who belong to the tenant
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Users []string `json:"users,omitempty"`
+
+	// Groups are the user groups who belong to the tenant, the members of the groups are resolved from the
+	// user.openshift.io groups
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Groups []string `json:"groups,omitempty"`
+
+	// ManagedHubs is an explicit list of the managed hubs which the tenant can access
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ManagedHubs []string `json:"managedHubs,omitempty"`
+
+	// ManagedHubSelector selects the managed hubs which the tenant can access by the labels of their
+	// managed clusters in the global hub
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ManagedHubSelector *metav1.LabelSelector `json:"managedHubSelector,omitempty"`
+}
+
+// GlobalHubTenantStatus defines the observed state of globalhubtenant
+type GlobalHubTenantStatus struct {
+	// ManagedHubs are the resolved managed hubs which the tenant can access
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ManagedHubs []string `json:"managedHubs,omitempty"`
+
+	// DatabaseUser is the database role of the tenant, its rows are restricted to the managed hubs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	DatabaseUser string `json:"databaseUser,omitempty"`
+
+	// DatabaseSecret is the secret of the database connection for the tenant in the global hub namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	DatabaseSecret string `json:"databaseSecret,omitempty"`
+
+	// GrafanaOrgID is the id of the grafana organization of the tenant
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	GrafanaOrgID int64 `json:"grafanaOrgId,omitempty"`
+
+	// Conditions represents the latest available observations of the current state
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubTenantList contains a list of globalhubtenant
+type GlobalHubTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubTenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubTenant{}, &GlobalHubTenantList{})
+}
@@ -106,6 +106,10 @@ type ManifestsConfig struct {
 	Resources                 *Resources
 	EnableStackroxIntegration bool
 	StackroxPollInterval      time.Duration
+	// the OTLP exporter of the traces, the endpoint must be reachable from the managed hubs
+	TracingEndpoint           string
+	TracingInsecure           bool
+	TracingSamplingPercentage int32
 
 	ImagePullSecretName     string
 	ImagePullSecretData     string
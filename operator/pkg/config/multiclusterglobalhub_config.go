@@ -177,6 +177,11 @@ func getAnnotation(obj client.Object, annotationKey string) string {
 	return annotations[annotationKey]
 }
 
+// IsDatabaseMigrationDryRun returns true if the database migrations should only be planned
+func IsDatabaseMigrationDryRun(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	return strings.EqualFold(getAnnotation(mgh, operatorconstants.AnnotationDatabaseMigrationDryRun), "true")
+}
+
 // IsPaused returns true if the MulticlusterGlobalHub instance is annotated as paused, and false otherwise
 func IsPaused(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	isPausedVal := getAnnotation(mgh, operatorconstants.AnnotationMGHPause)
@@ -245,6 +250,11 @@ func GetSchedulerInterval(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationMGHSchedulerInterval)
 }
 
+// GetClusterConflictResolution returns the resolution of the cluster claimed by several managed hubs
+func GetClusterConflictResolution(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	return getAnnotation(mgh, operatorconstants.AnnotationClusterConflictResolution)
+}
+
 func GetInstallCrunchyOperator(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	toInstallCrunchyOperator := getAnnotation(mgh, operatorconstants.AnnotationMGHInstallCrunchyOperator)
 	if toInstallCrunchyOperator != "" && strings.EqualFold(toInstallCrunchyOperator, "true") {
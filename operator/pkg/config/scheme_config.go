@@ -7,6 +7,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	userv1 "github.com/openshift/api/user/v1"
 	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	agentv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
@@ -26,6 +27,7 @@ import (
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
 	globalhubv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha1"
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
 )
 
 func GetRuntimeScheme() *runtime.Scheme {
@@ -51,6 +53,8 @@ func GetRuntimeScheme() *runtime.Scheme {
 	utilruntime.Must(imagev1.AddToScheme(scheme))
 	utilruntime.Must(spicedbv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(migrationv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(tenantv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(userv1.AddToScheme(scheme))
 
 	// add Kafka scheme
 	utilruntime.Must(kafkav1beta2.AddToScheme(scheme))
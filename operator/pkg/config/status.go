@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
 )
 
 const (
@@ -76,6 +77,11 @@ const (
 	CONDITION_TYPE_RETENTION_PARSED          = "DataRetentionParsed"
 	CONDITION_REASON_RETENTION_PARSED        = "DataRetentionParsed"
 	CONDITION_REASON_RETENTION_PARSED_FAILED = "DataRetentionParsedFailed"
+
+	CONDITION_TYPE_DATABASE_MIGRATED            = "DatabaseMigrated"
+	CONDITION_REASON_DATABASE_MIGRATED          = "DatabaseMigrated"
+	CONDITION_REASON_DATABASE_MIGRATION_PENDING = "DatabaseMigrationPending"
+	CONDITION_REASON_DATABASE_MIGRATION_FAILED  = "DatabaseMigrationFailed"
 )
 
 // NOTE: the status of ManagerDeployed can only be True; otherwise there is no condition
@@ -375,3 +381,23 @@ func GetComponentStatusWithReconcileError(ctx context.Context, c client.Client,
 	}
 	return GetDeploymentComponentStatus(ctx, c, namespace, name)
 }
+
+// UpdateTenantStatus updates the status of the global hub tenant by the mutate func, the tenant status is shared by
+// the storage and grafana controllers, so it's updated on the latest object to avoid overwriting each other
+func UpdateTenantStatus(ctx context.Context, c client.Client, name string,
+	mutate func(status *tenantv1alpha1.GlobalHubTenantStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		tenant := &tenantv1alpha1.GlobalHubTenant{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, tenant); err != nil {
+			return err
+		}
+		desired := tenant.Status.DeepCopy()
+		mutate(desired)
+		if reflect.DeepEqual(desired, &tenant.Status) {
+			return nil
+		}
+		tenant.Status = *desired
+		return c.Status().Update(ctx, tenant)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -39,6 +41,12 @@ type PostgresConnection struct {
 	// readonly user connection
 	// it is used for read the database by the grafana
 	ReadonlyUserDatabaseURI string
+	// the superuser connections of the read replicas
+	// they are used by the manager to route the read-only queries
+	ReplicaDatabaseURIs []string
+	// the readonly user connection of the read replica
+	// it is used for read the database by the grafana instead of the primary
+	ReadonlyUserReplicaDatabaseURI string
 	// ca certificate
 	CACert []byte
 }
@@ -77,6 +85,83 @@ func GetDatabaseReady() bool {
 	return databaseReady
 }
 
+// RetentionMonths is the number of months to keep each kind of data in the database
+type RetentionMonths struct {
+	Events      int
+	History     int
+	SoftDeleted int
+}
+
+func (r *RetentionMonths) String() string {
+	return fmt.Sprintf("events: %d months, history: %d months, soft deleted records: %d months",
+		r.Events, r.History, r.SoftDeleted)
+}
+
+// GetRetentionMonths parses the retention and the retention policy of the postgres spec. The unset values in the
+// retention policy fall back to the retention. The retention should at least be 1 month, otherwise it will delete the
+// current month partitions and records
+func GetRetentionMonths(mgh *v1alpha4.MulticlusterGlobalHub) (*RetentionMonths, error) {
+	postgresSpec := mgh.Spec.DataLayerSpec.Postgres
+	months, err := parseRetentionMonth("retention", postgresSpec.Retention, 0)
+	if err != nil {
+		return nil, err
+	}
+	retentionMonths := &RetentionMonths{
+		Events:      months,
+		History:     months,
+		SoftDeleted: months,
+	}
+	policy := postgresSpec.RetentionPolicy
+	if policy == nil {
+		return retentionMonths, nil
+	}
+
+	if retentionMonths.Events, err = parseRetentionMonth("retentionPolicy.events", policy.Events,
+		months); err != nil {
+		return nil, err
+	}
+	if retentionMonths.History, err = parseRetentionMonth("retentionPolicy.history", policy.History,
+		months); err != nil {
+		return nil, err
+	}
+	if retentionMonths.SoftDeleted, err = parseRetentionMonth("retentionPolicy.softDeleted", policy.SoftDeleted,
+		months); err != nil {
+		return nil, err
+	}
+	return retentionMonths, nil
+}
+
+// ValidateArchive validates the storage of the archived partitions, only one of the s3 and persistent volume claim
+// can be specified
+func ValidateArchive(archive *v1alpha4.ArchiveSpec) error {
+	if archive == nil {
+		return nil
+	}
+	if archive.S3 != nil && archive.PersistentVolumeClaim != "" {
+		return fmt.Errorf("only one of the s3 and persistentVolumeClaim can be specified for the archive")
+	}
+	if archive.S3 != nil && (archive.S3.Endpoint == "" || archive.S3.Bucket == "" ||
+		archive.S3.CredentialsSecret == "") {
+		return fmt.Errorf("the endpoint, bucket and credentialsSecret are required for the s3 archive")
+	}
+	return nil
+}
+
+// parseRetentionMonth returns the fallback months if the retention is empty and the fallback is specified
+func parseRetentionMonth(field, retention string, fallback int) (int, error) {
+	if retention == "" && fallback > 0 {
+		return fallback, nil
+	}
+	months, err := utils.ParseRetentionMonth(retention)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the %s: %w", field, err)
+	}
+	if months < 1 {
+		months = 1
+	}
+	return months, nil
+}
+
 // GeneratePGConnectionFromGHStorageSecret returns a postgres connection from the GH storage secret
 func GetPGConnectionFromGHStorageSecret(ctx context.Context, client client.Client) (
 	*PostgresConnection, error,
@@ -90,12 +175,25 @@ func GetPGConnectionFromGHStorageSecret(ctx context.Context, client client.Clien
 		return nil, err
 	}
 	return &PostgresConnection{
-		SuperuserDatabaseURI:    string(pgSecret.Data["database_uri"]),
-		ReadonlyUserDatabaseURI: string(pgSecret.Data["database_uri_with_readonlyuser"]),
-		CACert:                  pgSecret.Data["ca.crt"],
+		SuperuserDatabaseURI:           string(pgSecret.Data["database_uri"]),
+		ReadonlyUserDatabaseURI:        string(pgSecret.Data["database_uri_with_readonlyuser"]),
+		ReplicaDatabaseURIs:            parseReplicaURIs(string(pgSecret.Data["database_replica_uris"])),
+		ReadonlyUserReplicaDatabaseURI: string(pgSecret.Data["database_replica_uri_with_readonlyuser"]),
+		CACert:                         pgSecret.Data["ca.crt"],
 	}, nil
 }
 
+// parseReplicaURIs splits the replica uris, which are separated by comma or newline
+func parseReplicaURIs(uris string) []string {
+	var replicaURIs []string
+	for _, uri := range strings.FieldsFunc(uris, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			replicaURIs = append(replicaURIs, uri)
+		}
+	}
+	return replicaURIs
+}
+
 func GetPGConnectionFromBuildInPostgres(ctx context.Context, client client.Client) (
 	*PostgresConnection, error,
 ) {
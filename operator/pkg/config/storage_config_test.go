@@ -0,0 +1,136 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+)
+
+func TestGetRetentionMonths(t *testing.T) {
+	tests := []struct {
+		name     string
+		postgres v1alpha4.PostgresSpec
+		want     *RetentionMonths
+		wantErr  bool
+	}{
+		{
+			name:     "only the retention",
+			postgres: v1alpha4.PostgresSpec{Retention: "18m"},
+			want:     &RetentionMonths{Events: 18, History: 18, SoftDeleted: 18},
+		},
+		{
+			name: "retention policy overrides the retention",
+			postgres: v1alpha4.PostgresSpec{
+				Retention: "18m",
+				RetentionPolicy: &v1alpha4.RetentionPolicy{
+					Events:      "3m",
+					History:     "7y",
+					SoftDeleted: "1m",
+				},
+			},
+			want: &RetentionMonths{Events: 3, History: 84, SoftDeleted: 1},
+		},
+		{
+			name: "unset retention policy falls back to the retention",
+			postgres: v1alpha4.PostgresSpec{
+				Retention:       "1y",
+				RetentionPolicy: &v1alpha4.RetentionPolicy{History: "84m"},
+			},
+			want: &RetentionMonths{Events: 12, History: 84, SoftDeleted: 12},
+		},
+		{
+			name: "retention should at least be 1 month",
+			postgres: v1alpha4.PostgresSpec{
+				Retention:       "0m",
+				RetentionPolicy: &v1alpha4.RetentionPolicy{Events: "0m"},
+			},
+			want: &RetentionMonths{Events: 1, History: 1, SoftDeleted: 1},
+		},
+		{
+			name: "invalid retention policy",
+			postgres: v1alpha4.PostgresSpec{
+				Retention:       "18m",
+				RetentionPolicy: &v1alpha4.RetentionPolicy{Events: "3d"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &v1alpha4.MulticlusterGlobalHub{
+				Spec: v1alpha4.MulticlusterGlobalHubSpec{
+					DataLayerSpec: v1alpha4.DataLayerSpec{Postgres: tt.postgres},
+				},
+			}
+			got, err := GetRetentionMonths(mgh)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRetentionMonths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRetentionMonths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateArchive(t *testing.T) {
+	s3 := &v1alpha4.S3ArchiveSpec{
+		Endpoint:          "https://s3.us-east-1.amazonaws.com",
+		Bucket:            "global-hub",
+		CredentialsSecret: "archive-credentials",
+	}
+	tests := []struct {
+		name    string
+		archive *v1alpha4.ArchiveSpec
+		wantErr bool
+	}{
+		{name: "no archive"},
+		{name: "s3 archive", archive: &v1alpha4.ArchiveSpec{S3: s3}},
+		{name: "pvc archive", archive: &v1alpha4.ArchiveSpec{PersistentVolumeClaim: "archive"}},
+		{
+			name:    "both s3 and pvc archive",
+			archive: &v1alpha4.ArchiveSpec{S3: s3, PersistentVolumeClaim: "archive"},
+			wantErr: true,
+		},
+		{
+			name:    "s3 archive without bucket",
+			archive: &v1alpha4.ArchiveSpec{S3: &v1alpha4.S3ArchiveSpec{Endpoint: s3.Endpoint}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateArchive(tt.archive); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseReplicaURIs(t *testing.T) {
+	tests := []struct {
+		name string
+		uris string
+		want []string
+	}{
+		{name: "no replica", uris: ""},
+		{
+			name: "comma separated replicas",
+			uris: "postgresql://replica-1:5432/hoh, postgresql://replica-2:5432/hoh",
+			want: []string{"postgresql://replica-1:5432/hoh", "postgresql://replica-2:5432/hoh"},
+		},
+		{
+			name: "newline separated replicas",
+			uris: "postgresql://replica-1:5432/hoh\npostgresql://replica-2:5432/hoh\n",
+			want: []string{"postgresql://replica-1:5432/hoh", "postgresql://replica-2:5432/hoh"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseReplicaURIs(tt.uris); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReplicaURIs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
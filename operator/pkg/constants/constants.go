@@ -54,8 +54,14 @@ const (
 	// to identify the scheduler interval for moving policy compliance history
 	// valid value can be "month, week, day, hour, minute, second"
 	AnnotationMGHSchedulerInterval = "mgh-scheduler-interval"
+	// AnnotationDatabaseMigrationDryRun only plans the database migrations, the pending migrations are reported in
+	// the DatabaseMigrated condition instead of being applied
+	AnnotationDatabaseMigrationDryRun = "global-hub.open-cluster-management.io/database-migration-dry-run"
 	// MGHOperandImagePrefix ...
 	MGHOperandImagePrefix = "RELATED_IMAGE_"
+	// AnnotationClusterConflictResolution sets how the manager resolves the cluster claimed by several managed hubs,
+	// valid value can be "newest, pinned, quarantine"
+	AnnotationClusterConflictResolution = "global-hub.open-cluster-management.io/cluster-conflict-resolution"
 	// AnnotationStatisticInterval to log the interval of statistic log
 	AnnotationStatisticInterval = "mgh-statistic-interval"
 	// AnnotationMetricsScrapeInterval to set the scrape interval for metrics
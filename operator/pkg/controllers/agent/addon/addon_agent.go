@@ -156,6 +156,11 @@ func (a *GlobalHubAddonAgent) GetValues(cluster *clusterv1.ManagedCluster,
 	manifestsConfig.AggregationLevel = config.AggregationLevel
 	manifestsConfig.EnableLocalPolicies = config.EnableLocalPolicies
 	manifestsConfig.EventSendMode = config.GetEventSendMode(mgh)
+	if mgh.Spec.Tracing != nil {
+		manifestsConfig.TracingEndpoint = mgh.Spec.Tracing.Endpoint
+		manifestsConfig.TracingInsecure = mgh.Spec.Tracing.Insecure
+		manifestsConfig.TracingSamplingPercentage = mgh.Spec.Tracing.SamplingPercentage
+	}
 	manifestsConfig.Tolerations = mgh.Spec.Tolerations
 	manifestsConfig.NodeSelector = mgh.Spec.NodeSelector
 
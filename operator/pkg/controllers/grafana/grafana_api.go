@@ -0,0 +1,206 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// grafanaAPI calls the HTTP API of a grafana instance as the server admin, the admin is authenticated by the auth
+// proxy header, so the API is only reachable inside the grafana pod network
+type grafanaAPI struct {
+	baseURL    string
+	adminUser  string
+	httpClient *http.Client
+}
+
+type grafanaOrgUser struct {
+	UserID int64  `json:"userId"`
+	Login  string `json:"login"`
+}
+
+func newGrafanaAPI(baseURL, adminUser string) *grafanaAPI {
+	return &grafanaAPI{
+		baseURL:    baseURL,
+		adminUser:  adminUser,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do sends the request to the org, and decodes the response into the out. It returns the status code, the error is
+// only returned for the unexpected status codes
+func (g *grafanaAPI) do(ctx context.Context, method, path string, orgID int64, in, out any,
+	expected ...int,
+) (int, error) {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-User", g.adminUser)
+	if orgID > 0 {
+		req.Header.Set("X-Grafana-Org-Id", fmt.Sprintf("%d", orgID))
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if out != nil && len(respBody) > 0 {
+			return resp.StatusCode, json.Unmarshal(respBody, out)
+		}
+		return resp.StatusCode, nil
+	}
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return resp.StatusCode, nil
+		}
+	}
+	return resp.StatusCode, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, string(respBody))
+}
+
+// ensureOrg returns the id of the organization, it's created if not exists
+func (g *grafanaAPI) ensureOrg(ctx context.Context, name string) (int64, error) {
+	org := struct {
+		ID int64 `json:"id"`
+	}{}
+	code, err := g.do(ctx, http.MethodGet, "/api/orgs/name/"+url.PathEscape(name), 0, nil, &org,
+		http.StatusNotFound)
+	if err != nil || code != http.StatusNotFound {
+		return org.ID, err
+	}
+
+	created := struct {
+		OrgID int64 `json:"orgId"`
+	}{}
+	_, err = g.do(ctx, http.MethodPost, "/api/orgs", 0, map[string]string{"name": name}, &created)
+	return created.OrgID, err
+}
+
+// listOrgs returns the ids of the organizations by the names
+func (g *grafanaAPI) listOrgs(ctx context.Context) (map[string]int64, error) {
+	orgs := []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}{}
+	if _, err := g.do(ctx, http.MethodGet, "/api/orgs", 0, nil, &orgs); err != nil {
+		return nil, err
+	}
+	results := map[string]int64{}
+	for _, org := range orgs {
+		results[org.Name] = org.ID
+	}
+	return results, nil
+}
+
+func (g *grafanaAPI) deleteOrg(ctx context.Context, orgID int64) error {
+	_, err := g.do(ctx, http.MethodDelete, fmt.Sprintf("/api/orgs/%d", orgID), 0, nil, nil, http.StatusNotFound)
+	return err
+}
+
+// ensureUser returns the id of the user. The user is created before it signs in by the auth proxy, so that it
+// won't be auto assigned to the main organization
+func (g *grafanaAPI) ensureUser(ctx context.Context, login string) (int64, error) {
+	user := struct {
+		ID int64 `json:"id"`
+	}{}
+	code, err := g.do(ctx, http.MethodGet, "/api/users/lookup?loginOrEmail="+url.QueryEscape(login), 0, nil,
+		&user, http.StatusNotFound)
+	if err != nil || code != http.StatusNotFound {
+		return user.ID, err
+	}
+
+	// the password is never used since the login form is disabled
+	password := make([]byte, 16)
+	if _, err = rand.Read(password); err != nil {
+		return 0, err
+	}
+	_, err = g.do(ctx, http.MethodPost, "/api/admin/users", 0, map[string]string{
+		"name":     login,
+		"login":    login,
+		"password": hex.EncodeToString(password),
+	}, &user)
+	return user.ID, err
+}
+
+func (g *grafanaAPI) listOrgUsers(ctx context.Context, orgID int64) ([]grafanaOrgUser, error) {
+	users := []grafanaOrgUser{}
+	_, err := g.do(ctx, http.MethodGet, fmt.Sprintf("/api/orgs/%d/users", orgID), 0, nil, &users)
+	return users, err
+}
+
+// addOrgUser adds the user into the organization, it's ignored if the user is already a member
+func (g *grafanaAPI) addOrgUser(ctx context.Context, orgID int64, login, role string) error {
+	_, err := g.do(ctx, http.MethodPost, fmt.Sprintf("/api/orgs/%d/users", orgID), 0,
+		map[string]string{"loginOrEmail": login, "role": role}, nil, http.StatusConflict)
+	return err
+}
+
+func (g *grafanaAPI) removeOrgUser(ctx context.Context, orgID, userID int64) error {
+	_, err := g.do(ctx, http.MethodDelete, fmt.Sprintf("/api/orgs/%d/users/%d", orgID, userID), 0, nil, nil,
+		http.StatusNotFound)
+	return err
+}
+
+// ensureDatasource creates or updates the datasource by the uid in the organization
+func (g *grafanaAPI) ensureDatasource(ctx context.Context, orgID int64, ds *GrafanaDatasource) error {
+	code, err := g.do(ctx, http.MethodGet, "/api/datasources/uid/"+url.PathEscape(ds.UID), orgID, nil, nil,
+		http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	if code == http.StatusNotFound {
+		_, err = g.do(ctx, http.MethodPost, "/api/datasources", orgID, ds, nil)
+		return err
+	}
+	_, err = g.do(ctx, http.MethodPut, "/api/datasources/uid/"+url.PathEscape(ds.UID), orgID, ds, nil)
+	return err
+}
+
+// ensureFolder creates the folder in the organization if not exists
+func (g *grafanaAPI) ensureFolder(ctx context.Context, orgID int64, uid, title string) error {
+	code, err := g.do(ctx, http.MethodGet, "/api/folders/"+url.PathEscape(uid), orgID, nil, nil,
+		http.StatusNotFound)
+	if err != nil || code != http.StatusNotFound {
+		return err
+	}
+	_, err = g.do(ctx, http.MethodPost, "/api/folders", orgID, map[string]string{"uid": uid, "title": title}, nil)
+	return err
+}
+
+// importDashboard overwrites the dashboard in the folder of the organization
+func (g *grafanaAPI) importDashboard(ctx context.Context, orgID int64, folderUID string,
+	dashboard map[string]any,
+) error {
+	dashboard["id"] = nil
+	_, err := g.do(ctx, http.MethodPost, "/api/dashboards/db", orgID, map[string]any{
+		"dashboard": dashboard,
+		"folderUid": folderUID,
+		"overwrite": true,
+	}, nil)
+	return err
+}
@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
@@ -169,7 +170,9 @@ func (r *GrafanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&rbacv1.ClusterRoleBinding{},
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(config.GeneralPredicate)).
 		Watches(&routev1.Route{},
-			&handler.EnqueueRequestForObject{}, builder.WithPredicates(config.GeneralPredicate))
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(config.GeneralPredicate)).
+		Watches(&tenantv1alpha1.GlobalHubTenant{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(tenantPred))
 
 	if _, err := mgr.GetRESTMapper().KindFor(schema.GroupVersionResource{
 		Group:    "image.openshift.io",
@@ -200,6 +203,24 @@ var deploymentPred = predicate.Funcs{
 	},
 }
 
+// tenantPred watches the changes of the tenant spec and the database user, which is created by the storage controller
+var tenantPred = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+			return true
+		}
+		oldTenant, ok := e.ObjectOld.(*tenantv1alpha1.GlobalHubTenant)
+		if !ok {
+			return false
+		}
+		newTenant, ok := e.ObjectNew.(*tenantv1alpha1.GlobalHubTenant)
+		if !ok {
+			return false
+		}
+		return oldTenant.Status.DatabaseSecret != newTenant.Status.DatabaseSecret
+	},
+}
+
 var imageStreamPred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return e.Object.GetName() == operatorconstants.OauthProxyImageStreamName
@@ -365,6 +386,12 @@ func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
+	// the tenant organizations are provisioned again after the grafana pods are restarted and ready
+	if err = r.reconcileTenants(ctx, mgh, grafanaObjects); err != nil {
+		reconcileErr = fmt.Errorf("failed to reconcile the grafana organizations of tenants: %w", err)
+		return ctrl.Result{}, reconcileErr
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -655,7 +682,7 @@ func (r *GrafanaReconciler) GenerateGrafanaDataSourceSecret(
 		saToken = string(saSecret.Data["token"])
 	}
 
-	datasourceVal, err := GrafanaDataSource(storageConn.ReadonlyUserDatabaseURI, storageConn.CACert, saToken)
+	datasourceVal, err := GrafanaDataSource(datasourceURI(storageConn), storageConn.CACert, saToken)
 	if err != nil {
 		datasourceVal, err = GrafanaDataSource(storageConn.SuperuserDatabaseURI, storageConn.CACert, saToken)
 		if err != nil {
@@ -705,7 +732,55 @@ func (r *GrafanaReconciler) GenerateGrafanaDataSourceSecret(
 	return false, nil
 }
 
+// datasourceURI prefers the read replica to keep the dashboard queries away from the write path of the primary
+func datasourceURI(storageConn *config.PostgresConnection) string {
+	if storageConn.ReadonlyUserReplicaDatabaseURI != "" {
+		return storageConn.ReadonlyUserReplicaDatabaseURI
+	}
+	return storageConn.ReadonlyUserDatabaseURI
+}
+
 func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken string) ([]byte, error) {
+	postgresDS, err := postgresDataSource(databaseURI, cert)
+	if err != nil {
+		return nil, err
+	}
+	datasource := []*GrafanaDatasource{postgresDS}
+
+	if serviceAccountToken != "" {
+		prometheusDS := &GrafanaDatasource{
+			Name:      "Prometheus",
+			Type:      "prometheus",
+			Access:    "proxy",
+			IsDefault: false,
+			URL:       "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091",
+			BasicAuth: false,
+			Editable:  false,
+			JSONData: &JsonData{
+				QueryTimeout:    "300s",
+				TimeInterval:    "30s",
+				TLSSkipVerify:   true,
+				HttpHeaderName1: "Authorization",
+			},
+			SecureJSONData: &SecureJsonData{
+				HttpHeaderValue1: "Bearer " + serviceAccountToken,
+			},
+		}
+		datasource = append(datasource, prometheusDS)
+	}
+
+	grafanaDatasources, err := yaml.Marshal(GrafanaDatasources{
+		APIVersion:  1,
+		Datasources: datasource,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grafanaDatasources, nil
+}
+
+// postgresDataSource returns the global hub datasource which connects to the database by the URI
+func postgresDataSource(databaseURI string, cert []byte) (*GrafanaDatasource, error) {
 	postgresURI := string(databaseURI)
 	objURI, err := url.Parse(postgresURI)
 	if err != nil {
@@ -740,29 +815,6 @@ func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken stri
 			Password: password,
 		},
 	}
-	datasource := []*GrafanaDatasource{postgresDS}
-
-	if serviceAccountToken != "" {
-		prometheusDS := &GrafanaDatasource{
-			Name:      "Prometheus",
-			Type:      "prometheus",
-			Access:    "proxy",
-			IsDefault: false,
-			URL:       "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091",
-			BasicAuth: false,
-			Editable:  false,
-			JSONData: &JsonData{
-				QueryTimeout:    "300s",
-				TimeInterval:    "30s",
-				TLSSkipVerify:   true,
-				HttpHeaderName1: "Authorization",
-			},
-			SecureJSONData: &SecureJsonData{
-				HttpHeaderValue1: "Bearer " + serviceAccountToken,
-			},
-		}
-		datasource = append(datasource, prometheusDS)
-	}
 
 	if len(cert) > 0 {
 		postgresDS.JSONData.SSLMode = objURI.Query().Get("sslmode") // sslmode == "verify-full" || sslmode == "verify-ca"
@@ -772,55 +824,49 @@ func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken stri
 		postgresDS.JSONData.TLSConfigurationMethod = "file-content"
 		postgresDS.SecureJSONData.TLSCACert = string(cert)
 	}
-	grafanaDatasources, err := yaml.Marshal(GrafanaDatasources{
-		APIVersion:  1,
-		Datasources: datasource,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return grafanaDatasources, nil
+	return postgresDS, nil
 }
 
 type GrafanaDatasources struct {
-	APIVersion  int                  `yaml:"apiVersion,omitempty"`
-	Datasources []*GrafanaDatasource `yaml:"datasources,omitempty"`
+	APIVersion  int                  `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+	Datasources []*GrafanaDatasource `yaml:"datasources,omitempty" json:"datasources,omitempty"`
 }
 
 type GrafanaDatasource struct {
-	Access            string          `yaml:"access,omitempty"`
-	BasicAuth         bool            `yaml:"basicAuth,omitempty"`
-	BasicAuthPassword string          `yaml:"basicAuthPassword,omitempty"`
-	BasicAuthUser     string          `yaml:"basicAuthUser,omitempty"`
-	Editable          bool            `yaml:"editable,omitempty"`
-	IsDefault         bool            `yaml:"isDefault,omitempty"`
-	Name              string          `yaml:"name,omitempty"`
-	OrgID             int             `yaml:"orgId,omitempty"`
-	Type              string          `yaml:"type,omitempty"`
-	URL               string          `yaml:"url,omitempty"`
-	User              string          `yaml:"user,omitempty"`
-	Version           int             `yaml:"version,omitempty"`
-	JSONData          *JsonData       `yaml:"jsonData,omitempty"`
-	SecureJSONData    *SecureJsonData `yaml:"secureJsonData,omitempty"`
+	Access            string          `yaml:"access,omitempty" json:"access,omitempty"`
+	BasicAuth         bool            `yaml:"basicAuth,omitempty" json:"basicAuth,omitempty"`
+	BasicAuthPassword string          `yaml:"basicAuthPassword,omitempty" json:"basicAuthPassword,omitempty"`
+	BasicAuthUser     string          `yaml:"basicAuthUser,omitempty" json:"basicAuthUser,omitempty"`
+	Editable          bool            `yaml:"editable,omitempty" json:"editable,omitempty"`
+	IsDefault         bool            `yaml:"isDefault,omitempty" json:"isDefault,omitempty"`
+	Name              string          `yaml:"name,omitempty" json:"name,omitempty"`
+	OrgID             int             `yaml:"orgId,omitempty" json:"orgId,omitempty"`
+	UID               string          `yaml:"uid,omitempty" json:"uid,omitempty"`
+	Type              string          `yaml:"type,omitempty" json:"type,omitempty"`
+	URL               string          `yaml:"url,omitempty" json:"url,omitempty"`
+	User              string          `yaml:"user,omitempty" json:"user,omitempty"`
+	Version           int             `yaml:"version,omitempty" json:"version,omitempty"`
+	JSONData          *JsonData       `yaml:"jsonData,omitempty" json:"jsonData,omitempty"`
+	SecureJSONData    *SecureJsonData `yaml:"secureJsonData,omitempty" json:"secureJsonData,omitempty"`
 }
 
 type JsonData struct {
-	Database               string `yaml:"database,omitempty"`
-	SSLMode                string `yaml:"sslmode,omitempty"`
-	TLSAuth                bool   `yaml:"tlsAuth,omitempty"`
-	TLSAuthWithCACert      bool   `yaml:"tlsAuthWithCACert,omitempty"`
-	TLSConfigurationMethod string `yaml:"tlsConfigurationMethod,omitempty"`
-	TLSSkipVerify          bool   `yaml:"tlsSkipVerify,omitempty"`
-	QueryTimeout           string `yaml:"queryTimeout,omitempty"`
-	HttpMethod             string `yaml:"httpMethod,omitempty"`
-	TimeInterval           string `yaml:"timeInterval,omitempty"`
-	HttpHeaderName1        string `yaml:"httpHeaderName1,omitempty"`
+	Database               string `yaml:"database,omitempty" json:"database,omitempty"`
+	SSLMode                string `yaml:"sslmode,omitempty" json:"sslmode,omitempty"`
+	TLSAuth                bool   `yaml:"tlsAuth,omitempty" json:"tlsAuth,omitempty"`
+	TLSAuthWithCACert      bool   `yaml:"tlsAuthWithCACert,omitempty" json:"tlsAuthWithCACert,omitempty"`
+	TLSConfigurationMethod string `yaml:"tlsConfigurationMethod,omitempty" json:"tlsConfigurationMethod,omitempty"`
+	TLSSkipVerify          bool   `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty"`
+	QueryTimeout           string `yaml:"queryTimeout,omitempty" json:"queryTimeout,omitempty"`
+	HttpMethod             string `yaml:"httpMethod,omitempty" json:"httpMethod,omitempty"`
+	TimeInterval           string `yaml:"timeInterval,omitempty" json:"timeInterval,omitempty"`
+	HttpHeaderName1        string `yaml:"httpHeaderName1,omitempty" json:"httpHeaderName1,omitempty"`
 }
 
 type SecureJsonData struct {
-	Password         string `yaml:"password,omitempty"`
-	TLSCACert        string `yaml:"tlsCACert,omitempty"`
-	TLSClientCert    string `yaml:"tlsClientCert,omitempty"`
-	TLSClientKey     string `yaml:"tlsClientKey,omitempty"`
-	HttpHeaderValue1 string `yaml:"httpHeaderValue1,omitempty"`
+	Password         string `yaml:"password,omitempty" json:"password,omitempty"`
+	TLSCACert        string `yaml:"tlsCACert,omitempty" json:"tlsCACert,omitempty"`
+	TLSClientCert    string `yaml:"tlsClientCert,omitempty" json:"tlsClientCert,omitempty"`
+	TLSClientKey     string `yaml:"tlsClientKey,omitempty" json:"tlsClientKey,omitempty"`
+	HttpHeaderValue1 string `yaml:"httpHeaderValue1,omitempty" json:"httpHeaderValue1,omitempty"`
 }
@@ -0,0 +1,369 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	userv1 "github.com/openshift/api/user/v1"
+	"gopkg.in/ini.v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/storage"
+)
+
+// +kubebuilder:rbac:groups=global-hub.open-cluster-management.io,resources=globalhubtenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=global-hub.open-cluster-management.io,resources=globalhubtenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=user.openshift.io,resources=groups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+const (
+	// the uid of the global hub datasource, which is referenced by the dashboards
+	globalHubDatasourceUID = "P244538DD76A4C61D"
+	tenantOrgPrefix        = "tenant-"
+	grafanaMainOrgID       = 1
+	grafanaViewerRole      = "Viewer"
+)
+
+// tenantDashboards are the folders and dashboards imported into the tenant organizations, the dashboards of the
+// global hub infrastructure, like kafka and postgres, aren't visible to the tenants
+var tenantDashboards = []struct {
+	folderUID   string
+	folderTitle string
+	configMaps  []string
+}{
+	{
+		folderUID:   "global-hub-policy",
+		folderTitle: "Policy",
+		configMaps: []string{
+			"grafana-dashboard-acm-global-overview",
+			"grafana-dashboard-acm-global-cluster-group-compliancy-overview",
+			"grafana-dashboard-acm-global-policy-group-compliancy-overview",
+			"grafana-dashboard-acm-global-adhoc-investigation",
+			"grafana-dashboard-acm-global-offending-clusters",
+			"grafana-dashboard-acm-global-offending-policies",
+			"grafana-dashboard-acm-global-whats-changed-clusters",
+			"grafana-dashboard-acm-global-whats-changed-policies",
+		},
+	},
+	{
+		folderUID:   "global-hub-cluster",
+		folderTitle: "Cluster",
+		configMaps: []string{
+			"grafana-dashboard-acm-global-managedclusters",
+			"grafana-dashboard-acm-global-managed-cluster-addons",
+		},
+	},
+	{
+		folderUID:   "global-hub-application",
+		folderTitle: "Application",
+		configMaps: []string{
+			"grafana-dashboard-acm-global-applications",
+		},
+	},
+}
+
+// reconcileTenants creates a grafana organization for each tenant, which only contains the datasource of the tenant
+// database user and the dashboards of the managed hubs. The tenant users are the viewers of the organization, and
+// removed from the main organization. The grafana storage isn't persistent, so the organizations are provisioned
+// into each grafana instance after it's ready
+func (r *GrafanaReconciler) reconcileTenants(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	grafanaObjects []*unstructured.Unstructured,
+) error {
+	tenants := &tenantv1alpha1.GlobalHubTenantList{}
+	if err := r.GetClient().List(ctx, tenants); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list the global hub tenants: %w", err)
+	}
+
+	apis, err := r.grafanaAPIs(ctx, mgh)
+	if err != nil || len(apis) == 0 {
+		return err
+	}
+	dashboards, err := tenantDashboardsFromObjects(grafanaObjects)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	desiredOrgs := sets.New[string]()
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if tenant.DeletionTimestamp != nil {
+			continue
+		}
+		desiredOrgs.Insert(tenantOrgPrefix + tenant.Name)
+		if err := r.reconcileTenant(ctx, mgh, tenant, apis, dashboards); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reconcile the grafana organization of tenant %s: %w",
+				tenant.Name, err))
+		}
+	}
+
+	// delete the organizations of the deleted tenants
+	for _, api := range apis {
+		orgs, err := api.listOrgs(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for name, id := range orgs {
+			if strings.HasPrefix(name, tenantOrgPrefix) && !desiredOrgs.Has(name) {
+				if err := api.deleteOrg(ctx, id); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				log.Infof("deleted the grafana organization: %s", name)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *GrafanaReconciler) reconcileTenant(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	tenant *tenantv1alpha1.GlobalHubTenant, apis []*grafanaAPI, dashboards map[string][]map[string]any,
+) error {
+	var orgID int64
+	err := func() error {
+		if tenant.Status.DatabaseSecret == "" {
+			return fmt.Errorf("the database user of the tenant isn't ready")
+		}
+		datasource, err := r.tenantDataSource(ctx, mgh, tenant.Status.DatabaseSecret)
+		if err != nil {
+			return err
+		}
+		users, err := r.tenantUsers(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		for _, api := range apis {
+			id, err := provisionTenantOrg(ctx, api, tenantOrgPrefix+tenant.Name, datasource, dashboards, users)
+			if err != nil {
+				return err
+			}
+			if orgID == 0 {
+				orgID = id
+			}
+		}
+		return nil
+	}()
+
+	condition := metav1.Condition{
+		Type:    tenantv1alpha1.ConditionTypeGrafanaReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "GrafanaOrganizationReady",
+		Message: fmt.Sprintf("the grafana organization %s%s is ready", tenantOrgPrefix, tenant.Name),
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "GrafanaOrganizationFailed"
+		condition.Message = err.Error()
+	}
+	statusErr := config.UpdateTenantStatus(ctx, r.GetClient(), tenant.Name,
+		func(status *tenantv1alpha1.GlobalHubTenantStatus) {
+			if orgID > 0 {
+				status.GrafanaOrgID = orgID
+			}
+			meta.SetStatusCondition(&status.Conditions, condition)
+		})
+	return utilerrors.NewAggregate([]error{err, statusErr})
+}
+
+// provisionTenantOrg ensures the organization with the datasource, dashboards and users in the grafana instance,
+// it returns the id of the organization
+func provisionTenantOrg(ctx context.Context, api *grafanaAPI, orgName string, datasource *GrafanaDatasource,
+	dashboards map[string][]map[string]any, users []string,
+) (int64, error) {
+	orgID, err := api.ensureOrg(ctx, orgName)
+	if err != nil {
+		return 0, err
+	}
+	if err = api.ensureDatasource(ctx, orgID, datasource); err != nil {
+		return orgID, err
+	}
+	for _, folder := range tenantDashboards {
+		if err = api.ensureFolder(ctx, orgID, folder.folderUID, folder.folderTitle); err != nil {
+			return orgID, err
+		}
+		for _, dashboard := range dashboards[folder.folderUID] {
+			if err = api.importDashboard(ctx, orgID, folder.folderUID, dashboard); err != nil {
+				return orgID, err
+			}
+		}
+	}
+
+	// add the tenant users into the organization before removing them from the main organization, so that they're
+	// switched to the tenant organization
+	desiredUsers := sets.New(users...)
+	for _, user := range users {
+		userID, err := api.ensureUser(ctx, user)
+		if err != nil {
+			return orgID, err
+		}
+		if err = api.addOrgUser(ctx, orgID, user, grafanaViewerRole); err != nil {
+			return orgID, err
+		}
+		if err = api.removeOrgUser(ctx, grafanaMainOrgID, userID); err != nil {
+			return orgID, err
+		}
+	}
+
+	// remove the users who don't belong to the tenant anymore
+	members, err := api.listOrgUsers(ctx, orgID)
+	if err != nil {
+		return orgID, err
+	}
+	for _, member := range members {
+		if member.Login == api.adminUser || desiredUsers.Has(member.Login) {
+			continue
+		}
+		if err = api.removeOrgUser(ctx, orgID, member.UserID); err != nil {
+			return orgID, err
+		}
+	}
+	return orgID, nil
+}
+
+// tenantDataSource returns the global hub datasource which connects to the database with the tenant user
+func (r *GrafanaReconciler) tenantDataSource(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	secretName string,
+) (*GrafanaDatasource, error) {
+	secret := &corev1.Secret{}
+	if err := r.GetClient().Get(ctx, client.ObjectKey{Namespace: mgh.Namespace, Name: secretName},
+		secret); err != nil {
+		return nil, fmt.Errorf("failed to get the tenant database secret: %w", err)
+	}
+	storageConn := config.GetStorageConnection()
+	if storageConn == nil {
+		return nil, fmt.Errorf("PgConnection config is null")
+	}
+	return TenantGrafanaDataSource(datasourceURI(storageConn), storageConn.CACert,
+		string(secret.Data[storage.PostgresCustomizedUserSecretUserKey]),
+		string(secret.Data[storage.PostgresCustomizedUserSecretPasswordKey]))
+}
+
+// TenantGrafanaDataSource returns the global hub datasource of the database URI with the tenant credential
+func TenantGrafanaDataSource(databaseURI string, cert []byte, user, password string) (*GrafanaDatasource, error) {
+	if user == "" || password == "" {
+		return nil, fmt.Errorf("the tenant database credential is empty")
+	}
+	datasource, err := postgresDataSource(databaseURI, cert)
+	if err != nil {
+		return nil, err
+	}
+	datasource.UID = globalHubDatasourceUID
+	datasource.User = user
+	datasource.SecureJSONData.Password = password
+	return datasource, nil
+}
+
+// tenantUsers returns the sorted users of the tenant, which includes the members of the tenant groups
+func (r *GrafanaReconciler) tenantUsers(ctx context.Context, tenant *tenantv1alpha1.GlobalHubTenant) (
+	[]string, error,
+) {
+	users := sets.New(tenant.Spec.Users...)
+	for _, groupName := range tenant.Spec.Groups {
+		group := &userv1.Group{}
+		err := r.GetClient().Get(ctx, client.ObjectKey{Name: groupName}, group)
+		if meta.IsNoMatchError(err) {
+			log.Warnf("the user groups aren't supported, skip the groups of tenant %s", tenant.Name)
+			break
+		}
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get the group %s: %w", groupName, err)
+		}
+		users.Insert(group.Users...)
+	}
+	return sets.List(users), nil
+}
+
+// tenantDashboardsFromObjects returns the dashboards of the tenant folders from the rendered configmaps
+func tenantDashboardsFromObjects(objects []*unstructured.Unstructured) (map[string][]map[string]any, error) {
+	configMaps := map[string]*unstructured.Unstructured{}
+	for _, obj := range objects {
+		if obj.GetKind() == "ConfigMap" {
+			configMaps[obj.GetName()] = obj
+		}
+	}
+
+	dashboards := map[string][]map[string]any{}
+	for _, folder := range tenantDashboards {
+		for _, name := range folder.configMaps {
+			obj, ok := configMaps[name]
+			if !ok {
+				continue
+			}
+			data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+			if err != nil {
+				return nil, fmt.Errorf("invalid dashboard configmap %s: %w", name, err)
+			}
+			keys := make([]string, 0, len(data))
+			for key := range data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				dashboard := map[string]any{}
+				if err := json.Unmarshal([]byte(data[key]), &dashboard); err != nil {
+					return nil, fmt.Errorf("invalid dashboard %s in configmap %s: %w", key, name, err)
+				}
+				dashboards[folder.folderUID] = append(dashboards[folder.folderUID], dashboard)
+			}
+		}
+	}
+	return dashboards, nil
+}
+
+// grafanaAPIs returns the API of each ready grafana instance, it's empty if the grafana isn't ready
+func (r *GrafanaReconciler) grafanaAPIs(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub) (
+	[]*grafanaAPI, error,
+) {
+	iniSecret := &corev1.Secret{}
+	if err := r.GetClient().Get(ctx, client.ObjectKey{Namespace: mgh.Namespace, Name: defaultGrafanaIniName},
+		iniSecret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	grafanaIni, err := ini.Load(iniSecret.Data[grafanaIniKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the grafana.ini: %w", err)
+	}
+	adminUser := grafanaIni.Section("security").Key("admin_user").String()
+	port := grafanaIni.Section("server").Key("http_port").MustInt(3001)
+
+	pods := &corev1.PodList{}
+	if err := r.GetClient().List(ctx, pods, client.InNamespace(mgh.Namespace),
+		client.MatchingLabels{"name": grafanaDeploymentName}); err != nil {
+		return nil, err
+	}
+	var apis []*grafanaAPI
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !isPodReady(&pod) {
+			continue
+		}
+		apis = append(apis, newGrafanaAPI("http://"+net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)), adminUser))
+	}
+	return apis, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -63,6 +64,10 @@ import (
 // +kubebuilder:rbac:groups="authentication.open-cluster-management.io",resources=managedserviceaccounts,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=managedclustermigrations,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=managedclustermigrations/status,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalhubresyncs,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalhubresyncs/status,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=policyremediationrequests,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=policyremediationrequests/status,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=placementbindings,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkausers,verbs=get;watch;update
@@ -239,13 +244,20 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 	}
 
 	// dataRetention should at least be 1 month, otherwise it will deleted the current month partitions and records
-	months, err := commonutils.ParseRetentionMonth(mgh.Spec.DataLayerSpec.Postgres.Retention)
+	retentionMonths, err := config.GetRetentionMonths(mgh)
 	if err != nil {
 		reconcileErr = fmt.Errorf("failed to parse month retention: %v", err)
 		return ctrl.Result{}, reconcileErr
 	}
-	if months < 1 {
-		months = 1
+	archive := mgh.Spec.DataLayerSpec.Postgres.Archive
+	if err = config.ValidateArchive(archive); err != nil {
+		reconcileErr = err
+		return ctrl.Result{}, reconcileErr
+	}
+	archiveVariables := ArchiveVariables{}
+	if archive != nil {
+		archiveVariables.PersistentVolumeClaim = archive.PersistentVolumeClaim
+		archiveVariables.S3 = archive.S3
 	}
 
 	replicas := int32(1)
@@ -293,6 +305,9 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			DatabaseURL: base64.StdEncoding.EncodeToString(
 				[]byte(storageConn.SuperuserDatabaseURI),
 			),
+			ReplicaDatabaseURLs: base64.StdEncoding.EncodeToString(
+				[]byte(strings.Join(storageConn.ReplicaDatabaseURIs, ",")),
+			),
 			PostgresCACert:            base64.StdEncoding.EncodeToString(storageConn.CACert),
 			TransportType:             string(transport.Kafka),
 			TransportConfigSecret:     constants.GHTransportConfigSecret,
@@ -302,16 +317,21 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			RenewDeadline:             strconv.Itoa(electionConfig.RenewDeadline),
 			RetryPeriod:               strconv.Itoa(electionConfig.RetryPeriod),
 			SchedulerInterval:         config.GetSchedulerInterval(mgh),
+			ClusterConflictResolution: config.GetClusterConflictResolution(mgh),
 			LaunchJobNames:            config.GetLaunchJobNames(mgh),
 			NodeSelector:              mgh.Spec.NodeSelector,
 			Tolerations:               mgh.Spec.Tolerations,
-			RetentionMonth:            months,
+			EventRetentionMonth:       retentionMonths.Events,
+			HistoryRetentionMonth:     retentionMonths.History,
+			SoftDeletedRetentionMonth: retentionMonths.SoftDeleted,
+			Archive:                   archiveVariables,
 			StatisticLogInterval:      config.GetStatisticLogInterval(),
 			EnableInventoryAPI:        config.WithInventory(mgh),
 			EnablePprof:               r.operatorConfig.EnablePprof,
 			Resources:                 utils.GetResources(operatorconstants.Manager, mgh.Spec.AdvancedSpec),
 			WithACM:                   config.IsACMResourceReady(),
 			TransportFailureThreshold: r.operatorConfig.TransportFailureThreshold,
+			Tracing:                   mgh.Spec.Tracing,
 		}, nil
 	})
 	if err != nil {
@@ -437,6 +457,12 @@ func storageConnectionUpdated(storageConn *config.PostgresConnection) bool {
 	return updated
 }
 
+// ArchiveVariables is the storage of the archived partitions
+type ArchiveVariables struct {
+	PersistentVolumeClaim string
+	S3                    *v1alpha4.S3ArchiveSpec
+}
+
 type ManagerVariables struct {
 	Image                     string
 	Replicas                  int32
@@ -445,6 +471,7 @@ type ManagerVariables struct {
 	ImagePullPolicy           string
 	ProxySessionSecret        string
 	DatabaseURL               string
+	ReplicaDatabaseURLs       string
 	PostgresCACert            string
 	TransportConfigSecret     string
 	StorageConfigSecret       string
@@ -454,14 +481,19 @@ type ManagerVariables struct {
 	RenewDeadline             string
 	RetryPeriod               string
 	SchedulerInterval         string
+	ClusterConflictResolution string
 	LaunchJobNames            string
 	NodeSelector              map[string]string
 	Tolerations               []corev1.Toleration
-	RetentionMonth            int
+	EventRetentionMonth       int
+	HistoryRetentionMonth     int
+	SoftDeletedRetentionMonth int
+	Archive                   ArchiveVariables
 	StatisticLogInterval      string
 	EnableInventoryAPI        bool
 	EnablePprof               bool
 	Resources                 *corev1.ResourceRequirements
 	WithACM                   bool
 	TransportFailureThreshold int
+	Tracing                   *v1alpha4.TracingSpec
 }
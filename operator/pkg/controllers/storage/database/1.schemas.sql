@@ -11,6 +11,8 @@ CREATE SCHEMA IF NOT EXISTS event;
 
 CREATE SCHEMA IF NOT EXISTS security;
 
+CREATE SCHEMA IF NOT EXISTS audit;
+
 CREATE EXTENSION IF NOT EXISTS pg_stat_statements;
 
 DO $$ BEGIN
@@ -43,3 +45,5 @@ DO $$ BEGIN
 EXCEPTION
   WHEN duplicate_object THEN null;
 END $$;
+-- the cluster is quarantined because it's claimed by several managed hubs
+ALTER TYPE status.error_type ADD VALUE IF NOT EXISTS 'conflict';
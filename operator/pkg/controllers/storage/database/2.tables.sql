@@ -14,6 +14,27 @@ CREATE TABLE IF NOT EXISTS local_spec.policies (
 CREATE INDEX IF NOT EXISTS local_policies_deleted_at_idx ON local_spec.policies (deleted_at);
 CREATE INDEX IF NOT EXISTS local_policies_leafhub_idx ON local_spec.policies (leaf_hub_name);
 
+-- the kind is one of Placement, PlacementDecision, ManagedClusterSet and ManagedClusterSetBinding
+CREATE TABLE IF NOT EXISTS local_spec.placements (
+    leaf_hub_name character varying(254) NOT NULL,
+    id uuid NOT NULL,
+    kind character varying(63) NOT NULL,
+    namespace text generated always as (payload -> 'metadata' ->> 'namespace') stored,
+    name text generated always as (payload -> 'metadata' ->> 'name') stored,
+    placement_name text generated always as (
+        CASE kind
+            WHEN 'Placement' THEN payload -> 'metadata' ->> 'name'
+            WHEN 'PlacementDecision' THEN payload -> 'metadata' -> 'labels' ->> 'cluster.open-cluster-management.io/placement'
+        END
+    ) stored,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, id)
+);
+CREATE INDEX IF NOT EXISTS local_placements_kind_idx ON local_spec.placements (leaf_hub_name, kind);
+CREATE INDEX IF NOT EXISTS local_placements_placement_idx ON local_spec.placements (leaf_hub_name, namespace, placement_name);
+
 CREATE TABLE IF NOT EXISTS local_status.compliance (
     policy_id uuid NOT NULL,
     cluster_name character varying(254) NOT NULL,
@@ -24,6 +45,61 @@ CREATE TABLE IF NOT EXISTS local_status.compliance (
     PRIMARY KEY (policy_id, cluster_name, leaf_hub_name)
 );
 
+-- the compliance of the templates of the replicated policies, the related objects are parsed from the compliance
+-- messages of the configuration policies
+CREATE TABLE IF NOT EXISTS local_status.policy_template_compliance (
+    policy_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    template_name character varying(254) NOT NULL,
+    template_kind character varying(254) NOT NULL,
+    cluster_id uuid,
+    compliance local_status.compliance_type NOT NULL,
+    message text,
+    related_objects jsonb,
+    last_timestamp timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (policy_id, cluster_name, leaf_hub_name, template_kind, template_name)
+);
+CREATE INDEX IF NOT EXISTS policy_template_compliance_leaf_hub_idx ON local_status.policy_template_compliance (leaf_hub_name);
+
+-- the compliance counts of the policies reported by the managed hubs with the minimal aggregation level
+CREATE TABLE IF NOT EXISTS status.aggregated_compliance (
+    policy_id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    applied_clusters integer NOT NULL,
+    non_compliant_clusters integer NOT NULL,
+    compliant_clusters integer NOT NULL DEFAULT 0,
+    pending_clusters integer NOT NULL DEFAULT 0,
+    unknown_clusters integer NOT NULL DEFAULT 0,
+    remediation_action character varying(63),
+    PRIMARY KEY (policy_id, leaf_hub_name)
+);
+
+-- the compliance counts of the policies in either aggregation level, the rows of a managed hub are only kept in the
+-- table of its current level
+CREATE OR REPLACE VIEW status.policy_compliance_summary AS
+SELECT
+    leaf_hub_name,
+    policy_id,
+    COUNT(*)::integer AS applied_clusters,
+    (COUNT(*) FILTER (WHERE compliance = 'non_compliant'))::integer AS non_compliant_clusters,
+    (COUNT(*) FILTER (WHERE compliance = 'compliant'))::integer AS compliant_clusters,
+    (COUNT(*) FILTER (WHERE compliance = 'pending'))::integer AS pending_clusters,
+    (COUNT(*) FILTER (WHERE compliance = 'unknown'))::integer AS unknown_clusters
+FROM local_status.compliance
+GROUP BY leaf_hub_name, policy_id
+UNION ALL
+SELECT
+    leaf_hub_name,
+    policy_id,
+    applied_clusters,
+    non_compliant_clusters,
+    compliant_clusters,
+    pending_clusters,
+    unknown_clusters
+FROM status.aggregated_compliance;
+
 CREATE TABLE IF NOT EXISTS status.leaf_hub_heartbeats (
     leaf_hub_name character varying(254) NOT NULL,
     last_timestamp timestamp without time zone DEFAULT now() NOT NULL,
@@ -46,6 +122,22 @@ CREATE TABLE IF NOT EXISTS status.managed_clusters (
 CREATE INDEX IF NOT EXISTS cluster_deleted_at_idx ON status.managed_clusters (deleted_at);
 CREATE INDEX IF NOT EXISTS leafhub_cluster_idx ON status.managed_clusters (leaf_hub_name, cluster_name);
 
+-- the claims of the managed hubs on the clusters which are claimed by several managed hubs, e.g. the cluster is
+-- imported to another hub after a failed migration, the claims are kept with the resolved_at once resolved
+CREATE TABLE IF NOT EXISTS status.managed_cluster_conflicts (
+    cluster_id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    resolution character varying(63) NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    resolved_at timestamp without time zone,
+    PRIMARY KEY (cluster_id, leaf_hub_name)
+);
+CREATE INDEX IF NOT EXISTS managed_cluster_conflicts_active_idx ON status.managed_cluster_conflicts (cluster_id)
+    WHERE resolved_at IS NULL;
+
 CREATE TABLE IF NOT EXISTS status.leaf_hubs (
     leaf_hub_name character varying(254) NOT NULL,
     cluster_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
@@ -59,6 +151,94 @@ CREATE TABLE IF NOT EXISTS status.leaf_hubs (
 );
 CREATE INDEX IF NOT EXISTS leafhub_deleted_at_idx ON status.leaf_hubs (deleted_at);
 
+-- the conditions of the addons on the managed clusters, the namespace of the addon is the cluster name
+CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    addon_name character varying(254) NOT NULL,
+    available text,
+    degraded text,
+    progressing text,
+    message text,
+    conditions jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, addon_name)
+);
+CREATE INDEX IF NOT EXISTS managed_cluster_addons_addon_idx ON status.managed_cluster_addons (addon_name, available);
+
+-- the clusters selected by the placements of the managed hubs
+CREATE OR REPLACE VIEW local_spec.placement_clusters AS
+SELECT
+    p.leaf_hub_name,
+    p.namespace,
+    p.placement_name,
+    d ->> 'clusterName' AS cluster_name,
+    mc.cluster_id
+FROM local_spec.placements p
+CROSS JOIN LATERAL jsonb_array_elements(COALESCE(p.payload -> 'status' -> 'decisions', '[]'::jsonb)) d
+LEFT JOIN status.managed_clusters mc ON mc.leaf_hub_name = p.leaf_hub_name
+    AND mc.cluster_name = d ->> 'clusterName' AND mc.deleted_at IS NULL
+WHERE p.kind = 'PlacementDecision';
+
+-- the cluster_name is the destination cluster of the argo cd application
+CREATE TABLE IF NOT EXISTS status.applications (
+    leaf_hub_name character varying(254) NOT NULL,
+    id uuid NOT NULL,
+    namespace text generated always as (payload -> 'metadata' ->> 'namespace') stored,
+    name text generated always as (payload -> 'metadata' ->> 'name') stored,
+    project text generated always as (payload -> 'spec' ->> 'project') stored,
+    destination_server text generated always as (payload -> 'spec' -> 'destination' ->> 'server') stored,
+    destination_namespace text generated always as (payload -> 'spec' -> 'destination' ->> 'namespace') stored,
+    sync_status text generated always as (payload -> 'status' -> 'sync' ->> 'status') stored,
+    health_status text generated always as (payload -> 'status' -> 'health' ->> 'status') stored,
+    cluster_name character varying(254) NOT NULL,
+    application_set text,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, id)
+);
+CREATE INDEX IF NOT EXISTS applications_cluster_idx ON status.applications (cluster_name);
+CREATE INDEX IF NOT EXISTS applications_name_idx ON status.applications (leaf_hub_name, namespace, name);
+
+CREATE TABLE IF NOT EXISTS status.application_sets (
+    leaf_hub_name character varying(254) NOT NULL,
+    id uuid NOT NULL,
+    namespace text generated always as (payload -> 'metadata' ->> 'namespace') stored,
+    name text generated always as (payload -> 'metadata' ->> 'name') stored,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, id)
+);
+CREATE INDEX IF NOT EXISTS application_sets_name_idx ON status.application_sets (leaf_hub_name, namespace, name);
+
+-- the lifecycle of the clusters provisioned, upgraded or destroyed by the ClusterDeployment, ClusterInstance and
+-- ClusterCurator of the managed hubs, the rows are soft deleted to measure the durations and the failure rates
+CREATE TABLE IF NOT EXISTS status.cluster_provisioning (
+    leaf_hub_name character varying(254) NOT NULL,
+    id uuid NOT NULL,
+    kind character varying(63) NOT NULL,
+    namespace text generated always as (payload -> 'metadata' ->> 'namespace') stored,
+    name text generated always as (payload -> 'metadata' ->> 'name') stored,
+    cluster_name character varying(254) NOT NULL,
+    operation character varying(63) NOT NULL,
+    phase character varying(63) NOT NULL,
+    message text,
+    started_at timestamp without time zone,
+    completed_at timestamp without time zone,
+    failed_at timestamp without time zone,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp without time zone,
+    PRIMARY KEY (leaf_hub_name, id)
+);
+CREATE INDEX IF NOT EXISTS cluster_provisioning_cluster_idx ON status.cluster_provisioning (cluster_name);
+CREATE INDEX IF NOT EXISTS cluster_provisioning_phase_idx ON status.cluster_provisioning (kind, operation, phase);
+CREATE INDEX IF NOT EXISTS cluster_provisioning_name_idx ON status.cluster_provisioning (leaf_hub_name, namespace, name);
+
 -- Partition tables
 CREATE TABLE IF NOT EXISTS event.managed_clusters (
     event_namespace text NOT NULL,
@@ -105,6 +285,31 @@ CREATE TABLE IF NOT EXISTS event.local_root_policies (
     CONSTRAINT local_root_policies_unique_constraint UNIQUE (event_name, count, created_at)
 ) PARTITION BY RANGE (created_at);
 
+-- the events of the involved objects declared by the resourceEvents of the agents, the involved objects are identified
+-- by the group, version and kind, so the new event sources don't need a dedicated table
+CREATE TABLE IF NOT EXISTS event.resource_events (
+    event_namespace text NOT NULL,
+    event_name text NOT NULL,
+    leaf_hub_name character varying(256) NOT NULL,
+    involved_group character varying(253) NOT NULL DEFAULT '',
+    involved_version character varying(63) NOT NULL DEFAULT '',
+    involved_kind character varying(63) NOT NULL,
+    involved_namespace text,
+    involved_name text NOT NULL,
+    involved_uid character varying(63),
+    message text,
+    reason text,
+    count integer NOT NULL DEFAULT 0,
+    reporting_controller text,
+    reporting_instance text,
+    event_type character varying(64) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT resource_events_unique_constraint UNIQUE (leaf_hub_name, event_namespace, event_name, created_at)
+) PARTITION BY RANGE (created_at);
+CREATE INDEX IF NOT EXISTS resource_events_gvk_idx ON event.resource_events (involved_group, involved_kind);
+CREATE INDEX IF NOT EXISTS resource_events_object_idx ON event.resource_events (leaf_hub_name, involved_namespace,
+    involved_name);
+
 -- log tables
 CREATE TABLE IF NOT EXISTS event.data_retention_job_log (
     table_name varchar(254) NOT NULL,
@@ -113,7 +318,11 @@ CREATE TABLE IF NOT EXISTS event.data_retention_job_log (
     min_partition varchar(254), -- minimum partition after the job
     max_partition varchar(254), -- maximum partition after the job
     min_deletion  timestamp, -- the oldest deleted record in the table after the job
-    error TEXT
+    error TEXT,
+    archived_partition varchar(254), -- the partition exported before it's dropped by the job
+    archive_uri TEXT, -- the location of the archived partition
+    archive_rows bigint, -- the number of rows in the archived partition
+    archive_checksum varchar(64) -- the sha256 checksum of the archived file
 );
 
 CREATE TABLE IF NOT EXISTS history.local_compliance (
@@ -126,6 +335,24 @@ CREATE TABLE IF NOT EXISTS history.local_compliance (
     CONSTRAINT local_policies_unique_constraint UNIQUE (leaf_hub_name, policy_id, cluster_id, compliance_date)
 ) PARTITION BY RANGE (compliance_date);
 
+-- the weighted compliance scores snapshotted by the manager, the scope is one of cluster, hub, standard, category,
+-- control, clusterset and global. The leaf_hub_name is empty for the scopes across the managed hubs
+CREATE TABLE IF NOT EXISTS history.compliance_scores (
+    score_date DATE NOT NULL,
+    scope character varying(63) NOT NULL,
+    name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL DEFAULT '',
+    score numeric(5,2), -- it's null if none of the clusters is compliant or non_compliant
+    weighted_compliant integer NOT NULL DEFAULT 0,
+    weighted_total integer NOT NULL DEFAULT 0,
+    compliant integer NOT NULL DEFAULT 0,
+    non_compliant integer NOT NULL DEFAULT 0,
+    pending integer NOT NULL DEFAULT 0,
+    unknown integer NOT NULL DEFAULT 0,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (score_date, scope, name, leaf_hub_name)
+) PARTITION BY RANGE (score_date);
+
 CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     name varchar(254) NOT NULL,
     start_at timestamp NOT NULL DEFAULT now(),
@@ -156,3 +383,21 @@ CREATE TABLE IF NOT EXISTS security.alert_counts (
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     PRIMARY KEY (hub_name, source)
 );
+
+-- the spec events sent by the manager to the managed hubs, the result is updated by the acknowledgements of the
+-- managed hubs, the records are kept for the change management
+CREATE TABLE IF NOT EXISTS audit.actions (
+    id text PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    initiator text NOT NULL,
+    correlation_id text,
+    payload_hash character varying(64) NOT NULL,
+    payload_size integer NOT NULL,
+    result character varying(63) NOT NULL,
+    message text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    acknowledged_at timestamp without time zone
+);
+CREATE INDEX IF NOT EXISTS actions_leaf_hub_idx ON audit.actions (leaf_hub_name, created_at);
+CREATE INDEX IF NOT EXISTS actions_correlation_idx ON audit.actions (correlation_id);
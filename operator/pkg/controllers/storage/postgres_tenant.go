@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// +kubebuilder:rbac:groups=global-hub.open-cluster-management.io,resources=globalhubtenants,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=global-hub.open-cluster-management.io,resources=globalhubtenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+
+const (
+	// the group role of the tenants, it's created by the tenant_isolation migration
+	tenantGroupRole          = "global_hub_tenant"
+	tenantRolePrefix         = "tenant_"
+	tenantSecretNamePrefix   = "postgresql-tenant-" // #nosec G101
+	tenantSecretOwnerLabel   = "globalhubtenant"
+	postgresMaxIdentifierLen = 63
+)
+
+// TenantDatabaseUser returns the database role of the tenant, it's a valid postgres identifier
+func TenantDatabaseUser(tenantName string) string {
+	role := tenantRolePrefix + strings.NewReplacer("-", "_", ".", "_").Replace(tenantName)
+	if len(role) <= postgresMaxIdentifierLen {
+		return role
+	}
+	hash := sha256.Sum256([]byte(tenantName))
+	return role[:postgresMaxIdentifierLen-9] + "_" + hex.EncodeToString(hash[:])[:8]
+}
+
+// TenantSecretName returns the secret of the database connection for the tenant
+func TenantSecretName(tenantName string) string {
+	return tenantSecretNamePrefix + tenantName
+}
+
+// applyTenants creates the database roles for the tenants, and restricts their rows to the managed hubs of the
+// tenants by the row level security. The roles of the deleted tenants are dropped
+func (r *PostgresConfigUserReconciler) applyTenants(ctx context.Context, conn *pgx.Conn,
+	mgh *v1alpha4.MulticlusterGlobalHub,
+) error {
+	tenants := &tenantv1alpha1.GlobalHubTenantList{}
+	if err := r.GetClient().List(ctx, tenants); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list the global hub tenants: %w", err)
+	}
+
+	var isolationEnabled bool
+	if err := conn.QueryRow(ctx, "SELECT to_regproc('public.enable_tenant_isolation') IS NOT NULL").
+		Scan(&isolationEnabled); err != nil {
+		return fmt.Errorf("failed to check the tenant isolation: %w", err)
+	}
+	if !isolationEnabled {
+		if len(tenants.Items) > 0 {
+			return fmt.Errorf("the tenant isolation migration isn't applied to the database yet")
+		}
+		return nil
+	}
+
+	// the tables created after the migration should also be isolated
+	if _, err := conn.Exec(ctx, "SELECT public.enable_tenant_isolation()"); err != nil {
+		return fmt.Errorf("failed to enable the tenant isolation: %w", err)
+	}
+
+	var errs []error
+	roles := sets.New[string]()
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if tenant.DeletionTimestamp != nil {
+			continue
+		}
+		roles.Insert(TenantDatabaseUser(tenant.Name))
+		if err := r.applyTenant(ctx, conn, tenant, mgh); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply the tenant %s: %w", tenant.Name, err))
+		}
+	}
+
+	if err := pruneTenantRoles(ctx, conn, roles); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *PostgresConfigUserReconciler) applyTenant(ctx context.Context, conn *pgx.Conn,
+	tenant *tenantv1alpha1.GlobalHubTenant, mgh *v1alpha4.MulticlusterGlobalHub,
+) error {
+	role := TenantDatabaseUser(tenant.Name)
+
+	hubs, err := r.resolveTenantHubs(ctx, tenant)
+	if err == nil {
+		err = ensureTenantRole(ctx, conn, role, hubs)
+	}
+	if err == nil {
+		err = r.ensureTenantSecret(ctx, tenant, role, mgh.Namespace)
+	}
+
+	condition := metav1.Condition{
+		Type:    tenantv1alpha1.ConditionTypeDatabaseReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DatabaseUserReady",
+		Message: fmt.Sprintf("the database user %s can access %d managed hubs", role, len(hubs)),
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DatabaseUserFailed"
+		condition.Message = err.Error()
+	}
+	statusErr := config.UpdateTenantStatus(ctx, r.GetClient(), tenant.Name,
+		func(status *tenantv1alpha1.GlobalHubTenantStatus) {
+			if err == nil {
+				status.ManagedHubs = hubs
+				status.DatabaseUser = role
+				status.DatabaseSecret = TenantSecretName(tenant.Name)
+			}
+			meta.SetStatusCondition(&status.Conditions, condition)
+		})
+	return utilerrors.NewAggregate([]error{err, statusErr})
+}
+
+// resolveTenantHubs returns the sorted managed hubs of the tenant, which are the explicit hubs and the managed
+// clusters selected by the label selector
+func (r *PostgresConfigUserReconciler) resolveTenantHubs(ctx context.Context,
+	tenant *tenantv1alpha1.GlobalHubTenant,
+) ([]string, error) {
+	hubs := sets.New(tenant.Spec.ManagedHubs...)
+	if tenant.Spec.ManagedHubSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(tenant.Spec.ManagedHubSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid managed hub selector: %w", err)
+		}
+		if !selector.Empty() {
+			clusters := &clusterv1.ManagedClusterList{}
+			if err := r.GetClient().List(ctx, clusters,
+				&client.ListOptions{LabelSelector: selector}); err != nil {
+				return nil, fmt.Errorf("failed to list the managed hubs: %w", err)
+			}
+			hubs.Insert(selectedHubs(clusters.Items, selector)...)
+		}
+	}
+	return sets.List(hubs), nil
+}
+
+// selectedHubs filters the managed clusters by the selector, the local cluster isn't a managed hub
+func selectedHubs(clusters []clusterv1.ManagedCluster, selector labels.Selector) []string {
+	hubs := []string{}
+	for _, cluster := range clusters {
+		if cluster.Labels[constants.LocalClusterName] == "true" || !selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		hubs = append(hubs, cluster.Name)
+	}
+	return hubs
+}
+
+// ensureTenantRole creates the login role of the tenant as the member of the global_hub_tenant, and replaces the
+// managed hubs of the role in a transaction
+func ensureTenantRole(ctx context.Context, conn *pgx.Conn, role string, hubs []string) error {
+	if _, err := ensurePgUser(ctx, conn, role); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, fmt.Sprintf("GRANT %s TO \"%s\"", tenantGroupRole, role)); err != nil {
+		return fmt.Errorf("failed to grant the tenant role to %s: %w", role, err)
+	}
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM public.tenant_hubs WHERE role_name = $1 AND NOT leaf_hub_name = ANY($2)",
+			role, hubs); err != nil {
+			return fmt.Errorf("failed to delete the managed hubs of %s: %w", role, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO public.tenant_hubs (role_name, leaf_hub_name)
+			SELECT $1, unnest($2::varchar[]) ON CONFLICT DO NOTHING`, role, hubs); err != nil {
+			return fmt.Errorf("failed to insert the managed hubs of %s: %w", role, err)
+		}
+		return nil
+	})
+}
+
+// pruneTenantRoles drops the tenant roles which don't belong to any tenant
+func pruneTenantRoles(ctx context.Context, conn *pgx.Conn, roles sets.Set[string]) error {
+	rows, err := conn.Query(ctx, `SELECT r.rolname FROM pg_roles r
+		JOIN pg_auth_members m ON m.member = r.oid
+		JOIN pg_roles g ON g.oid = m.roleid
+		WHERE g.rolname = $1`, tenantGroupRole)
+	if err != nil {
+		return fmt.Errorf("failed to query the tenant roles: %w", err)
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to read the tenant roles: %w", err)
+	}
+
+	for _, role := range existing {
+		if roles.Has(role) || !strings.HasPrefix(role, tenantRolePrefix) {
+			continue
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, "DELETE FROM public.tenant_hubs WHERE role_name = $1", role); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf("DROP OWNED BY \"%s\"", role)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, fmt.Sprintf("DROP ROLE \"%s\"", role))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to drop the tenant role %s: %w", role, err)
+		}
+		log.Infof("dropped the postgres user of the deleted tenant: %s", role)
+	}
+	return nil
+}
+
+// ensureTenantSecret creates the database connection secret of the tenant in the global hub namespace, it's owned
+// by the tenant and has the same keys as the customized user secrets
+func (r *PostgresConfigUserReconciler) ensureTenantSecret(ctx context.Context,
+	tenant *tenantv1alpha1.GlobalHubTenant, role, namespace string,
+) error {
+	storageConn := config.GetStorageConnection()
+	pgConfig, err := pgx.ParseConfig(storageConn.SuperuserDatabaseURI)
+	if err != nil {
+		return fmt.Errorf("failed the parse the supper user database URI")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TenantSecretName(tenant.Name),
+			Namespace: namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.GetClient(), secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[constants.GlobalHubOwnerLabelKey] = tenantSecretOwnerLabel
+		secret.Data = map[string][]byte{
+			PostgresCustomizedUserSecretHostKey:      []byte(pgConfig.Host),
+			PostgresCustomizedUserSecretPortKey:      []byte(fmt.Sprintf("%d", pgConfig.Port)),
+			PostgresCustomizedUserSecretUserKey:      []byte(role),
+			PostgresCustomizedUserSecretPasswordKey:  []byte(userPassword(role, PostgresCustomizedUserPasswordSalt)),
+			PostgresCustomizedUserSecretDatabasesKey: []byte(fmt.Sprintf("[%q]", pgConfig.Database)),
+			PostgresCustomizedUserSecretCACertKey:    storageConn.CACert,
+		}
+		return controllerutil.SetOwnerReference(tenant, secret, r.GetScheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply the tenant secret %s: %w", secret.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Infof("%s the postgresql tenant secret: %s", result, secret.Name)
+	}
+	return nil
+}
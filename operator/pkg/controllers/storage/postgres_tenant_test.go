@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestTenantDatabaseUser(t *testing.T) {
+	assert.Equal(t, "tenant_team_a", TenantDatabaseUser("team-a"))
+	assert.Equal(t, "tenant_team_a_example_com", TenantDatabaseUser("team-a.example.com"))
+
+	longName := strings.Repeat("a", 100)
+	user := TenantDatabaseUser(longName)
+	assert.Len(t, user, postgresMaxIdentifierLen)
+	assert.True(t, strings.HasPrefix(user, tenantRolePrefix))
+	assert.NotEqual(t, user, TenantDatabaseUser(longName+"b"))
+	assert.Equal(t, user, TenantDatabaseUser(longName))
+}
+
+func TestSelectedHubs(t *testing.T) {
+	clusters := []clusterv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "hub1", Labels: map[string]string{"tenant": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "hub2", Labels: map[string]string{"tenant": "b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "local-cluster", Labels: map[string]string{
+			"tenant": "a", "local-cluster": "true",
+		}}},
+	}
+	selector := labels.SelectorFromSet(labels.Set{"tenant": "a"})
+	assert.Equal(t, []string{"hub1"}, selectedHubs(clusters, selector))
+}
@@ -13,6 +13,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	tenantv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/tenant/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -96,6 +99,14 @@ func (r *PostgresConfigUserReconciler) SetupWithManager(mgr ctrl.Manager) error
 						PostgresCustomizedUsersConfigMapName
 				},
 			})).
+		Watches(&tenantv1alpha1.GlobalHubTenant{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&clusterv1.ManagedCluster{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+				},
+			})).
 		Complete(r)
 }
 
@@ -103,24 +114,23 @@ func (r *PostgresConfigUserReconciler) Reconcile(ctx context.Context, req ctrl.R
 	log.Debug("reconcile config user controller")
 
 	mgh, err := config.GetMulticlusterGlobalHub(ctx, r.GetClient())
-	if err != nil || mgh == nil || config.IsPaused(mgh) || config.IsBYOPostgres() {
+	if err != nil || mgh == nil || config.IsPaused(mgh) {
 		return ctrl.Result{}, err
 	}
 	if mgh.DeletionTimestamp != nil {
 		return ctrl.Result{}, nil
 	}
 
-	// get the user configMap
+	// get the user configMap, the customized users aren't supported for the BYO postgres
 	pgUsers := &corev1.ConfigMap{}
-	err = r.GetClient().Get(ctx, client.ObjectKey{
-		Name:      PostgresCustomizedUsersConfigMapName,
-		Namespace: mgh.Namespace,
-	}, pgUsers)
-	if err != nil && !errors.IsNotFound(err) {
-		return ctrl.Result{}, fmt.Errorf("unable to fetch ConfigMap: %w", err)
-	}
-	if errors.IsNotFound(err) || pgUsers.Data == nil {
-		return ctrl.Result{}, nil
+	if !config.IsBYOPostgres() {
+		err = r.GetClient().Get(ctx, client.ObjectKey{
+			Name:      PostgresCustomizedUsersConfigMapName,
+			Namespace: mgh.Namespace,
+		}, pgUsers)
+		if err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("unable to fetch ConfigMap: %w", err)
+		}
 	}
 
 	// get db connection
@@ -143,10 +153,16 @@ func (r *PostgresConfigUserReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	if err = r.applyPostgresUsers(ctx, conn, pgUsers.Data, mgh); err != nil {
+	if len(pgUsers.Data) > 0 {
+		if err = r.applyPostgresUsers(ctx, conn, pgUsers.Data, mgh); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Infof("applied the postgresql users successfully!")
+	}
+
+	if err = r.applyTenants(ctx, conn, mgh); err != nil {
 		return ctrl.Result{}, err
 	}
-	log.Infof("applied the postgresql users successfully!")
 	return ctrl.Result{}, nil
 }
 
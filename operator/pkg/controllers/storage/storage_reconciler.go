@@ -31,6 +31,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonutils "github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -48,9 +49,6 @@ import (
 //go:embed database
 var databaseFS embed.FS
 
-//go:embed upgrade
-var upgradeFS embed.FS
-
 //go:embed manifests.sts
 var stsPostgresFS embed.FS
 
@@ -58,7 +56,8 @@ var log = logger.DefaultZapLogger()
 
 type StorageReconciler struct {
 	ctrl.Manager
-	upgrade                bool
+	migrated               bool
+	migrationCondition     *metav1.Condition
 	databaseReconcileCount int
 	enableMetrics          bool
 }
@@ -101,7 +100,7 @@ func StartController(initOption config.ControllerOption) (config.ControllerInter
 func NewStorageReconciler(mgr ctrl.Manager, enableMetrics bool) *StorageReconciler {
 	return &StorageReconciler{
 		Manager:                mgr,
-		upgrade:                false,
+		migrated:               false,
 		databaseReconcileCount: 0,
 		enableMetrics:          enableMetrics,
 	}
@@ -232,12 +231,23 @@ func (r *StorageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		Namespace: mgh.Namespace,
 		Name:      mgh.Name,
 	}, getRetentionConditions(mgh), "")
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
+
+	// Update migration condition
+	if r.migrationCondition != nil {
+		reconcileErr = config.UpdateCondition(ctx, r.GetClient(), types.NamespacedName{
+			Namespace: mgh.Namespace,
+			Name:      mgh.Name,
+		}, *r.migrationCondition, "")
+	}
 
 	return ctrl.Result{}, reconcileErr
 }
 
 func getRetentionConditions(mgh *v1alpha4.MulticlusterGlobalHub) metav1.Condition {
-	months, err := commonutils.ParseRetentionMonth(mgh.Spec.DataLayerSpec.Postgres.Retention)
+	retentionMonths, err := config.GetRetentionMonths(mgh)
 	if err != nil {
 		err = fmt.Errorf("failed to parse the retention month, err:%v", err)
 		return metav1.Condition{
@@ -248,10 +258,23 @@ func getRetentionConditions(mgh *v1alpha4.MulticlusterGlobalHub) metav1.Conditio
 		}
 	}
 
-	if months < 1 {
-		months = 1
+	archive := mgh.Spec.DataLayerSpec.Postgres.Archive
+	if err = config.ValidateArchive(archive); err != nil {
+		return metav1.Condition{
+			Type:    config.CONDITION_TYPE_DATABASE,
+			Status:  config.CONDITION_STATUS_FALSE,
+			Reason:  config.CONDITION_REASON_RETENTION_PARSED_FAILED,
+			Message: err.Error(),
+		}
+	}
+
+	msg := fmt.Sprintf("The data will be kept in the database for %s.", retentionMonths.String())
+	if archive != nil && archive.S3 != nil {
+		msg += fmt.Sprintf(" The expired partitions are archived to the bucket %s.", archive.S3.Bucket)
+	} else if archive != nil && archive.PersistentVolumeClaim != "" {
+		msg += fmt.Sprintf(" The expired partitions are archived to the persistent volume claim %s.",
+			archive.PersistentVolumeClaim)
 	}
-	msg := fmt.Sprintf("The data will be kept in the database for %d months.", months)
 	return metav1.Condition{
 		Type:    config.CONDITION_TYPE_DATABASE,
 		Status:  config.CONDITION_STATUS_TRUE,
@@ -293,8 +316,8 @@ func (r *StorageReconciler) ReconcileStorage(ctx context.Context,
 }
 
 func (r *StorageReconciler) ReconcileDatabase(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub) (bool, error) {
-	// Don't reconcile, or create the connection, when database has been initialized
-	if r.databaseReconcileCount > 0 {
+	// Don't reconcile, or create the connection, when database has been initialized and migrated
+	if r.databaseReconcileCount > 0 && r.migrated {
 		return false, nil
 	}
 	storageConn := config.GetStorageConnection()
@@ -323,30 +346,26 @@ func (r *StorageReconciler) ReconcileDatabase(ctx context.Context, mgh *v1alpha4
 		log.Debug("global hub database initialized")
 		r.databaseReconcileCount++
 	}
+
+	// migrate the database schema to the latest version, or only plan it in the dry-run mode
+	if err = r.migrateDatabase(ctx, conn, config.IsDatabaseMigrationDryRun(mgh)); err != nil {
+		return false, err
+	}
 	return false, nil
 }
 
 func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx.Conn, readonlyUserURI string) error {
-	// Check if backup is enabled
-	var backupEnabled bool
-	backupEnabled, err := commonutils.IsBackupEnabled(ctx, r.GetClient())
-	if err != nil {
-		return fmt.Errorf("failed to get the backup status: %v", err)
-	}
-
-	if backupEnabled || !r.upgrade {
-		lockSql := fmt.Sprintf("select pg_advisory_lock(%s)", constants.LockId)
-		unLockSql := fmt.Sprintf("select pg_advisory_unlock(%s)", constants.LockId)
-		defer func() {
-			_, err = conn.Exec(ctx, unLockSql)
-			if err != nil {
-				log.Errorf("failed to unlock db: %v", err)
-			}
-		}()
-		_, err = conn.Exec(ctx, lockSql)
+	lockSql := fmt.Sprintf("select pg_advisory_lock(%s)", constants.LockId)
+	unLockSql := fmt.Sprintf("select pg_advisory_unlock(%s)", constants.LockId)
+	defer func() {
+		_, err := conn.Exec(ctx, unLockSql)
 		if err != nil {
-			return fmt.Errorf("failed to parse database_uri_with_readonlyuser: %v", err)
+			log.Errorf("failed to unlock db: %v", err)
 		}
+	}()
+	_, err := conn.Exec(ctx, lockSql)
+	if err != nil {
+		return fmt.Errorf("failed to lock db: %v", err)
 	}
 
 	objURI, err := url.Parse(readonlyUserURI)
@@ -358,18 +377,69 @@ func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx
 	if err = applySQL(ctx, conn, databaseFS, "database", readonlyUsername); err != nil {
 		return fmt.Errorf("failed to apply the database sql: %v", err)
 	}
+	return nil
+}
 
-	if !r.upgrade {
-		err = applySQL(ctx, conn, upgradeFS, "upgrade", readonlyUsername)
-		if err != nil {
-			return fmt.Errorf("failed to apply the upgrade sql: %v", err)
+// migrateDatabase applies the pending migrations after the base schema is initialized, and records the result in
+// the migration condition of the mgh
+func (r *StorageReconciler) migrateDatabase(ctx context.Context, conn *pgx.Conn, dryRun bool) error {
+	migrations, err := runMigrations(ctx, conn, dryRun)
+	if err != nil {
+		r.migrationCondition = &metav1.Condition{
+			Type:    config.CONDITION_TYPE_DATABASE_MIGRATED,
+			Status:  config.CONDITION_STATUS_FALSE,
+			Reason:  config.CONDITION_REASON_DATABASE_MIGRATION_FAILED,
+			Message: err.Error(),
 		}
-		r.upgrade = true
+		return fmt.Errorf("failed to migrate the database: %w", err)
 	}
-
+	r.migrated = !dryRun
+	r.migrationCondition = getMigrationCondition(migrations, dryRun)
 	return nil
 }
 
+// runMigrations returns the pending migrations in the dry-run mode, otherwise applies and returns them
+func runMigrations(ctx context.Context, conn *pgx.Conn, dryRun bool) ([]migration.Migration, error) {
+	migrations, err := migration.GlobalHubMigrations()
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migration.NewMigrator(conn, migrations)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return migrator.Plan(ctx)
+	}
+	return migrator.Up(ctx)
+}
+
+// getMigrationCondition returns the condition of the pending migrations in the dry-run mode, otherwise the applied
+func getMigrationCondition(migrations []migration.Migration, dryRun bool) *metav1.Condition {
+	names := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		names = append(names, m.String())
+	}
+	if dryRun && len(migrations) > 0 {
+		return &metav1.Condition{
+			Type:    config.CONDITION_TYPE_DATABASE_MIGRATED,
+			Status:  config.CONDITION_STATUS_FALSE,
+			Reason:  config.CONDITION_REASON_DATABASE_MIGRATION_PENDING,
+			Message: fmt.Sprintf("The migrations are pending in the dry-run mode: %s", strings.Join(names, ", ")),
+		}
+	}
+	msg := "The database schema is up to date."
+	if len(migrations) > 0 {
+		msg = fmt.Sprintf("The database schema is migrated: %s", strings.Join(names, ", "))
+	}
+	return &metav1.Condition{
+		Type:    config.CONDITION_TYPE_DATABASE_MIGRATED,
+		Status:  config.CONDITION_STATUS_TRUE,
+		Reason:  config.CONDITION_REASON_DATABASE_MIGRATED,
+		Message: msg,
+	}
+}
+
 func applySQL(ctx context.Context, conn *pgx.Conn, databaseFS embed.FS, rootDir, username string) error {
 	err := iofs.WalkDir(databaseFS, rootDir, func(file string, d iofs.DirEntry, beforeError error) error {
 		if beforeError != nil {
@@ -5,13 +5,17 @@ package webhook
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -26,11 +30,12 @@ import (
 var log = logger.DefaultZapLogger()
 
 // NewAdmissionHandler creates a new admission webhook handler.
-// It handles ManagedCluster and KlusterletAddonConfig resources.
+// It handles ManagedCluster, KlusterletAddonConfig, PolicyRemediationRequest and ManagedClusterMigration resources.
 // For ManagedCluster, it checks for a specific label to determine if the cluster
 // should be treated as hosted, and adds necessary annotations.
 // For KlusterletAddonConfig, it disables addons if the corresponding ManagedCluster
 // is in hosted mode.
+// For PolicyRemediationRequest and ManagedClusterMigration, it sets the requester into the user identity annotations.
 func NewAdmissionHandler(c client.Client, s *runtime.Scheme) admission.Handler {
 	return &admissionHandler{
 		client:  c,
@@ -53,6 +58,8 @@ func (a *admissionHandler) Handle(ctx context.Context, req admission.Request) ad
 		return a.handleManagedCluster(ctx, req)
 	case "KlusterletAddonConfig":
 		return a.handleKlusterletAddonConfig(ctx, req)
+	case "PolicyRemediationRequest", constants.ManagedClusterMigrationKind:
+		return a.handleRequester(req)
 	default:
 		return admission.Allowed("")
 	}
@@ -187,6 +194,60 @@ func (a *admissionHandler) handleKlusterletAddonConfig(ctx context.Context, req
 	return admission.Allowed("")
 }
 
+// handleRequester handles the admission request for the PolicyRemediationRequest and ManagedClusterMigration
+// It sets the requester into the user identity annotations when it's created, the managed hubs impersonate the
+// requester to apply the request. The annotations given by the requester are overridden, and they're kept by the
+// updates, so the requester can't act as others.
+func (a *admissionHandler) handleRequester(req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	request := &unstructured.Unstructured{}
+	if err := request.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	userIdentity := base64.StdEncoding.EncodeToString([]byte(req.UserInfo.Username))
+	userGroups := ""
+	if len(req.UserInfo.Groups) > 0 {
+		userGroups = base64.StdEncoding.EncodeToString([]byte(strings.Join(req.UserInfo.Groups, ",")))
+	}
+	if req.Operation == admissionv1.Update {
+		if len(req.OldObject.Raw) == 0 {
+			return admission.Allowed("")
+		}
+		old := &unstructured.Unstructured{}
+		if err := old.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		userIdentity = old.GetAnnotations()[constants.UserIdentityAnnotation]
+		userGroups = old.GetAnnotations()[constants.UserGroupsAnnotation]
+	}
+
+	annotations := request.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[constants.UserIdentityAnnotation] == userIdentity &&
+		annotations[constants.UserGroupsAnnotation] == userGroups {
+		return admission.Allowed("")
+	}
+	annotations[constants.UserIdentityAnnotation] = userIdentity
+	delete(annotations, constants.UserGroupsAnnotation)
+	if userGroups != "" {
+		annotations[constants.UserGroupsAnnotation] = userGroups
+	}
+	request.SetAnnotations(annotations)
+
+	log.Infof("set the requester into the %s: %s/%s", strings.ToLower(req.Kind.Kind), request.GetNamespace(),
+		request.GetName())
+	marshaledRequest, err := request.MarshalJSON()
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledRequest)
+}
+
 // getLocalClusterName gets the local cluster name of the current cluster,
 func getLocalClusterName(ctx context.Context, client client.Client) (string, error) {
 	mcList := &clusterv1.ManagedClusterList{}
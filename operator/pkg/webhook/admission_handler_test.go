@@ -5,11 +5,13 @@ package webhook
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 
 	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,6 +19,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	remediationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/remediation/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
@@ -869,3 +873,227 @@ func TestAdmissionHandler_Handle_CrossCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestAdmissionHandler_handlePolicyRemediationRequest(t *testing.T) {
+	request := &remediationv1alpha1.PolicyRemediationRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: remediationv1alpha1.GroupVersion.String(),
+			Kind:       "PolicyRemediationRequest",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "enforce-policies",
+			Namespace: "default",
+			Annotations: map[string]string{
+				// the requester can't act as others
+				remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("admin")),
+				remediationv1alpha1.UserGroupsAnnotation:   base64.StdEncoding.EncodeToString([]byte("admins")),
+			},
+		},
+		Spec: remediationv1alpha1.PolicyRemediationRequestSpec{
+			Action:   remediationv1alpha1.ActionEnforce,
+			Policies: []remediationv1alpha1.PolicyReference{{Namespace: "default", Name: "policy1"}},
+		},
+	}
+	objJSON, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the request created by alice, the update above tries to act as the admin
+	created := request.DeepCopy()
+	created.Annotations = map[string]string{
+		remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+	}
+	oldJSON, err := json.Marshal(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		operation       admissionv1.Operation
+		object          []byte
+		oldObject       []byte
+		groups          []string
+		expectedPatches map[string]string
+	}{
+		{
+			name:      "create with the groups",
+			operation: admissionv1.Create,
+			groups:    []string{"system:authenticated", "policy-operators"},
+			expectedPatches: map[string]string{
+				remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+				remediationv1alpha1.UserGroupsAnnotation: base64.StdEncoding.EncodeToString(
+					[]byte("system:authenticated,policy-operators")),
+			},
+		},
+		{
+			name:      "create without the groups",
+			operation: admissionv1.Create,
+			expectedPatches: map[string]string{
+				remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+			},
+		},
+		{
+			name:      "update is allowed without patches",
+			operation: admissionv1.Update,
+		},
+		{
+			name:      "update keeps the requester",
+			operation: admissionv1.Update,
+			oldObject: oldJSON,
+			expectedPatches: map[string]string{
+				remediationv1alpha1.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+			},
+		},
+		{
+			name:      "update without changing the requester",
+			operation: admissionv1.Update,
+			object:    oldJSON,
+			oldObject: oldJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admissionHandler := &admissionHandler{}
+			object := tt.object
+			if object == nil {
+				object = objJSON
+			}
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Kind:      metav1.GroupVersionKind{Kind: "PolicyRemediationRequest"},
+					Operation: tt.operation,
+					Object:    runtime.RawExtension{Raw: object},
+					OldObject: runtime.RawExtension{Raw: tt.oldObject},
+					UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: tt.groups},
+				},
+			}
+			resp := admissionHandler.Handle(context.Background(), req)
+			if !resp.Allowed {
+				t.Fatalf("expected allowed, got result=%v", resp.Result)
+			}
+
+			patched := request.DeepCopy()
+			for _, patch := range resp.Patches {
+				value, _ := patch.Value.(string)
+				switch patch.Path {
+				case "/metadata/annotations/open-cluster-management.io~1user-identity":
+					patched.Annotations[remediationv1alpha1.UserIdentityAnnotation] = value
+				case "/metadata/annotations/open-cluster-management.io~1user-group":
+					if patch.Operation == "remove" {
+						delete(patched.Annotations, remediationv1alpha1.UserGroupsAnnotation)
+					} else {
+						patched.Annotations[remediationv1alpha1.UserGroupsAnnotation] = value
+					}
+				default:
+					t.Errorf("unexpected patch %v", patch)
+				}
+			}
+			if tt.expectedPatches == nil {
+				if len(resp.Patches) != 0 {
+					t.Errorf("expected no patches, got %v", resp.Patches)
+				}
+				return
+			}
+			if len(patched.Annotations) != len(tt.expectedPatches) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedPatches, patched.Annotations)
+			}
+			for key, value := range tt.expectedPatches {
+				if patched.Annotations[key] != value {
+					t.Errorf("expected annotation %s=%s, got %s", key, value, patched.Annotations[key])
+				}
+			}
+		})
+	}
+}
+
+func TestAdmissionHandler_handleManagedClusterMigration(t *testing.T) {
+	migration := &migrationv1alpha1.ManagedClusterMigration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: migrationv1alpha1.GroupVersion.String(),
+			Kind:       constants.ManagedClusterMigrationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "multicluster-global-hub"},
+		Spec:       migrationv1alpha1.ManagedClusterMigrationSpec{From: "hub1", To: "hub2"},
+	}
+	created := migration.DeepCopy()
+	created.Annotations = map[string]string{
+		constants.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+	}
+	// the requester of the update tries to act as the admin
+	updated := created.DeepCopy()
+	updated.Annotations[constants.UserIdentityAnnotation] = base64.StdEncoding.EncodeToString([]byte("admin"))
+	toRaw := func(obj any) []byte {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	tests := []struct {
+		name          string
+		operation     admissionv1.Operation
+		object        []byte
+		oldObject     []byte
+		expectedPatch string
+	}{
+		{
+			name:          "create",
+			operation:     admissionv1.Create,
+			object:        toRaw(migration),
+			expectedPatch: created.Annotations[constants.UserIdentityAnnotation],
+		},
+		{
+			name:          "update keeps the requester",
+			operation:     admissionv1.Update,
+			object:        toRaw(updated),
+			oldObject:     toRaw(created),
+			expectedPatch: created.Annotations[constants.UserIdentityAnnotation],
+		},
+		{
+			name:      "update without changing the requester",
+			operation: admissionv1.Update,
+			object:    toRaw(created),
+			oldObject: toRaw(created),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admissionHandler := &admissionHandler{}
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Kind:      metav1.GroupVersionKind{Kind: constants.ManagedClusterMigrationKind},
+					Operation: tt.operation,
+					Object:    runtime.RawExtension{Raw: tt.object},
+					OldObject: runtime.RawExtension{Raw: tt.oldObject},
+					UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+				},
+			}
+			resp := admissionHandler.Handle(context.Background(), req)
+			if !resp.Allowed {
+				t.Fatalf("expected allowed, got result=%v", resp.Result)
+			}
+			if tt.expectedPatch == "" {
+				if len(resp.Patches) != 0 {
+					t.Errorf("expected no patches, got %v", resp.Patches)
+				}
+				return
+			}
+			found := false
+			for _, patch := range resp.Patches {
+				switch value := patch.Value.(type) {
+				case string:
+					found = found || value == tt.expectedPatch
+				case map[string]any: // the annotations are added
+					found = found || value[constants.UserIdentityAnnotation] == tt.expectedPatch
+				}
+			}
+			if !found {
+				t.Errorf("expected the user identity %s, got %v", tt.expectedPatch, resp.Patches)
+			}
+		})
+	}
+}
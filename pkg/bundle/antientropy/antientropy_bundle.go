@@ -0,0 +1,80 @@
+package antientropy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"sort"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+)
+
+// DefaultBucketCount is the number of the buckets the objects of an event type are hashed into, a mismatched bucket
+// resends about 1/64 of the objects
+const DefaultBucketCount = 64
+
+// ObjectDigest identifies the version of an object, the ID is the id of the row in the database, e.g. the uid of
+// the object, and the Version is the resource version or the generation which is persisted in the payload
+type ObjectDigest struct {
+	ID      string
+	Version string
+}
+
+// DigestBundle is sent by the managed hub with the bucket hashes of the objects of the event type
+type DigestBundle struct {
+	EventType   string   `json:"eventType"`
+	BucketCount int      `json:"bucketCount"`
+	Buckets     []string `json:"buckets"`
+}
+
+// RepairBundle is sent by the manager to request the managed hub to resend the objects in the mismatched buckets, the
+// Objects are the rows of the buckets in the database, the managed hub deletes the ones which don't exist anymore
+type RepairBundle struct {
+	EventType   string                   `json:"eventType"`
+	BucketCount int                      `json:"bucketCount"`
+	Buckets     []int                    `json:"buckets"`
+	Objects     []generic.ObjectMetadata `json:"objects,omitempty"`
+}
+
+// BucketOf returns the bucket of the object id
+func BucketOf(id string, bucketCount int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return int(h.Sum32() % uint32(bucketCount))
+}
+
+// BucketHashes returns the hashes of the buckets, the hash of a bucket is the sha256 of the sorted ids and versions
+// of its objects, and it's empty if the bucket has no objects
+func BucketHashes(digests []ObjectDigest, bucketCount int) []string {
+	buckets := make([][]ObjectDigest, bucketCount)
+	for _, digest := range digests {
+		bucket := BucketOf(digest.ID, bucketCount)
+		buckets[bucket] = append(buckets[bucket], digest)
+	}
+
+	hashes := make([]string, bucketCount)
+	for i, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+		sort.Slice(bucket, func(a, b int) bool { return bucket[a].ID < bucket[b].ID })
+		h := sha256.New()
+		for _, digest := range bucket {
+			_, _ = h.Write([]byte(digest.ID + ":" + digest.Version + "\n"))
+		}
+		hashes[i] = hex.EncodeToString(h.Sum(nil))
+	}
+	return hashes
+}
+
+// MismatchedBuckets returns the buckets whose hashes are different, all the buckets are mismatched if the bucket
+// counts are different
+func MismatchedBuckets(expected, actual []string) []int {
+	mismatched := []int{}
+	for i := range expected {
+		if i >= len(actual) || expected[i] != actual[i] {
+			mismatched = append(mismatched, i)
+		}
+	}
+	return mismatched
+}
@@ -0,0 +1,29 @@
+package antientropy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketHashes(t *testing.T) {
+	digests := []ObjectDigest{{ID: "a", Version: "1"}, {ID: "b", Version: "2"}, {ID: "c", Version: "3"}}
+	hashes := BucketHashes(digests, 4)
+	assert.Len(t, hashes, 4)
+
+	// the order of the objects doesn't change the hashes
+	reversed := []ObjectDigest{digests[2], digests[1], digests[0]}
+	assert.Equal(t, hashes, BucketHashes(reversed, 4))
+	assert.Empty(t, MismatchedBuckets(hashes, BucketHashes(reversed, 4)))
+
+	// only the bucket of the changed object is mismatched
+	changed := []ObjectDigest{digests[0], {ID: "b", Version: "5"}, digests[2]}
+	assert.Equal(t, []int{BucketOf("b", 4)}, MismatchedBuckets(hashes, BucketHashes(changed, 4)))
+
+	// the bucket of the missing object is mismatched
+	missing := []ObjectDigest{digests[0], digests[1]}
+	assert.Equal(t, []int{BucketOf("c", 4)}, MismatchedBuckets(hashes, BucketHashes(missing, 4)))
+
+	// all the buckets are mismatched if the bucket counts are different
+	assert.Equal(t, []int{0, 1, 2, 3}, MismatchedBuckets(hashes, nil))
+}
@@ -10,6 +10,9 @@ type MinimalCompliance struct {
 	NamespacedName       string                     `json:"-"` // need it to delete obj from bundle for local resources.
 	RemediationAction    policyv1.RemediationAction `json:"remediationAction"`
 	NonCompliantClusters int                        `json:"nonCompliantClusters"`
+	CompliantClusters    int                        `json:"compliantClusters"`
+	PendingClusters      int                        `json:"pendingClusters"`
+	UnknownClusters      int                        `json:"unknownClusters"`
 	AppliedClusters      int                        `json:"appliedClusters"`
 }
 
@@ -0,0 +1,33 @@
+package grc
+
+import "time"
+
+// RelatedObject is an object evaluated by the policy template on the cluster.
+type RelatedObject struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Compliance string `json:"compliance"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// TemplateCompliance holds the compliance of a policy template, e.g. the ConfigurationPolicy, on the cluster.
+type TemplateCompliance struct {
+	TemplateKind   string          `json:"templateKind"`
+	TemplateName   string          `json:"templateName"`
+	Compliance     string          `json:"compliance"`
+	Message        string          `json:"message,omitempty"`
+	LastTimestamp  time.Time       `json:"lastTimestamp,omitempty"`
+	RelatedObjects []RelatedObject `json:"relatedObjects,omitempty"`
+}
+
+// PolicyTemplateCompliance holds the compliance of the templates of the replicated policy on the cluster.
+type PolicyTemplateCompliance struct {
+	PolicyID       string               `json:"policyId"`
+	ClusterName    string               `json:"clusterName"`
+	ClusterID      string               `json:"clusterId"`
+	NamespacedName string               `json:"-"` // the replicated policy, need it to delete obj from bundle.
+	Templates      []TemplateCompliance `json:"templates"`
+}
+
+type PolicyTemplateComplianceBundle []PolicyTemplateCompliance
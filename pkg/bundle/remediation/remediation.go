@@ -0,0 +1,44 @@
+package remediation
+
+// PolicyRemediationBundle is sent by the manager to request the managed hub to remediate the local policies, the ID
+// is the uid of the PolicyRemediationRequest. The UserIdentity and UserGroups are the base64 encoded identity of the
+// requester, the managed hub runs the action as the requester.
+type PolicyRemediationBundle struct {
+	ID                     string          `json:"id"`
+	Action                 string          `json:"action"`
+	Policies               []PolicyRef     `json:"policies"`
+	Clusters               []string        `json:"clusters,omitempty"`
+	EnforceDurationSeconds int64           `json:"enforceDurationSeconds,omitempty"`
+	Automation             *AutomationSpec `json:"automation,omitempty"`
+	UserIdentity           string          `json:"userIdentity"`
+	UserGroups             string          `json:"userGroups,omitempty"`
+}
+
+// PolicyRef is the namespaced name of the root policy on the managed hub
+type PolicyRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// AutomationSpec is the ansible job of the PolicyAutomation created for the policies
+type AutomationSpec struct {
+	Mode         string `json:"mode"`
+	TemplateName string `json:"templateName"`
+	TowerSecret  string `json:"towerSecret"`
+}
+
+// PolicyRemediationResultBundle is sent by the managed hub to report the results of the remediation, the Error is
+// set if the whole request is rejected, e.g. the requester can't be impersonated
+type PolicyRemediationResultBundle struct {
+	ID       string                    `json:"id"`
+	Error    string                    `json:"error,omitempty"`
+	Policies []PolicyRemediationResult `json:"policies,omitempty"`
+}
+
+// PolicyRemediationResult is the result of the action on a policy, the Result is one of Succeeded, Failed and Skipped
+type PolicyRemediationResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Result    string `json:"result"`
+	Message   string `json:"message,omitempty"`
+}
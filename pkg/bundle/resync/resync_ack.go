@@ -0,0 +1,17 @@
+package resync
+
+// ResyncAckBundle is sent by the managed hub to acknowledge the resync request of the manager, the ID is the
+// resync id from the request
+type ResyncAckBundle struct {
+	ID         string              `json:"id"`
+	EventTypes []ResyncedEventType `json:"eventTypes"`
+}
+
+// ResyncedEventType is the result of an event type resynced by the managed hub, the Registered is false if the event
+// type isn't synced by the managed hub
+type ResyncedEventType struct {
+	EventType  string `json:"eventType"`
+	Objects    int    `json:"objects"`
+	Registered bool   `json:"registered"`
+	Error      string `json:"error,omitempty"`
+}
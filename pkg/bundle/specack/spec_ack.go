@@ -0,0 +1,16 @@
+package specack
+
+// SpecAckBundle is sent by the managed hub once the spec event is handled by the syncer, the CorrelationID is from
+// the correlation id extension of the spec event, and the Error is empty if the spec event is applied
+type SpecAckBundle struct {
+	CorrelationID string `json:"correlationId"`
+	EventType     string `json:"eventType"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
+	// Denied is true if the spec event is denied by the RBAC of the managed hub, the denied events without the
+	// correlation id, e.g. the ones sent by the other managed hubs, are acknowledged with their id and Source
+	Denied bool   `json:"denied,omitempty"`
+	Source string `json:"source,omitempty"`
+	// DurationMilliseconds is the duration of the syncer handling the spec event
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+}
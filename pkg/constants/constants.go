@@ -106,6 +106,8 @@ const (
 	LocalClusterName = "local-cluster"
 	// lock the database
 	LockId = "1"
+	// lock the database schema migrations, only one process can migrate the schema at the same time
+	MigrationLockId = "2"
 )
 
 // global hub transport and storage secret and configmap names
@@ -184,6 +186,12 @@ const (
 	// ResyncMsgKey - request resync from the managed hub
 	ResyncMsgKey = "Resync"
 
+	// AntiEntropyRepairMsgKey - request the managed hub to resend the objects of the mismatched buckets
+	AntiEntropyRepairMsgKey = "AntiEntropyRepair"
+
+	// PolicyRemediationMsgKey - request the managed hub to remediate the local policies
+	PolicyRemediationMsgKey = "PolicyRemediation"
+
 	// HubStatusUpdateMsgKey - hub state update message for Hub HA failover
 	HubStatusUpdateMsgKey = "HubStatusUpdate"
 
@@ -239,6 +247,24 @@ const (
 	CloudEventExtensionKeyMigrationId    = "migrationid"
 	CloudEventExtensionKeyMigrationStage = "migrationstage"
 	CloudEventExtensionKeyExpireTime     = "expirytime"
+	// CloudEventExtensionKeyResyncId is the id of the GlobalHubResync, the managed hub acknowledges the resync with it
+	CloudEventExtensionKeyResyncId = "resyncid"
+	// CloudEventExtensionKeyRemediationId is the id of the PolicyRemediationRequest, the managed hub reports the
+	// results of the remediation with it
+	CloudEventExtensionKeyRemediationId = "remediationid"
+	// CloudEventExtensionKeyCorrelationId is the id of the spec event, the managed hub acknowledges the event with it
+	CloudEventExtensionKeyCorrelationId = "correlationid"
+	// CloudEventExtensionKeyTarget is the object which the spec event is about if it isn't the receiving hub, e.g. the
+	// active hub of the hub status update sent to the standby hub
+	CloudEventExtensionKeyTarget = "target"
+	// CloudEventExtensionKeyUserIdentity and CloudEventExtensionKeyUserGroups are the base64 encoded global hub user who
+	// initiates the spec event, the managed hub impersonates the user to apply the event if the RBAC is enforced
+	CloudEventExtensionKeyUserIdentity = "useridentity"
+	CloudEventExtensionKeyUserGroups   = "usergroups"
+	// UserIdentityAnnotation and UserGroupsAnnotation are the base64 encoded global hub user who creates the object,
+	// they're set by the global hub webhook
+	UserIdentityAnnotation = "open-cluster-management.io/user-identity"
+	UserGroupsAnnotation   = "open-cluster-management.io/user-group"
 	// LabelKeyIsManagedServiceAccount is from     managed-serviceaccount/pkg/common/constants.go
 	LabelKeyIsManagedServiceAccount = "authentication.open-cluster-management.io/is-managed-serviceaccount"
 )
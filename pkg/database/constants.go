@@ -44,6 +44,8 @@ const (
 const (
 	// ErrorNone is default value when no error occurs.
 	ErrorNone = "none"
+	// ErrorConflict is set when the cluster is quarantined because it's claimed by several managed hubs.
+	ErrorConflict = "conflict"
 )
 
 // ComplianceStatus represents the possible options for compliance status.
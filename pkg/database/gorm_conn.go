@@ -39,6 +39,8 @@ type DatabaseConfig struct {
 	Dialect    string
 	CaCertPath string
 	PoolSize   int
+	// ReplicaURLs are the read replicas of the database, the read-only queries are routed to them by GetReadGorm
+	ReplicaURLs []string
 }
 
 func InitGormInstance(config *DatabaseConfig) error {
@@ -55,6 +57,9 @@ func InitGormInstance(config *DatabaseConfig) error {
 			return err
 		}
 		sqlDB.SetMaxOpenConns(config.PoolSize)
+		if err = initReplicas(config); err != nil {
+			return err
+		}
 	}
 	return nil
 }
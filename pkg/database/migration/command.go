@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// Command is the subcommand of the manager to migrate the database manually, e.g. revert the migrations before
+// rolling back the global hub: "manager migrate-database --down-to 1 --dry-run"
+const Command = "migrate-database"
+
+// Run migrates the database to the latest version, or reverts it to the version of the --down-to. The migrations are
+// only printed in the dry-run mode
+func Run(ctx context.Context, args []string) error {
+	var databaseURL, caCertPath string
+	var downTo int64
+	var dryRun bool
+	flags := pflag.NewFlagSet(Command, pflag.ContinueOnError)
+	flags.StringVar(&databaseURL, "database-url", os.Getenv("DATABASE_URL"), "The URL of database server")
+	flags.StringVar(&caCertPath, "postgres-ca-path", "/postgres-credential/ca.crt",
+		"The path of CA certificate for the database server")
+	flags.Int64Var(&downTo, "down-to", -1, "Revert the migrations which are newer than the version")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print the migrations without applying or reverting them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if databaseURL == "" {
+		return fmt.Errorf("the database-url is required to migrate the database")
+	}
+
+	cert, err := os.ReadFile(caCertPath) // #nosec G304
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read the database ca cert: %w", err)
+	}
+	conn, err := database.PostgresConnection(ctx, databaseURL, cert)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	migrations, err := GlobalHubMigrations()
+	if err != nil {
+		return err
+	}
+	migrator, err := NewMigrator(conn, migrations)
+	if err != nil {
+		return err
+	}
+
+	var changed []Migration
+	switch {
+	case downTo >= 0 && dryRun:
+		changed, err = migrator.PlanDown(ctx, downTo)
+	case downTo >= 0:
+		changed, err = migrator.Down(ctx, downTo)
+	case dryRun:
+		changed, err = migrator.Plan(ctx)
+	default:
+		changed, err = migrator.Up(ctx)
+	}
+	for _, m := range changed {
+		fmt.Println(m.String())
+	}
+	return err
+}
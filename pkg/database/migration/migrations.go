@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+// GlobalHubMigrations returns the migrations of the global hub database. The base schema is created by the
+// idempotent sql of the operator, so the migrations must also be applicable on a fresh database. Never modify or
+// renumber a released migration, append a new version instead.
+func GlobalHubMigrations() ([]Migration, error) {
+	migrations, err := LoadSQL(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return append(migrations, Migration{
+		Version: 2,
+		Name:    "transport_position_key",
+		Up:      migrateTransportPositionKey,
+	}), nil
+}
+
+// migrateTransportPositionKey migrates the transport records from the old format (topic) to the new format
+// (topic@partition). It's irreversible since the old format can't keep the positions of multiple partitions.
+func migrateTransportPositionKey(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, "SELECT name, payload FROM status.transport WHERE name NOT LIKE '%@%'")
+	if err != nil {
+		return fmt.Errorf("failed to query the old transport records: %w", err)
+	}
+	type record struct {
+		name    string
+		payload []byte
+	}
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (record, error) {
+		r := record{}
+		err := row.Scan(&r.name, &r.payload)
+		return r, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the old transport records: %w", err)
+	}
+
+	for _, r := range records {
+		var position transport.EventPosition
+		if err := json.Unmarshal(r.payload, &position); err != nil {
+			log.Warnw("failed to unmarshal payload, skipping record", "name", r.name, "error", err)
+			continue
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO status.transport (name, payload, created_at, updated_at)
+			SELECT $1, payload, created_at, now() FROM status.transport WHERE name = $2
+			ON CONFLICT (name) DO UPDATE SET payload = EXCLUDED.payload, updated_at = EXCLUDED.updated_at`,
+			fmt.Sprintf("%s@%d", r.name, position.Partition), r.name); err != nil {
+			return fmt.Errorf("failed to migrate the transport record %s: %w", r.name, err)
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM status.transport WHERE name = $1", r.name); err != nil {
+			return fmt.Errorf("failed to delete the old transport record %s: %w", r.name, err)
+		}
+	}
+	log.Infow("migrated transport records", "count", len(records))
+	return nil
+}
@@ -0,0 +1,4 @@
+ALTER TABLE event.data_retention_job_log DROP COLUMN IF EXISTS archived_partition;
+ALTER TABLE event.data_retention_job_log DROP COLUMN IF EXISTS archive_uri;
+ALTER TABLE event.data_retention_job_log DROP COLUMN IF EXISTS archive_rows;
+ALTER TABLE event.data_retention_job_log DROP COLUMN IF EXISTS archive_checksum;
@@ -0,0 +1,5 @@
+-- the manifest of the archived partition in the data retention job
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archived_partition varchar(254);
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archive_uri TEXT;
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archive_rows bigint;
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archive_checksum varchar(64);
@@ -0,0 +1,24 @@
+DO $$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT schemaname, tablename FROM pg_policies WHERE policyname = 'tenant_isolation'
+    LOOP
+        EXECUTE format('DROP POLICY tenant_isolation ON %I.%I', t.schemaname, t.tablename);
+        EXECUTE format('ALTER TABLE %I.%I DISABLE ROW LEVEL SECURITY', t.schemaname, t.tablename);
+    END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS public.enable_tenant_isolation();
+DROP FUNCTION IF EXISTS public.tenant_leaf_hubs();
+DROP TABLE IF EXISTS public.tenant_hubs;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'global_hub_tenant') THEN
+        DROP OWNED BY global_hub_tenant;
+        DROP ROLE global_hub_tenant;
+    END IF;
+END
+$$;
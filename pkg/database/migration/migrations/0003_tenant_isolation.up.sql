@@ -0,0 +1,62 @@
+-- the tenant roles are members of the global_hub_tenant, their rows are restricted to the leaf hubs of the tenant
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'global_hub_tenant') THEN
+        CREATE ROLE global_hub_tenant NOLOGIN;
+    END IF;
+END
+$$;
+
+-- the leaf hubs of each tenant role, it's maintained by the operator from the GlobalHubTenant
+CREATE TABLE IF NOT EXISTS public.tenant_hubs (
+    role_name name NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    PRIMARY KEY (role_name, leaf_hub_name)
+);
+
+-- the tenant can't read the public.tenant_hubs, so the leaf hubs of the current role are returned by the owner
+CREATE OR REPLACE FUNCTION public.tenant_leaf_hubs()
+RETURNS SETOF character varying
+LANGUAGE sql STABLE SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT leaf_hub_name FROM public.tenant_hubs WHERE role_name = session_user;
+$$;
+REVOKE ALL ON FUNCTION public.tenant_leaf_hubs() FROM PUBLIC;
+GRANT EXECUTE ON FUNCTION public.tenant_leaf_hubs() TO global_hub_tenant;
+
+-- enable the row level security on the tables with the leaf_hub_name, and grant the tenants to read them. The
+-- partitions aren't granted, so the tenants can only read them from the parent tables with the policy. It's
+-- idempotent and invoked by the operator again for the tables created later.
+CREATE OR REPLACE FUNCTION public.enable_tenant_isolation()
+RETURNS void
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN
+        SELECT n.nspname AS schema_name, c.relname AS table_name
+        FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        JOIN pg_attribute a ON a.attrelid = c.oid
+        WHERE a.attname = 'leaf_hub_name' AND NOT a.attisdropped
+            AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+            AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'public')
+    LOOP
+        IF NOT EXISTS (SELECT 1 FROM pg_policies
+            WHERE schemaname = t.schema_name AND tablename = t.table_name AND policyname = 'tenant_isolation') THEN
+            EXECUTE format('CREATE POLICY tenant_isolation ON %I.%I
+                USING (NOT pg_has_role(current_user, ''global_hub_tenant'', ''MEMBER'')
+                    OR leaf_hub_name IN (SELECT public.tenant_leaf_hubs()))
+                WITH CHECK (NOT pg_has_role(current_user, ''global_hub_tenant'', ''MEMBER'')
+                    OR leaf_hub_name IN (SELECT public.tenant_leaf_hubs()))', t.schema_name, t.table_name);
+        END IF;
+        EXECUTE format('ALTER TABLE %I.%I ENABLE ROW LEVEL SECURITY', t.schema_name, t.table_name);
+        EXECUTE format('GRANT USAGE ON SCHEMA %I TO global_hub_tenant', t.schema_name);
+        EXECUTE format('GRANT SELECT ON %I.%I TO global_hub_tenant', t.schema_name, t.table_name);
+    END LOOP;
+END;
+$$;
+
+SELECT public.enable_tenant_isolation();
@@ -0,0 +1,267 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const createMigrationTableSql = `CREATE TABLE IF NOT EXISTS public.schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamp without time zone DEFAULT now() NOT NULL
+)`
+
+// the sql migration files are named as "<version>_<name>.up.sql" and "<version>_<name>.down.sql"
+var sqlFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var log = logger.ZapLogger("database-migration")
+
+// MigrateFunc changes the schema or data in the transaction of the migration
+type MigrateFunc func(ctx context.Context, tx pgx.Tx) error
+
+// Migration is a versioned change of the database, the versions are applied in ascending order
+type Migration struct {
+	Version int64
+	Name    string
+	Up      MigrateFunc
+	// Down reverts the Up, the migration is irreversible if it's nil
+	Down MigrateFunc
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// SQL returns the MigrateFunc which executes the sql statements
+func SQL(statements string) MigrateFunc {
+	return func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, statements)
+		return err
+	}
+}
+
+// LoadSQL reads the sql migrations from the directory of the file system
+func LoadSQL(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migration directory %s: %w", dir, err)
+	}
+	migrations := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := sqlFileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of the migration %s: %w", entry.Name(), err)
+		}
+		sqlBytes, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := migrations[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			migrations[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("the migrations %s and %s have the same version", m.String(), entry.Name())
+		}
+		if matches[3] == "up" {
+			m.Up = SQL(string(sqlBytes))
+		} else {
+			m.Down = SQL(string(sqlBytes))
+		}
+	}
+
+	results := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.Up == nil {
+			return nil, fmt.Errorf("the migration %s doesn't have the up sql", m.String())
+		}
+		results = append(results, *m)
+	}
+	return results, nil
+}
+
+// Migrator applies or reverts the migrations with the advisory lock, and records the applied versions in the
+// public.schema_migrations
+type Migrator struct {
+	conn       *pgx.Conn
+	migrations []Migration
+}
+
+func NewMigrator(conn *pgx.Conn, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := range sorted {
+		if sorted[i].Up == nil {
+			return nil, fmt.Errorf("the migration %s doesn't have the up function", sorted[i].String())
+		}
+		if i > 0 && sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("the migrations %s and %s have the same version", sorted[i-1].String(),
+				sorted[i].String())
+		}
+	}
+	return &Migrator{conn: conn, migrations: sorted}, nil
+}
+
+// Plan returns the pending migrations in the order they will be applied, it doesn't change the database
+func (m *Migrator) Plan(ctx context.Context) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pending(m.migrations, applied), nil
+}
+
+// Version returns the latest applied version, it's 0 if no migration is applied
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version := int64(0)
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Up applies the pending migrations, each one is applied and recorded in a transaction
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if _, err = m.conn.Exec(ctx, createMigrationTableSql); err != nil {
+		return nil, fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+	migrations, err := m.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, migration := range migrations {
+		err = pgx.BeginFunc(ctx, m.conn, func(tx pgx.Tx) error {
+			if err := migration.Up(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, name) VALUES ($1, $2)",
+				migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			return migrations[:i], fmt.Errorf("failed to apply the migration %s: %w", migration.String(), err)
+		}
+		log.Infow("applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	return migrations, nil
+}
+
+// Down reverts the applied migrations which are newer than the target version, in descending order
+func (m *Migrator) Down(ctx context.Context, target int64) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	migrations, err := m.PlanDown(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	for i, migration := range migrations {
+		err = pgx.BeginFunc(ctx, m.conn, func(tx pgx.Tx) error {
+			if err := migration.Down(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "DELETE FROM public.schema_migrations WHERE version = $1", migration.Version)
+			return err
+		})
+		if err != nil {
+			return migrations[:i], fmt.Errorf("failed to revert the migration %s: %w", migration.String(), err)
+		}
+		log.Infow("reverted migration", "version", migration.Version, "name", migration.Name)
+	}
+	return migrations, nil
+}
+
+// PlanDown returns the migrations in the order they will be reverted to the target version, it fails if any of them
+// is irreversible
+func (m *Migrator) PlanDown(ctx context.Context, target int64) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= target || !applied[migration.Version] {
+			continue
+		}
+		if migration.Down == nil {
+			return nil, fmt.Errorf("the migration %s is irreversible", migration.String())
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]bool, error) {
+	applied := map[int64]bool{}
+	exists := false
+	if err := m.conn.QueryRow(ctx, "SELECT to_regclass('public.schema_migrations') IS NOT NULL").
+		Scan(&exists); err != nil || !exists {
+		return applied, err
+	}
+	rows, err := m.conn.Query(ctx, "SELECT version FROM public.schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the applied migrations: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	if _, err := m.conn.Exec(ctx, fmt.Sprintf("SELECT pg_advisory_lock(%s)", constants.MigrationLockId)); err != nil {
+		return nil, fmt.Errorf("failed to lock the database migration: %w", err)
+	}
+	return func() {
+		if _, err := m.conn.Exec(ctx, fmt.Sprintf("SELECT pg_advisory_unlock(%s)", constants.MigrationLockId)); err != nil {
+			log.Errorw("failed to unlock the database migration", "error", err)
+		}
+	}, nil
+}
+
+// pending returns the migrations which aren't applied in ascending order
+func pending(migrations []Migration, applied map[int64]bool) []Migration {
+	var results []Migration
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			results = append(results, migration)
+		}
+	}
+	return results
+}
@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSQL(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_column.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN c int;")},
+		"migrations/0002_add_column.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+		"migrations/0001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id int);")},
+		"migrations/README.md":                {Data: []byte("not a migration")},
+	}
+	migrations, err := LoadSQL(fsys, "migrations")
+	require.NoError(t, err)
+
+	migrator, err := NewMigrator(nil, migrations)
+	require.NoError(t, err)
+	require.Len(t, migrator.migrations, 2)
+	assert.Equal(t, "0001_create_table", migrator.migrations[0].String())
+	assert.Nil(t, migrator.migrations[0].Down, "the migration without down sql is irreversible")
+	assert.Equal(t, "0002_add_column", migrator.migrations[1].String())
+	assert.NotNil(t, migrator.migrations[1].Down)
+
+	// the down sql without the up sql
+	fsys["migrations/0003_drop_table.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE t;")}
+	_, err = LoadSQL(fsys, "migrations")
+	assert.Error(t, err)
+}
+
+func TestNewMigrator(t *testing.T) {
+	up := SQL("SELECT 1")
+	_, err := NewMigrator(nil, []Migration{
+		{Version: 1, Name: "first", Up: up},
+		{Version: 1, Name: "second", Up: up},
+	})
+	assert.Error(t, err, "the versions must be unique")
+
+	_, err = NewMigrator(nil, []Migration{{Version: 1, Name: "first"}})
+	assert.Error(t, err, "the up function is required")
+}
+
+func TestPending(t *testing.T) {
+	up := SQL("SELECT 1")
+	migrations := []Migration{
+		{Version: 1, Name: "first", Up: up},
+		{Version: 2, Name: "second", Up: up},
+		{Version: 3, Name: "third", Up: up},
+	}
+	// the skipped version is still applied, so the upgrades across releases are deterministic
+	results := pending(migrations, map[int64]bool{1: true, 3: true})
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(2), results[0].Version)
+
+	assert.Len(t, pending(migrations, map[int64]bool{}), 3)
+}
+
+func TestGlobalHubMigrations(t *testing.T) {
+	migrations, err := GlobalHubMigrations()
+	require.NoError(t, err)
+	_, err = NewMigrator(nil, migrations)
+	require.NoError(t, err)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditAction is the spec event sent by the manager to the managed hub, the Result is updated by the acknowledgement
+// of the managed hub if the CorrelationID is set
+type AuditAction struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	LeafHubName    string     `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	EventType      string     `gorm:"column:event_type;not null" json:"eventType"`
+	Initiator      string     `gorm:"column:initiator;not null" json:"initiator"`
+	CorrelationID  string     `gorm:"column:correlation_id" json:"correlationId,omitempty"`
+	PayloadHash    string     `gorm:"column:payload_hash;not null" json:"payloadHash"`
+	PayloadSize    int        `gorm:"column:payload_size;not null" json:"payloadSize"`
+	Result         string     `gorm:"column:result;not null" json:"result"`
+	Message        string     `gorm:"column:message" json:"message,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime:true" json:"createdAt"`
+	AcknowledgedAt *time.Time `gorm:"column:acknowledged_at" json:"acknowledgedAt,omitempty"`
+}
+
+func (AuditAction) TableName() string {
+	return "audit.actions"
+}
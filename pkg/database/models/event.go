@@ -45,6 +45,11 @@ type DataRetentionJobLog struct {
 	MaxPartition string    `gorm:"column:max_partition"`
 	MinDeletion  time.Time `gorm:"column:min_deletion"`
 	Error        string    `gorm:"column:error"`
+	// the manifest of the partition archived before it's dropped
+	ArchivedPartition string `gorm:"column:archived_partition"`
+	ArchiveURI        string `gorm:"column:archive_uri"`
+	ArchiveRows       int64  `gorm:"column:archive_rows"`
+	ArchiveChecksum   string `gorm:"column:archive_checksum"`
 }
 
 func (DataRetentionJobLog) TableName() string {
@@ -86,3 +91,27 @@ type ClusterGroupUpgradeEvent struct {
 func (ClusterGroupUpgradeEvent) TableName() string {
 	return "event.clustergroup_upgrades"
 }
+
+// ResourceEvent is the event of any involved object declared by the agent, the object is identified by the GVK columns
+type ResourceEvent struct {
+	EventNamespace      string    `gorm:"column:event_namespace;type:varchar(63);not null" json:"eventNamespace"`
+	EventName           string    `gorm:"column:event_name;type:varchar(253);not null" json:"eventName"`
+	LeafHubName         string    `gorm:"column:leaf_hub_name;type:varchar(256);not null" json:"leafHubName"`
+	Group               string    `gorm:"column:involved_group;type:varchar(253);not null" json:"group"`
+	Version             string    `gorm:"column:involved_version;type:varchar(63);not null" json:"version"`
+	Kind                string    `gorm:"column:involved_kind;type:varchar(63);not null" json:"kind"`
+	ObjectNamespace     string    `gorm:"column:involved_namespace;type:varchar(63)" json:"objectNamespace,omitempty"`
+	ObjectName          string    `gorm:"column:involved_name;type:varchar(253);not null" json:"objectName"`
+	ObjectUID           string    `gorm:"column:involved_uid;type:varchar(63)" json:"objectUid,omitempty"`
+	Message             string    `gorm:"column:message;type:text" json:"message"`
+	Reason              string    `gorm:"column:reason;type:text" json:"reason"`
+	Count               int       `gorm:"column:count;type:integer;not null;default:0" json:"count"`
+	ReportingController string    `gorm:"column:reporting_controller;type:text" json:"reportingController"`
+	ReportingInstance   string    `gorm:"column:reporting_instance;type:text" json:"reportingInstance"`
+	EventType           string    `gorm:"column:event_type;type:varchar(63);not null" json:"type"`
+	CreatedAt           time.Time `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+func (ResourceEvent) TableName() string {
+	return "event.resource_events"
+}
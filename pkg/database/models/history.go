@@ -29,3 +29,23 @@ type LocalComplianceHistory struct {
 func (LocalComplianceHistory) TableName() string {
 	return "history.local_compliance"
 }
+
+// ComplianceScore is the weighted compliance score of a scope on that day, e.g. the cluster, hub or standard
+type ComplianceScore struct {
+	ScoreDate         time.Time `gorm:"type:date;column:score_date"`
+	Scope             string    `gorm:"column:scope"`
+	Name              string    `gorm:"column:name"`
+	LeafHubName       string    `gorm:"column:leaf_hub_name"`
+	Score             *float64  `gorm:"column:score"`
+	WeightedCompliant int       `gorm:"column:weighted_compliant"`
+	WeightedTotal     int       `gorm:"column:weighted_total"`
+	Compliant         int       `gorm:"column:compliant"`
+	NonCompliant      int       `gorm:"column:non_compliant"`
+	Pending           int       `gorm:"column:pending"`
+	Unknown           int       `gorm:"column:unknown"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;default:current_timestamp"`
+}
+
+func (ComplianceScore) TableName() string {
+	return "history.compliance_scores"
+}
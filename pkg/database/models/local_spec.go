@@ -23,3 +23,18 @@ type LocalSpecPolicy struct {
 func (LocalSpecPolicy) TableName() string {
 	return "local_spec.policies"
 }
+
+// LocalPlacement is the placement, the placement decision or the managed cluster set of the managed hub, the
+// Kind distinguishes them
+type LocalPlacement struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ID          string         `gorm:"column:id;primaryKey"`
+	Kind        string         `gorm:"column:kind;not null"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (LocalPlacement) TableName() string {
+	return "local_spec.placements"
+}
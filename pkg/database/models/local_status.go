@@ -1,6 +1,12 @@
 package models
 
-import "github.com/stolostron/multicluster-global-hub/pkg/database"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
 
 type LocalStatusCompliance struct {
 	PolicyID    string                    `gorm:"column:policy_id;primaryKey"`
@@ -14,3 +20,21 @@ type LocalStatusCompliance struct {
 func (LocalStatusCompliance) TableName() string {
 	return "local_status.compliance"
 }
+
+type LocalPolicyTemplateCompliance struct {
+	PolicyID       string                    `gorm:"column:policy_id;primaryKey"`
+	ClusterName    string                    `gorm:"column:cluster_name;primaryKey"`
+	LeafHubName    string                    `gorm:"column:leaf_hub_name;primaryKey"`
+	TemplateName   string                    `gorm:"column:template_name;primaryKey"`
+	TemplateKind   string                    `gorm:"column:template_kind;primaryKey"`
+	ClusterID      string                    `gorm:"column:cluster_id"`
+	Compliance     database.ComplianceStatus `gorm:"column:compliance;not null"`
+	Message        string                    `gorm:"column:message"`
+	RelatedObjects datatypes.JSON            `gorm:"column:related_objects;type:jsonb"`
+	LastTimestamp  time.Time                 `gorm:"column:last_timestamp"`
+	UpdatedAt      time.Time                 `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (LocalPolicyTemplateCompliance) TableName() string {
+	return "local_status.policy_template_compliance"
+}
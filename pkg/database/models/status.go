@@ -24,6 +24,23 @@ func (ManagedCluster) TableName() string {
 	return "status.managed_clusters"
 }
 
+// ManagedClusterConflict is the claim of the managed hub on the cluster which is claimed by several managed hubs,
+// the Payload is the cluster reported by the managed hub, and the ResolvedAt is set once the conflict is resolved
+type ManagedClusterConflict struct {
+	ClusterID   string         `gorm:"column:cluster_id;primaryKey"`
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName string         `gorm:"column:cluster_name;not null"`
+	Resolution  string         `gorm:"column:resolution;not null"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+	ResolvedAt  *time.Time     `gorm:"column:resolved_at"`
+}
+
+func (ManagedClusterConflict) TableName() string {
+	return "status.managed_cluster_conflicts"
+}
+
 type LeafHub struct {
 	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
 	ClusterID   string         `gorm:"column:cluster_id;primaryKey"`
@@ -37,6 +54,75 @@ func (LeafHub) TableName() string {
 	return "status.leaf_hubs"
 }
 
+// ManagedClusterAddOn is the health of the addon on the managed cluster, the Available, Degraded and Progressing are
+// the status of the conditions, and the Message is from the condition of the unhealthy addon
+type ManagedClusterAddOn struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName string         `gorm:"column:cluster_name;primaryKey"`
+	AddonName   string         `gorm:"column:addon_name;primaryKey"`
+	Available   string         `gorm:"column:available"`
+	Degraded    string         `gorm:"column:degraded"`
+	Progressing string         `gorm:"column:progressing"`
+	Message     string         `gorm:"column:message"`
+	Conditions  datatypes.JSON `gorm:"column:conditions;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterAddOn) TableName() string {
+	return "status.managed_cluster_addons"
+}
+
+// Application is the argo cd application, the ClusterName is resolved from the destination of the application
+type Application struct {
+	LeafHubName    string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ID             string         `gorm:"column:id;primaryKey"`
+	ClusterName    string         `gorm:"column:cluster_name;not null"`
+	ApplicationSet string         `gorm:"column:application_set"`
+	Payload        datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (Application) TableName() string {
+	return "status.applications"
+}
+
+type ApplicationSet struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ID          string         `gorm:"column:id;primaryKey"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ApplicationSet) TableName() string {
+	return "status.application_sets"
+}
+
+// ClusterProvisioning is the lifecycle of the cluster provisioned, upgraded or destroyed by the ClusterDeployment,
+// ClusterInstance or ClusterCurator, it's soft deleted to keep the durations once the resource is deleted
+type ClusterProvisioning struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ID          string         `gorm:"column:id;primaryKey"`
+	Kind        string         `gorm:"column:kind;not null"`
+	ClusterName string         `gorm:"column:cluster_name;not null"`
+	Operation   string         `gorm:"column:operation;not null"`
+	Phase       string         `gorm:"column:phase;not null"`
+	Message     string         `gorm:"column:message"`
+	StartedAt   *time.Time     `gorm:"column:started_at"`
+	CompletedAt *time.Time     `gorm:"column:completed_at"`
+	FailedAt    *time.Time     `gorm:"column:failed_at"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (ClusterProvisioning) TableName() string {
+	return "status.cluster_provisioning"
+}
+
 type StatusCompliance struct {
 	PolicyID    string                    `gorm:"column:policy_id;primaryKey"`
 	ClusterName string                    `gorm:"column:cluster_name;primaryKey"`
@@ -55,6 +141,10 @@ type AggregatedCompliance struct {
 	LeafHubName          string `gorm:"column:leaf_hub_name;not null"`
 	AppliedClusters      int    `gorm:"column:applied_clusters;not null"`
 	NonCompliantClusters int    `gorm:"column:non_compliant_clusters;not null"`
+	CompliantClusters    int    `gorm:"column:compliant_clusters;not null"`
+	PendingClusters      int    `gorm:"column:pending_clusters;not null"`
+	UnknownClusters      int    `gorm:"column:unknown_clusters;not null"`
+	RemediationAction    string `gorm:"column:remediation_action"`
 }
 
 func (a AggregatedCompliance) TableName() string {
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const replicaCheckTimeout = 5 * time.Second
+
+// the replay lag of the replica, it's 0 if the replica has replayed all the received WAL, otherwise it's the seconds
+// since the last replayed transaction
+const replicaLagSql = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+var (
+	replicas     []*replica
+	replicaIndex atomic.Uint64
+)
+
+// replica is a read-only connection, the read queries are routed to it only when it's healthy
+type replica struct {
+	host    string
+	gormDB  *gorm.DB
+	sqlDB   *sql.DB
+	healthy atomic.Bool
+}
+
+// initReplicas opens the connections of the read replicas, they're unhealthy until they pass the health check
+func initReplicas(config *DatabaseConfig) error {
+	for _, replicaURL := range config.ReplicaURLs {
+		if replicaURL == "" {
+			continue
+		}
+		gormConn, sqlConn, err := NewGormConn(&DatabaseConfig{
+			URL:        replicaURL,
+			Dialect:    config.Dialect,
+			CaCertPath: config.CaCertPath,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open the replica connection: %w", err)
+		}
+		sqlConn.SetMaxOpenConns(config.PoolSize)
+
+		host := ""
+		if urlObj, err := url.Parse(replicaURL); err == nil {
+			host = urlObj.Host
+		}
+		replicas = append(replicas, &replica{host: host, gormDB: gormConn, sqlDB: sqlConn})
+	}
+	return nil
+}
+
+// GetReadGorm returns the connection of the healthy read replicas in turn for the read-only queries. It fails over
+// to the primary if there isn't any healthy replica. The writes and advisory locks must use the GetGorm and GetConn.
+func GetReadGorm() *gorm.DB {
+	count := len(replicas)
+	if count > 0 {
+		start := replicaIndex.Add(1)
+		for i := 0; i < count; i++ {
+			r := replicas[(start+uint64(i))%uint64(count)]
+			if r.healthy.Load() {
+				return r.gormDB
+			}
+		}
+	}
+	return GetGorm()
+}
+
+// HealthyReplicas returns the number of the replicas which the read queries can be routed to
+func HealthyReplicas() int {
+	healthy := 0
+	for _, r := range replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// StartReplicaHealthCheck probes the replicas periodically until the context is done. A replica is unhealthy if it
+// can't be connected, or its replay lag exceeds the maxLag.
+func StartReplicaHealthCheck(ctx context.Context, interval, maxLag time.Duration) {
+	if len(replicas) == 0 {
+		return
+	}
+	log.Infow("start the replica health check", "replicas", len(replicas), "interval", interval, "maxLag", maxLag)
+	checkReplicas(ctx, maxLag)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkReplicas(ctx, maxLag)
+		}
+	}
+}
+
+func checkReplicas(ctx context.Context, maxLag time.Duration) {
+	for _, r := range replicas {
+		err := checkReplica(ctx, r, maxLag)
+		healthy := err == nil
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Infow("the replica is healthy, route the read queries to it", "replica", r.host)
+			} else {
+				log.Warnw("the replica is unhealthy, fail over the read queries", "replica", r.host, "error", err)
+			}
+		}
+	}
+}
+
+func checkReplica(ctx context.Context, r *replica, maxLag time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lag float64
+	if err := r.sqlDB.QueryRowContext(ctx, replicaLagSql).Scan(&lag); err != nil {
+		return err
+	}
+	if replayLag := time.Duration(lag * float64(time.Second)); replayLag > maxLag {
+		return fmt.Errorf("the replay lag %s exceeds %s", replayLag, maxLag)
+	}
+	return nil
+}
+
+// CloseReplicas closes the connections of the read replicas
+func CloseReplicas() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, r := range replicas {
+		CloseGorm(r.sqlDB)
+	}
+	replicas = nil
+}
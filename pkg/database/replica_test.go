@@ -0,0 +1,39 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetReadGorm(t *testing.T) {
+	primary := &gorm.DB{}
+	first, second := &replica{gormDB: &gorm.DB{}}, &replica{gormDB: &gorm.DB{}}
+
+	originalGormDB, originalReplicas := gormDB, replicas
+	defer func() {
+		gormDB, replicas = originalGormDB, originalReplicas
+	}()
+	gormDB, replicas = primary, []*replica{first, second}
+
+	// the replicas are unhealthy until they pass the health check
+	assert.Same(t, primary, GetReadGorm())
+	assert.Equal(t, 0, HealthyReplicas())
+
+	// round robin between the healthy replicas
+	first.healthy.Store(true)
+	second.healthy.Store(true)
+	routed := map[*gorm.DB]int{}
+	for i := 0; i < 4; i++ {
+		routed[GetReadGorm()]++
+	}
+	assert.Equal(t, map[*gorm.DB]int{first.gormDB: 2, second.gormDB: 2}, routed)
+
+	// fail over to the other healthy replica, then the primary
+	first.healthy.Store(false)
+	assert.Same(t, second.gormDB, GetReadGorm())
+	assert.Same(t, second.gormDB, GetReadGorm())
+	second.healthy.Store(false)
+	assert.Same(t, primary, GetReadGorm())
+}
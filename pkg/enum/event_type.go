@@ -12,11 +12,17 @@ const (
 	ManagedClusterMigrationType EventType = EventTypePrefix + "managedclustermigration"
 	ManagedClusterType          EventType = EventTypePrefix + "managedcluster"
 	ManagedClusterInfoType      EventType = EventTypePrefix + "managedclusterinfo"
+	ManagedClusterAddOnType     EventType = EventTypePrefix + "managedclusteraddon"
+	ResyncAckType               EventType = EventTypePrefix + "resync.ack"
+	AntiEntropyDigestType       EventType = EventTypePrefix + "antientropy.digest"
+	SpecAckType                 EventType = EventTypePrefix + "spec.ack"
+	PolicyRemediationResultType EventType = EventTypePrefix + "remediation.result"
 
 	// used by the local resources
 	LocalComplianceType         EventType = EventTypePrefix + "policy.localcompliance"
 	LocalCompleteComplianceType EventType = EventTypePrefix + "policy.localcompletecompliance"
 	LocalPolicySpecType         EventType = EventTypePrefix + "policy.localspec"
+	LocalTemplateComplianceType EventType = EventTypePrefix + "policy.localtemplatecompliance"
 
 	// used by the global resources
 	ComplianceType         EventType = EventTypePrefix + "policy.compliance"
@@ -30,9 +36,26 @@ const (
 
 	ManagedClusterEventType       EventType = EventTypePrefix + "event.managedcluster"
 	ClusterGroupUpgradesEventType EventType = EventTypePrefix + "event.clustergroupupgrade"
+	// the events of the involved objects declared by the resourceEvents in the agent configmap
+	ResourceEventType EventType = EventTypePrefix + "event.resource"
 
 	// Used to send security alerts:
 	SecurityAlertCountsType EventType = EventTypePrefix + "security.alertcounts"
+
+	// used to send the argo cd application delivery status
+	ApplicationType    EventType = EventTypePrefix + "application.application"
+	ApplicationSetType EventType = EventTypePrefix + "application.applicationset"
+
+	// used to send the placements and the cluster sets of the managed hub
+	LocalPlacementType           EventType = EventTypePrefix + "placement.localplacement"
+	LocalPlacementDecisionType   EventType = EventTypePrefix + "placement.localplacementdecision"
+	ManagedClusterSetType        EventType = EventTypePrefix + "placement.managedclusterset"
+	ManagedClusterSetBindingType EventType = EventTypePrefix + "placement.managedclustersetbinding"
+
+	// used to send the provisioning lifecycle of the clusters before they become managed clusters
+	ClusterDeploymentType EventType = EventTypePrefix + "provisioning.clusterdeployment"
+	ClusterInstanceType   EventType = EventTypePrefix + "provisioning.clusterinstance"
+	ClusterCuratorType    EventType = EventTypePrefix + "provisioning.clustercurator"
 )
 
 func ShortenEventType(eventType string) string {
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const instrumentationName = "github.com/stolostron/multicluster-global-hub"
+
+// the extensions of the CloudEvents distributed tracing extension, which carry the W3C trace context of the events
+const (
+	TraceParentExtension = "traceparent"
+	TraceStateExtension  = "tracestate"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Config specifies the OTLP exporter of the traces, the tracing is disabled if the endpoint is empty
+type Config struct {
+	// Endpoint is the OTLP gRPC endpoint of the collector, e.g. otel-collector.observability.svc:4317
+	Endpoint string
+	// Insecure disables the TLS of the connection to the collector
+	Insecure bool
+	// SamplingPercentage is the percentage of the traces sampled, the events of a sampled trace are all recorded
+	SamplingPercentage int
+}
+
+// Init sets the global tracer provider to export the spans to the OTLP endpoint, and returns the function to flush
+// and stop the exporter. It's a no-op if the endpoint isn't set, the trace context is still propagated.
+func Init(ctx context.Context, serviceName string, config *Config) (func(context.Context) error, error) {
+	if config == nil || config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.SamplingPercentage < 0 || config.SamplingPercentage > 100 {
+		return nil, fmt.Errorf("the sampling percentage %d should be in the scope [0, 100]", config.SamplingPercentage)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(float64(config.SamplingPercentage)/100))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	logger.DefaultZapLogger().Infow("the traces are exported", "endpoint", config.Endpoint,
+		"samplingPercentage", config.SamplingPercentage)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the global hub from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject sets the trace context of the span in the context into the extensions of the event
+func Inject(ctx context.Context, evt *cloudevents.Event) {
+	propagator.Inject(ctx, &eventCarrier{evt: evt})
+}
+
+// Extract returns the context with the remote span context carried by the extensions of the event
+func Extract(ctx context.Context, evt *cloudevents.Event) context.Context {
+	return propagator.Extract(ctx, &eventCarrier{evt: evt})
+}
+
+// StartEventSpan starts the span of a stage which the event passes through, it's the child of the span carried by the
+// event, and the event carries the new span afterward, so the spans of the following stages are its children.
+func StartEventSpan(ctx context.Context, evt *cloudevents.Event, name string, kind trace.SpanKind,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("cloudevents.event_type", enum.ShortenEventType(evt.Type())),
+		attribute.String("cloudevents.event_source", evt.Source()),
+		attribute.String("cloudevents.event_id", evt.ID()),
+	)
+	ctx, span := Tracer().Start(Extract(ctx, evt), name+" "+enum.ShortenEventType(evt.Type()),
+		trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	Inject(ctx, evt)
+	return ctx, span
+}
+
+// EndSpan ends the span with the error status if the error isn't nil
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// eventCarrier adapts the extensions of the event to the propagation.TextMapCarrier
+type eventCarrier struct {
+	evt *cloudevents.Event
+}
+
+func (c *eventCarrier) Get(key string) string {
+	value, ok := c.evt.Extensions()[key]
+	if !ok {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func (c *eventCarrier) Set(key, value string) {
+	c.evt.SetExtension(key, value)
+}
+
+func (c *eventCarrier) Keys() []string {
+	keys := []string{}
+	for _, key := range []string{TraceParentExtension, TraceStateExtension} {
+		if _, ok := c.evt.Extensions()[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
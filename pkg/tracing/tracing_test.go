@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEventSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	evt := cloudevents.NewEvent()
+	evt.SetID("1")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.policy.localspec")
+	evt.SetSource("hub1")
+
+	// the agent sends the event
+	_, emitSpan := StartEventSpan(context.Background(), &evt, "emit", trace.SpanKindInternal)
+	_, sendSpan := StartEventSpan(context.Background(), &evt, "send", trace.SpanKindProducer)
+	sendSpan.End()
+	emitSpan.End()
+	assert.NotEmpty(t, evt.Extensions()[TraceParentExtension])
+
+	// the manager receives and handles the event carried by the transport
+	received := evt.Clone()
+	_, receiveSpan := StartEventSpan(context.Background(), &received, "receive", trace.SpanKindConsumer)
+	receiveSpan.End()
+	_, handleSpan := StartEventSpan(context.Background(), &received, "process", trace.SpanKindInternal)
+	EndSpan(handleSpan, errors.New("database is unavailable"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		assert.Equal(t, emitSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+		byName[span.Name()] = span
+	}
+	assert.False(t, byName["emit policy.localspec"].Parent().IsValid())
+	assert.Equal(t, byName["emit policy.localspec"].SpanContext().SpanID(),
+		byName["send policy.localspec"].Parent().SpanID())
+	assert.Equal(t, byName["send policy.localspec"].SpanContext().SpanID(),
+		byName["receive policy.localspec"].Parent().SpanID())
+	assert.True(t, byName["receive policy.localspec"].Parent().IsRemote())
+	assert.Equal(t, byName["receive policy.localspec"].SpanContext().SpanID(),
+		byName["process policy.localspec"].Parent().SpanID())
+	assert.Equal(t, codes.Error, byName["process policy.localspec"].Status().Code)
+}
+
+func TestInitWithoutEndpoint(t *testing.T) {
+	shutdown, err := Init(context.Background(), "test", &Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Init(context.Background(), "test", &Config{Endpoint: "localhost:4317", SamplingPercentage: 101})
+	require.Error(t, err)
+}
@@ -18,6 +18,8 @@ import (
 	ceprotocol "github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -25,6 +27,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
@@ -223,14 +226,14 @@ func (c *GenericConsumer) receive(client cloudevents.Client, ctx context.Context
 
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
-			c.eventChan <- &event
+			c.deliver(ctx, &event)
 			return ceprotocol.ResultACK
 		}
 		if payload := c.assembler.assemble(chunk); payload != nil {
 			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
 				log.Errorw("failed the set the assembled data to event", "error", err)
 			} else {
-				c.eventChan <- &event
+				c.deliver(ctx, &event)
 			}
 		}
 		return ceprotocol.ResultACK
@@ -242,6 +245,17 @@ func (c *GenericConsumer) receive(client cloudevents.Client, ctx context.Context
 	return nil
 }
 
+// deliver sends the received event to the event channel within the consumer span, which continues the trace of the
+// producer and is carried by the event to the following handlers
+func (c *GenericConsumer) deliver(ctx context.Context, event *cloudevents.Event) {
+	_, span := tracing.StartEventSpan(ctx, event, "receive", trace.SpanKindConsumer,
+		attribute.String("messaging.destination.name", fmt.Sprintf("%v", event.Extensions()[kafka_confluent.KafkaTopicKey])),
+		attribute.String("messaging.kafka.partition", fmt.Sprintf("%v", event.Extensions()[kafka_confluent.KafkaPartitionKey])),
+		attribute.String("messaging.kafka.offset", fmt.Sprintf("%v", event.Extensions()[kafka_confluent.KafkaOffsetKey])))
+	defer span.End()
+	c.eventChan <- event
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
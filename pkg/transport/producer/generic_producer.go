@@ -14,9 +14,13 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
@@ -29,6 +33,11 @@ type GenericProducer struct {
 	kafkaProducer     *kafka.Producer
 	messageSizeLimit  int
 	eventErrorHandler func(event *kafka.Message)
+	// outbox persists the undeliverable events, it's nil if the outbox isn't enabled
+	outbox *Outbox
+	// inflight tracks the events until they're delivered, it's only used with the outbox and the delivery reports
+	inflight        *inflightEvents
+	deliveryReports bool
 }
 
 func NewGenericProducer(transportConfig *transport.TransportInternalConfig, topic string,
@@ -39,11 +48,30 @@ func NewGenericProducer(transportConfig *transport.TransportInternalConfig, topi
 		messageSizeLimit:  config.MaxSizeToChunk,
 		eventErrorHandler: eventErrorHandler,
 	}
+	if transportConfig.Outbox != nil && transportConfig.Outbox.Path != "" {
+		outbox, err := OpenOutbox(transportConfig.Outbox)
+		if err != nil {
+			return nil, err
+		}
+		genericProducer.outbox = outbox
+		genericProducer.inflight = newInflightEvents()
+		// the messages failed to deliver after the retries of the kafka producer are persisted into the outbox
+		genericProducer.eventErrorHandler = func(msg *kafka.Message) {
+			if eventErrorHandler != nil {
+				eventErrorHandler(msg)
+			}
+			genericProducer.persistUndelivered(msg)
+		}
+	}
+
 	err := genericProducer.initClient(transportConfig, topic)
 	if err != nil {
 		return nil, err
 	}
 
+	if genericProducer.outbox != nil {
+		go genericProducer.outbox.run(genericProducer.sendTracked, genericProducer.reachable)
+	}
 	return genericProducer, nil
 }
 
@@ -55,7 +83,38 @@ func (p *GenericProducer) Protocol() *kafka_confluent.Protocol {
 	return p.ceProtocol.(*kafka_confluent.Protocol)
 }
 
-func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+// SendEvent sends the event to the transport. If the outbox is enabled, the undeliverable event is persisted into the
+// outbox instead of returning the error, and the events are queued behind the persisted ones until it's drained. The
+// order is best-effort across a delivery failure: the kafka producer sends asynchronously, so the events handed to it
+// before the failure is reported may be delivered ahead of the persisted event.
+// The trace context of the producer span is carried by the extensions of the event.
+func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) (err error) {
+	ctx, span := tracing.StartEventSpan(ctx, &evt, "send", trace.SpanKindProducer,
+		attribute.String("messaging.destination.name", cectx.TopicFrom(ctx)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if p.outbox == nil {
+		return p.send(ctx, evt)
+	}
+
+	if p.outbox.Len() > 0 {
+		span.AddEvent("queued behind the outbox")
+		return p.outbox.Enqueue(ctx, evt)
+	}
+	err = p.sendTracked(ctx, evt)
+	if err == nil {
+		return nil
+	}
+	span.AddEvent("persisted into the outbox", trace.WithAttributes(attribute.String("error", err.Error())))
+	if outboxErr := p.outbox.Enqueue(ctx, evt); outboxErr != nil {
+		return fmt.Errorf("%w, and failed to persist it into the outbox: %v", err, outboxErr)
+	}
+	p.log.Warnw("the undeliverable event is persisted into the outbox", "type", evt.Type(), "id", evt.ID(),
+		"error", err)
+	return nil
+}
+
+func (p *GenericProducer) send(ctx context.Context, evt cloudevents.Event) error {
 	// cloudevent kafka/gochan client
 	// message key
 	evtCtx := cectx.WithLogger(ctx, logger.ZapLogger("cloudevents"))
@@ -88,6 +147,69 @@ func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event)
 	return nil
 }
 
+// sendTracked sends the event and tracks it until all its chunks are delivered, so the whole event is persisted if
+// any of the chunks fails to deliver
+func (p *GenericProducer) sendTracked(ctx context.Context, evt cloudevents.Event) error {
+	if !p.deliveryReports {
+		// the chunks are set to the extensions and data of the event, so the sent one is cloned
+		return p.send(ctx, evt.Clone())
+	}
+	if evt.ID() == "" {
+		evt.SetID(uuid.New().String())
+	}
+	p.inflight.add(ctx, evt, max(len(p.splitPayloadIntoChunks(evt.Data())), 1))
+	err := p.send(ctx, evt.Clone())
+	if err != nil {
+		p.inflight.remove(evt.ID())
+	}
+	return err
+}
+
+// persistUndelivered persists the event of the kafka message which is failed to deliver into the outbox. The event is
+// persisted once as a whole, though several chunks of it fail, and the messages of the untracked events are dropped.
+func (p *GenericProducer) persistUndelivered(msg *kafka.Message) {
+	id := messageEventID(msg)
+	record := p.inflight.remove(id)
+	if record == nil {
+		p.log.Debugw("the undelivered message isn't tracked, it has been persisted or isn't from the producer",
+			"id", id)
+		return
+	}
+	record.EnqueuedAt = time.Now()
+	if err := p.outbox.enqueue(*record); err != nil {
+		p.log.Warnw("failed to persist the undelivered event into the outbox", "id", id, "error", err)
+	}
+}
+
+// delivered stops tracking the event once all its chunks are delivered
+func (p *GenericProducer) delivered(msg *kafka.Message) {
+	if p.inflight != nil {
+		p.inflight.delivered(messageEventID(msg))
+	}
+}
+
+// messageEventID returns the id of the event carried by the kafka message in the binary mode
+func messageEventID(msg *kafka.Message) string {
+	for _, header := range msg.Headers {
+		if header.Key == "ce_id" {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// reachable returns whether the brokers can be connected to replay the outbox
+func (p *GenericProducer) reachable() bool {
+	if p.kafkaProducer == nil {
+		return true
+	}
+	if _, err := p.kafkaProducer.GetMetadata(nil, false, 5000); err != nil {
+		p.log.Debugw("the transport isn't reachable to replay the outbox", "error", err)
+		return false
+	}
+	return true
+}
+
 // Reconnect close the previous producer state and init a new producer
 func (p *GenericProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
 	// cloudevent kafka/gochan client
@@ -113,9 +235,10 @@ func (p *GenericProducer) initClient(transportConfig *transport.TransportInterna
 		if err != nil {
 			return err
 		}
-		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, p.eventErrorHandler)
+		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, p.eventErrorHandler, p.delivered)
 		p.ceProtocol = kafkaProtocol
 		p.kafkaProducer = producer
+		p.deliveryReports = p.outbox != nil
 	case string(transport.Chan):
 		if transportConfig.Extends == nil {
 			transportConfig.Extends = make(map[string]interface{})
@@ -179,7 +302,7 @@ func getConfluentSenderProtocol(logger *zap.SugaredLogger, kafkaCredentail *tran
 }
 
 func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int,
-	eventErrorHandler func(event *kafka.Message),
+	eventErrorHandler func(event *kafka.Message), eventDeliveredHandler func(event *kafka.Message),
 ) {
 	// Listen to all the events on the default events channel
 	// It's important to read these events otherwise the events channel will eventually fill up
@@ -199,6 +322,8 @@ func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, tr
 						eventErrorHandler(m)
 					}
 					log.Warnw("delivery failed", "error", m.TopicPartition.Error)
+				} else if eventDeliveredHandler != nil {
+					eventDeliveredHandler(m)
 				}
 			case kafka.Error:
 				// Generic client instance-level errors, such as
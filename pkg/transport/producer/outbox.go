@@ -0,0 +1,397 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	DefaultOutboxMaxSize        = 100 * 1024 * 1024
+	DefaultOutboxMaxAge         = 24 * time.Hour
+	DefaultOutboxReplayInterval = 10 * time.Second
+
+	// the events are replayed in batches, so the outbox isn't locked while they're sent
+	outboxReplayBatchSize = 100
+)
+
+// the reasons of the events dropped by the outbox, they're the label values of the OutboxDroppedCounter
+const (
+	OutboxDropExpired  = "expired"
+	OutboxDropOverflow = "overflow"
+	OutboxDropInvalid  = "invalid"
+)
+
+var (
+	outboxBucket = []byte("events")
+
+	OutboxEventsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_events",
+		Help: "The number of the undeliverable events persisted in the transport outbox.",
+	})
+	OutboxBytesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_bytes",
+		Help: "The bytes of the undeliverable events persisted in the transport outbox.",
+	})
+	OutboxReplayedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_outbox_replayed_total",
+		Help: "The number of the events replayed from the transport outbox.",
+	})
+	OutboxDroppedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_outbox_dropped_total",
+		Help: "The number of the events dropped by the transport outbox, by the size and age limits.",
+	}, []string{"reason"})
+
+	// OutboxMetrics are the metrics of the outbox, they're registered by the component which enables the outbox
+	OutboxMetrics = []prometheus.Collector{
+		OutboxEventsGauge, OutboxBytesGauge, OutboxReplayedCounter, OutboxDroppedCounter,
+	}
+)
+
+// outboxRecord is the persisted event with the topic and message key it's sent with
+type outboxRecord struct {
+	Topic      string            `json:"topic,omitempty"`
+	MessageKey string            `json:"messageKey,omitempty"`
+	EnqueuedAt time.Time         `json:"enqueuedAt"`
+	Event      cloudevents.Event `json:"event"`
+}
+
+// inflightEvent is the event handed to the kafka producer, it's persisted as a whole if any of its chunks fails
+type inflightEvent struct {
+	record    outboxRecord
+	remaining int
+}
+
+// inflightEvents tracks the events by the id until all their chunks are delivered, so the undelivered event is
+// persisted before it's chunked, and the consumer can assemble it after the replay
+type inflightEvents struct {
+	mu     sync.Mutex
+	events map[string]*inflightEvent
+}
+
+func newInflightEvents() *inflightEvents {
+	return &inflightEvents{events: map[string]*inflightEvent{}}
+}
+
+func (i *inflightEvents) add(ctx context.Context, evt cloudevents.Event, chunks int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.events[evt.ID()] = &inflightEvent{
+		record: outboxRecord{
+			Topic:      cecontext.TopicFrom(ctx),
+			MessageKey: kafka_confluent.MessageKeyFrom(ctx),
+			Event:      evt,
+		},
+		remaining: chunks,
+	}
+}
+
+// delivered removes the event once all its chunks are delivered
+func (i *inflightEvents) delivered(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if evt, ok := i.events[id]; ok {
+		evt.remaining--
+		if evt.remaining <= 0 {
+			delete(i.events, id)
+		}
+	}
+}
+
+// remove returns the event and stops tracking it, it returns nil if the event has been removed, e.g. by the failure
+// of its another chunk
+func (i *inflightEvents) remove(id string) *outboxRecord {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	evt, ok := i.events[id]
+	if !ok {
+		return nil
+	}
+	delete(i.events, id)
+	return &evt.record
+}
+
+// Outbox is the bounded on-disk queue of the events which can't be delivered to the transport. The events are kept in
+// the order they're enqueued, and they're replayed in the order once the transport is reachable again.
+type Outbox struct {
+	log    *zap.SugaredLogger
+	db     *bolt.DB
+	config transport.OutboxConfig
+
+	mu    sync.Mutex // protects the count and size
+	count int
+	size  int64
+
+	replayMu sync.Mutex // only one replay at a time
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// OpenOutbox opens the outbox file, the events persisted before the restart are loaded to be replayed
+func OpenOutbox(config *transport.OutboxConfig) (*Outbox, error) {
+	o := &Outbox{
+		log:    logger.ZapLogger("transport-outbox"),
+		config: *config,
+		stopCh: make(chan struct{}),
+	}
+	if o.config.MaxSize <= 0 {
+		o.config.MaxSize = DefaultOutboxMaxSize
+	}
+	if o.config.MaxAge <= 0 {
+		o.config.MaxAge = DefaultOutboxMaxAge
+	}
+	if o.config.ReplayInterval <= 0 {
+		o.config.ReplayInterval = DefaultOutboxReplayInterval
+	}
+
+	db, err := bolt.Open(o.config.Path, 0o600, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the outbox %s: %w", o.config.Path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(outboxBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			o.count++
+			o.size += int64(len(v))
+			return nil
+		})
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load the outbox %s: %w", o.config.Path, err)
+	}
+	o.db = db
+	o.updateMetrics()
+	if o.count > 0 {
+		o.log.Infow("loaded the undeliverable events from the outbox", "events", o.count, "bytes", o.size)
+	}
+	return o, nil
+}
+
+// Len returns the number of the persisted events
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.count
+}
+
+// Enqueue persists the event with the topic and message key of the context. The oldest events are dropped if the
+// size limit is exceeded.
+func (o *Outbox) Enqueue(ctx context.Context, evt cloudevents.Event) error {
+	return o.enqueue(outboxRecord{
+		Topic:      cecontext.TopicFrom(ctx),
+		MessageKey: kafka_confluent.MessageKeyFrom(ctx),
+		EnqueuedAt: time.Now(),
+		Event:      evt,
+	})
+}
+
+func (o *Outbox) enqueue(record outboxRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the event %s: %w", record.Event.ID(), err)
+	}
+	if int64(len(value)) > o.config.MaxSize {
+		OutboxDroppedCounter.WithLabelValues(OutboxDropOverflow).Inc()
+		return fmt.Errorf("the event %s(%d bytes) exceeds the outbox size %d", record.Event.ID(), len(value),
+			o.config.MaxSize)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	dropped, droppedSize := 0, int64(0)
+	err = o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		// drop the oldest events to keep the outbox within the size limit
+		cursor := bucket.Cursor()
+		for o.size-droppedSize+int64(len(value)) > o.config.MaxSize {
+			k, v := cursor.First()
+			if k == nil {
+				break
+			}
+			droppedSize += int64(len(v))
+			dropped++
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+		}
+		return bucket.Put(outboxKey(seq), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist the event %s into the outbox: %w", record.Event.ID(), err)
+	}
+	o.count += 1 - dropped
+	o.size += int64(len(value)) - droppedSize
+	if dropped > 0 {
+		OutboxDroppedCounter.WithLabelValues(OutboxDropOverflow).Add(float64(dropped))
+		o.log.Warnw("dropped the oldest events since the outbox is full", "dropped", dropped,
+			"maxSize", o.config.MaxSize)
+	}
+	o.updateMetrics()
+	return nil
+}
+
+// Replay sends the persisted events in order, the sent and expired events are removed. It stops at the first event
+// failed to send, so the event and the following ones are replayed next time.
+func (o *Outbox) Replay(send func(context.Context, cloudevents.Event) error) (int, error) {
+	o.replayMu.Lock()
+	defer o.replayMu.Unlock()
+
+	replayed := 0
+	var after []byte
+	for {
+		keys, values, err := o.batch(after)
+		if err != nil {
+			return replayed, err
+		}
+		if len(keys) == 0 {
+			return replayed, nil
+		}
+
+		for i, key := range keys {
+			after = key
+			record := outboxRecord{}
+			if err := json.Unmarshal(values[i], &record); err != nil {
+				o.log.Warnw("dropped the invalid event in the outbox", "error", err)
+				o.remove(key, OutboxDropInvalid)
+				continue
+			}
+			if time.Since(record.EnqueuedAt) > o.config.MaxAge {
+				o.log.Debugw("dropped the expired event in the outbox", "id", record.Event.ID(),
+					"enqueuedAt", record.EnqueuedAt)
+				o.remove(key, OutboxDropExpired)
+				continue
+			}
+
+			ctx := context.Background()
+			if record.Topic != "" {
+				ctx = cecontext.WithTopic(ctx, record.Topic)
+			}
+			if record.MessageKey != "" {
+				ctx = kafka_confluent.WithMessageKey(ctx, record.MessageKey)
+			}
+			if err := send(ctx, record.Event); err != nil {
+				return replayed, fmt.Errorf("failed to replay the event %s: %w", record.Event.ID(), err)
+			}
+			o.remove(key, "")
+			replayed++
+			OutboxReplayedCounter.Inc()
+		}
+	}
+}
+
+// batch reads the events after the key
+func (o *Outbox) batch(after []byte) ([][]byte, [][]byte, error) {
+	keys, values := [][]byte{}, [][]byte{}
+	err := o.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(outboxBucket).Cursor()
+		k, v := cursor.First()
+		if after != nil {
+			k, v = cursor.Seek(after)
+			if bytes.Equal(k, after) {
+				k, v = cursor.Next()
+			}
+		}
+		for ; k != nil && len(keys) < outboxReplayBatchSize; k, v = cursor.Next() {
+			// the slices are only valid in the transaction
+			keys = append(keys, append([]byte{}, k...))
+			values = append(values, append([]byte{}, v...))
+		}
+		return nil
+	})
+	return keys, values, err
+}
+
+// remove deletes the event, it's ignored if the event has been dropped by the size limit
+func (o *Outbox) remove(key []byte, dropReason string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	removedSize := -1
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		value := bucket.Get(key)
+		if value == nil {
+			return nil
+		}
+		removedSize = len(value)
+		return bucket.Delete(key)
+	})
+	if err != nil {
+		o.log.Warnw("failed to remove the event from the outbox", "error", err)
+		return
+	}
+	if removedSize < 0 {
+		return
+	}
+	o.count--
+	o.size -= int64(removedSize)
+	if dropReason != "" {
+		OutboxDroppedCounter.WithLabelValues(dropReason).Inc()
+	}
+	o.updateMetrics()
+}
+
+// run replays the persisted events periodically once the transport is reachable, until the outbox is closed
+func (o *Outbox) run(send func(context.Context, cloudevents.Event) error, reachable func() bool) {
+	ticker := time.NewTicker(o.config.ReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.stopCh:
+			return
+		case <-ticker.C:
+			if o.Len() == 0 || !reachable() {
+				continue
+			}
+			replayed, err := o.Replay(send)
+			if err != nil {
+				o.log.Warnw("failed to replay the outbox, retry later", "replayed", replayed, "pending", o.Len(),
+					"error", err)
+				continue
+			}
+			o.log.Infow("replayed the undeliverable events from the outbox", "replayed", replayed)
+		}
+	}
+}
+
+// Close stops the replay and closes the outbox file
+func (o *Outbox) Close() error {
+	o.stopOnce.Do(func() { close(o.stopCh) })
+	return o.db.Close()
+}
+
+func (o *Outbox) updateMetrics() {
+	OutboxEventsGauge.Set(float64(o.count))
+	OutboxBytesGauge.Set(float64(o.size))
+}
+
+// outboxKey encodes the sequence in big endian, so the events are iterated in the order they're enqueued
+func outboxKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newOutboxEvent(id string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetType("test.type")
+	evt.SetSource("hub1")
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"id": id})
+	return evt
+}
+
+// metricValue returns the value of the counter or gauge
+func metricValue(t *testing.T, metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, metric.Write(m))
+	if m.Counter != nil {
+		return m.Counter.GetValue()
+	}
+	return m.Gauge.GetValue()
+}
+
+func TestOutbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	outbox, err := OpenOutbox(&transport.OutboxConfig{Path: path})
+	require.NoError(t, err)
+
+	ctx := kafka_confluent.WithMessageKey(cecontext.WithTopic(context.Background(), "status.hub1"), "hub1")
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, outbox.Enqueue(ctx, newOutboxEvent(fmt.Sprintf("event%d", i))))
+	}
+	assert.Equal(t, 3, outbox.Len())
+	assert.Equal(t, 3.0, metricValue(t, OutboxEventsGauge))
+
+	// the persisted events are loaded after the restart
+	require.NoError(t, outbox.Close())
+	outbox, err = OpenOutbox(&transport.OutboxConfig{Path: path})
+	require.NoError(t, err)
+	defer func() { _ = outbox.Close() }()
+	assert.Equal(t, 3, outbox.Len())
+
+	// the replay stops at the first event failed to send
+	sent := []string{}
+	replayed, err := outbox.Replay(func(ctx context.Context, evt cloudevents.Event) error {
+		if evt.ID() == "event2" {
+			return errors.New("all brokers are down")
+		}
+		assert.Equal(t, "status.hub1", cecontext.TopicFrom(ctx))
+		assert.Equal(t, "hub1", kafka_confluent.MessageKeyFrom(ctx))
+		sent = append(sent, evt.ID())
+		return nil
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Equal(t, 2, outbox.Len())
+
+	// the remaining events are replayed in order
+	replayed, err = outbox.Replay(func(ctx context.Context, evt cloudevents.Event) error {
+		sent = append(sent, evt.ID())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed)
+	assert.Equal(t, []string{"event1", "event2", "event3"}, sent)
+	assert.Equal(t, 0, outbox.Len())
+	assert.Equal(t, 0.0, metricValue(t, OutboxBytesGauge))
+}
+
+func TestOutboxLimits(t *testing.T) {
+	value, err := newOutboxEvent("event0").MarshalJSON()
+	require.NoError(t, err)
+	// the outbox holds 2 events at most
+	outbox, err := OpenOutbox(&transport.OutboxConfig{
+		Path:    filepath.Join(t.TempDir(), "outbox.db"),
+		MaxSize: int64(3 * len(value)),
+		MaxAge:  time.Minute,
+	})
+	require.NoError(t, err)
+	defer func() { _ = outbox.Close() }()
+
+	overflow := metricValue(t, OutboxDroppedCounter.WithLabelValues(OutboxDropOverflow))
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, outbox.Enqueue(context.Background(), newOutboxEvent(fmt.Sprintf("event%d", i))))
+	}
+	assert.Equal(t, 2, outbox.Len())
+	assert.Equal(t, overflow+1, metricValue(t, OutboxDroppedCounter.WithLabelValues(OutboxDropOverflow)))
+
+	// the event larger than the outbox is rejected
+	large := newOutboxEvent("large")
+	require.NoError(t, large.SetData(cloudevents.ApplicationJSON, make([]byte, 3*len(value))))
+	require.Error(t, outbox.Enqueue(context.Background(), large))
+
+	// the expired event isn't replayed
+	require.NoError(t, outbox.enqueue(outboxRecord{
+		EnqueuedAt: time.Now().Add(-2 * time.Minute),
+		Event:      newOutboxEvent("event4"),
+	}))
+	expired := metricValue(t, OutboxDroppedCounter.WithLabelValues(OutboxDropExpired))
+	sent := []string{}
+	_, err = outbox.Replay(func(ctx context.Context, evt cloudevents.Event) error {
+		sent = append(sent, evt.ID())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event3"}, sent)
+	assert.Equal(t, expired+1, metricValue(t, OutboxDroppedCounter.WithLabelValues(OutboxDropExpired)))
+	assert.Equal(t, 0, outbox.Len())
+}
+
+// fakeClient fails to send the events until it's reachable
+type fakeClient struct {
+	cloudevents.Client
+	reachable bool
+	sent      []string
+}
+
+func (c *fakeClient) Send(ctx context.Context, evt cloudevents.Event) protocol.Result {
+	if !c.reachable {
+		return errors.New("all brokers are down")
+	}
+	c.sent = append(c.sent, evt.ID())
+	return nil
+}
+
+func TestGenericProducerOutbox(t *testing.T) {
+	outbox, err := OpenOutbox(&transport.OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.db")})
+	require.NoError(t, err)
+	defer func() { _ = outbox.Close() }()
+
+	client := &fakeClient{}
+	p := &GenericProducer{
+		log:              logger.DefaultZapLogger(),
+		ceClient:         client,
+		messageSizeLimit: 1024,
+		outbox:           outbox,
+	}
+
+	// the undeliverable event is persisted, and the following events are queued behind it
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent("event1")))
+	client.reachable = true
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent("event2")))
+	assert.Empty(t, client.sent)
+	assert.Equal(t, 2, outbox.Len())
+
+	_, err = outbox.Replay(p.sendTracked)
+	require.NoError(t, err)
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent("event3")))
+	assert.Equal(t, []string{"event1", "event2", "event3"}, client.sent)
+}
+
+func deliveryReport(id string, err error) *kafka.Message {
+	return &kafka.Message{
+		Headers:        []kafka.Header{{Key: "ce_id", Value: []byte(id)}},
+		TopicPartition: kafka.TopicPartition{Error: err},
+	}
+}
+
+func TestGenericProducerOutboxChunks(t *testing.T) {
+	outbox, err := OpenOutbox(&transport.OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.db")})
+	require.NoError(t, err)
+	defer func() { _ = outbox.Close() }()
+
+	client := &fakeClient{reachable: true}
+	p := &GenericProducer{
+		log:              logger.DefaultZapLogger(),
+		ceClient:         client,
+		messageSizeLimit: 8,
+		outbox:           outbox,
+		inflight:         newInflightEvents(),
+		deliveryReports:  true,
+	}
+	ctx := cecontext.WithTopic(context.Background(), "status.hub1")
+
+	// the delivered event isn't tracked anymore
+	require.NoError(t, p.SendEvent(ctx, newOutboxEvent("event1")))
+	chunks := len(client.sent)
+	require.Greater(t, chunks, 1)
+	for i := 0; i < chunks; i++ {
+		p.delivered(deliveryReport("event1", nil))
+	}
+	assert.Empty(t, p.inflight.events)
+
+	// the event is persisted as a whole once, though several chunks of it fail to deliver
+	evt := newOutboxEvent("event2")
+	require.NoError(t, p.SendEvent(ctx, evt))
+	p.delivered(deliveryReport("event2", nil))
+	p.persistUndelivered(deliveryReport("event2", errors.New("message timed out")))
+	p.persistUndelivered(deliveryReport("event2", errors.New("message timed out")))
+	assert.Equal(t, 1, outbox.Len())
+	assert.Empty(t, p.inflight.events)
+
+	replayed := []cloudevents.Event{}
+	_, err = outbox.Replay(func(ctx context.Context, evt cloudevents.Event) error {
+		assert.Equal(t, "status.hub1", cecontext.TopicFrom(ctx))
+		replayed = append(replayed, evt)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, "event2", replayed[0].ID())
+	assert.Equal(t, evt.Data(), replayed[0].Data())
+	assert.NotContains(t, replayed[0].Extensions(), transport.ChunkSizeKey)
+}
@@ -41,6 +41,21 @@ type TransportInternalConfig struct {
 	RestfulCredential *RestfulConfig
 	Extends           map[string]interface{}
 	FailureThreshold  int
+	// Outbox persists the events which can't be delivered, it's disabled if nil or the path is empty
+	Outbox *OutboxConfig
+}
+
+// OutboxConfig specifics the on-disk outbox of the producer, the undeliverable events are persisted into the file and
+// replayed in order once the transport is reachable again
+type OutboxConfig struct {
+	// Path is the file of the outbox, e.g. on an emptyDir or PVC
+	Path string
+	// MaxSize is the maximum bytes of the persisted events, the oldest ones are dropped once it's exceeded
+	MaxSize int64
+	// MaxAge is the maximum age of the persisted events, the older ones are dropped instead of replayed
+	MaxAge time.Duration
+	// ReplayInterval is the interval to replay the persisted events
+	ReplayInterval time.Duration
 }
 
 // KafkaInternalConfig specifics the configuration for the global hub manager, agent, or even inventory
@@ -8,8 +8,10 @@ import (
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -124,6 +126,29 @@ func ToMigrationEvent(
 	return e
 }
 
+// SetUserIdentity carries the global hub user of the identity annotations with the spec event, the managed hub
+// impersonates the user to apply the event if the RBAC is enforced
+func SetUserIdentity(evt *cloudevents.Event, annotations map[string]string) {
+	if userIdentity := annotations[constants.UserIdentityAnnotation]; userIdentity != "" {
+		evt.SetExtension(constants.CloudEventExtensionKeyUserIdentity, userIdentity)
+	}
+	if userGroups := annotations[constants.UserGroupsAnnotation]; userGroups != "" {
+		evt.SetExtension(constants.CloudEventExtensionKeyUserGroups, userGroups)
+	}
+}
+
+// UserIdentityOf returns the global hub user carried by the spec event in the form of the identity annotations
+func UserIdentityOf(evt *cloudevents.Event) map[string]string {
+	annotations := map[string]string{}
+	if userIdentity, err := types.ToString(evt.Extensions()[constants.CloudEventExtensionKeyUserIdentity]); err == nil {
+		annotations[constants.UserIdentityAnnotation] = userIdentity
+	}
+	if userGroups, err := types.ToString(evt.Extensions()[constants.CloudEventExtensionKeyUserGroups]); err == nil {
+		annotations[constants.UserGroupsAnnotation] = userGroups
+	}
+	return annotations
+}
+
 func PrettyPrint(obj interface{}) {
 	payload, err := json.MarshalIndent(obj, "", "  ")
 	if err != nil {
@@ -132,3 +157,28 @@ func PrettyPrint(obj interface{}) {
 		fmt.Println(string(payload))
 	}
 }
+
+// SlimUnstructured keeps the identity metadata and the given nested fields of the object, the other fields are removed
+// to reduce the size of the bundle
+func SlimUnstructured(obj *unstructured.Unstructured, fields ...[]string) {
+	slim := unstructured.Unstructured{Object: map[string]any{}}
+	slim.SetGroupVersionKind(obj.GroupVersionKind())
+	slim.SetNamespace(obj.GetNamespace())
+	slim.SetName(obj.GetName())
+	slim.SetUID(obj.GetUID())
+	slim.SetResourceVersion(obj.GetResourceVersion())
+	slim.SetLabels(obj.GetLabels())
+	slim.SetOwnerReferences(obj.GetOwnerReferences())
+	slim.SetCreationTimestamp(obj.GetCreationTimestamp())
+	slim.SetDeletionTimestamp(obj.GetDeletionTimestamp())
+	for _, field := range fields {
+		val, found, err := unstructured.NestedFieldNoCopy(obj.Object, field...)
+		if err != nil || !found {
+			continue
+		}
+		if err := unstructured.SetNestedField(slim.Object, runtime.DeepCopyJSONValue(val), field...); err != nil {
+			log.Debugf("failed to copy the field %v: %v", field, err)
+		}
+	}
+	obj.Object = slim.Object
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/archive"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// failedStore fails to upload the archives, e.g. the bucket is unreachable
+type failedStore struct{}
+
+func (s *failedStore) Put(ctx context.Context, key string, file *os.File) (string, error) {
+	return "", errors.New("the archive storage is unreachable")
+}
+
+func (s *failedStore) Get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	return nil, errors.New("the archive storage is unreachable")
+}
+
+var _ = Describe("data retention job with the archive", Ordered, func() {
+	// use the retention months which aren't used by the other data retention tests, so that the expired partitions of
+	// them aren't dropped by each other
+	retentionMonth := 24
+	now := time.Now()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	expiredMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
+
+	// the first partition table is archived at first, and the compliance is used to verify the restored rows
+	firstTable := task.PartitionTables[0]
+	firstPartition := fmt.Sprintf("%s_%s", firstTable, expiredMonth.Format(task.PartitionDateFormat))
+	complianceTable := "history.local_compliance"
+	compliancePartition := fmt.Sprintf("%s_%s", complianceTable, expiredMonth.Format(task.PartitionDateFormat))
+	policyID := uuid.New().String()
+
+	// runDataRetention runs the job until the table is handled, it's the last table handled by the job
+	runDataRetention := func(archiver *archive.Archiver, lastTable string) {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, task.RetentionPolicy{
+			Events:      retentionMonth,
+			History:     retentionMonth,
+			SoftDeleted: retentionMonth,
+			Archiver:    archiver,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() error {
+			var count int64
+			if err := db.Model(&models.DataRetentionJobLog{}).Where("table_name = ?", lastTable).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("the data retention job of %s isn't finished", lastTable)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	}
+
+	partitionExists := func(partition string) bool {
+		exists := false
+		Expect(db.Raw("SELECT to_regclass(?) IS NOT NULL", partition).Scan(&exists).Error).NotTo(HaveOccurred())
+		return exists
+	}
+
+	BeforeAll(func() {
+		By("Creating the expired partitions")
+		Expect(createPartitionTable(firstTable, expiredMonth)).To(Succeed())
+		Expect(createPartitionTable(complianceTable, expiredMonth)).To(Succeed())
+		Expect(db.Exec(`INSERT INTO history.local_compliance (policy_id, cluster_id, leaf_hub_name, compliance_date,
+			compliance, compliance_changed_frequency) VALUES (?, ?, 'hub1', ?, 'non_compliant', 3)`,
+			policyID, uuid.New().String(), expiredMonth.Format(task.DateFormat)).Error).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		Expect(db.Exec("DELETE FROM event.data_retention_job_log").Error).To(Succeed())
+	})
+
+	It("should keep the expired partition if it fails to archive", func() {
+		runDataRetention(archive.NewArchiver(&failedStore{}, testPostgres.URI, ""), firstTable)
+
+		jobLog := &models.DataRetentionJobLog{}
+		Expect(db.Where("table_name = ?", firstTable).First(jobLog).Error).To(Succeed())
+		Expect(jobLog.Error).To(ContainSubstring("failed to archive partition table"))
+		Expect(jobLog.ArchiveURI).To(BeEmpty())
+
+		Expect(partitionExists(firstPartition)).To(BeTrue())
+		Expect(partitionExists(compliancePartition)).To(BeTrue())
+	})
+
+	It("should archive the expired partition before dropping it", func() {
+		GinkgoT().Setenv(archive.ArchivePathEnv, GinkgoT().TempDir())
+		store, err := archive.NewStoreFromEnv()
+		Expect(err).NotTo(HaveOccurred())
+		runDataRetention(archive.NewArchiver(store, testPostgres.URI, ""),
+			task.RetentionTables[len(task.RetentionTables)-1])
+
+		Expect(partitionExists(firstPartition)).To(BeFalse())
+		Expect(partitionExists(compliancePartition)).To(BeFalse())
+
+		jobLog := &models.DataRetentionJobLog{}
+		Expect(db.Where("table_name = ?", complianceTable).First(jobLog).Error).To(Succeed())
+		Expect(jobLog.Error).To(Equal("none"))
+		Expect(jobLog.ArchivedPartition).To(Equal(compliancePartition))
+		Expect(jobLog.ArchiveRows).To(Equal(int64(1)))
+		Expect(jobLog.ArchiveChecksum).NotTo(BeEmpty())
+
+		By("Restoring the archived partition")
+		archiver := archive.NewArchiver(store, testPostgres.URI, "")
+		manifest, err := archiver.LatestManifest(ctx, compliancePartition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.URI).To(Equal(jobLog.ArchiveURI))
+
+		// the archive is verified by the checksum before it's attached
+		corrupted := *manifest
+		corrupted.Checksum = "corrupted"
+		Expect(archiver.RestorePartition(ctx, complianceTable, expiredMonth, &corrupted)).
+			To(MatchError(ContainSubstring("doesn't match the manifest")))
+		Expect(partitionExists(compliancePartition)).To(BeFalse())
+
+		Expect(archiver.RestorePartition(ctx, complianceTable, expiredMonth, manifest)).To(Succeed())
+		Expect(partitionExists(compliancePartition)).To(BeTrue())
+
+		var compliance []models.LocalComplianceHistory
+		Expect(db.Where("policy_id = ?", policyID).Find(&compliance).Error).To(Succeed())
+		Expect(compliance).To(HaveLen(1))
+		Expect(compliance[0].LeafHubName).To(Equal("hub1"))
+		Expect(compliance[0].Compliance).To(Equal("non_compliant"))
+		Expect(compliance[0].ComplianceChangedFrequency).To(Equal(3))
+	})
+})
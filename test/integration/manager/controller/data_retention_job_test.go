@@ -30,6 +30,11 @@ var _ = Describe("data retention job", Ordered, func() {
 	// This ensures our test's "expired" partitions (13 months ago) won't be deleted by
 	// the scheduler's data-retention job (which only deletes partitions >18 months old).
 	retentionMonth := 12
+	retentionPolicy := task.RetentionPolicy{
+		Events:      retentionMonth,
+		History:     retentionMonth,
+		SoftDeleted: retentionMonth,
+	}
 
 	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
 	expirationTime := minTime.AddDate(0, -1, 0)
@@ -117,7 +122,7 @@ var _ = Describe("data retention job", Ordered, func() {
 	It("the data retention job should work", func() {
 		By("Create the data retention job")
 		s := gocron.NewScheduler(time.UTC)
-		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, retentionMonth)
+		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 		Expect(err).ToNot(HaveOccurred())
 		s.StartAsync()
 		defer s.Clear()
@@ -175,7 +180,7 @@ var _ = Describe("data retention job", Ordered, func() {
 
 		By("Run data retention job to generate fresh logs")
 		s := gocron.NewScheduler(time.UTC)
-		_, err := s.Every(1).Second().DoWithJobDetails(task.DataRetention, ctx, retentionMonth)
+		_, err := s.Every(1).Second().DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 		Expect(err).ToNot(HaveOccurred())
 		s.StartAsync()
 		defer s.Clear()
@@ -29,12 +29,17 @@ var _ = Describe("scheduler", func() {
 		)
 		Expect(err).To(Succeed())
 
+		retentionPolicy := task.RetentionPolicy{
+			Events:      managerConfig.DatabaseConfig.DataRetention,
+			History:     managerConfig.DatabaseConfig.DataRetention,
+			SoftDeleted: managerConfig.DatabaseConfig.DataRetention,
+		}
 		_, err = scheduler.Every(1).Month(1, 15).At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+			DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 		Expect(err).To(Succeed())
 
 		_, err = scheduler.Every(1).MonthLastDay().At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+			DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 		Expect(err).To(Succeed())
 
 		// Test scheduler mechanism without executing data-retention job to avoid
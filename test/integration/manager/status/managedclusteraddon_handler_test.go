@@ -0,0 +1,88 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ManagedClusterAddOnHandler"
+var _ = Describe("ManagedClusterAddOnHandler", Ordered, func() {
+	leafHubName := "hub1"
+	version := eventversion.NewVersion()
+
+	It("should sync the managed cluster addons", func() {
+		version.Incr()
+		bundle := generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{}
+		bundle.Create = []addonv1alpha1.ManagedClusterAddOn{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "work-manager", Namespace: "cluster1"},
+				Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: []metav1.Condition{{
+					Type:               addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+					Status:             metav1.ConditionTrue,
+					Reason:             "ManagedClusterAddOnLeaseUpdated",
+					LastTransitionTime: metav1.Now(),
+				}}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "work-manager", Namespace: "cluster2"},
+				Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: []metav1.Condition{{
+					Type:               addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+					Status:             metav1.ConditionUnknown,
+					Reason:             "ManagedClusterAddOnLeaseUpdateStopped",
+					Message:            "Addon agent stopped updating its lease.",
+					LastTransitionTime: metav1.Now(),
+				}}},
+			},
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterAddOnType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		Eventually(func() error {
+			var addons []models.ManagedClusterAddOn
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("cluster_name").
+				Find(&addons).Error
+			if err != nil {
+				return err
+			}
+			if len(addons) != 2 {
+				return fmt.Errorf("expected 2 addons, got %d", len(addons))
+			}
+			if addons[1].Available != string(metav1.ConditionUnknown) || addons[1].Message == "" {
+				return fmt.Errorf("unexpected addon of cluster2: %+v", addons[1])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should delete the managed cluster addon", func() {
+		version.Incr()
+		bundle := generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{}
+		bundle.Delete = []generic.ObjectMetadata{{Namespace: "cluster2", Name: "work-manager"}}
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterAddOnType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		Eventually(func() error {
+			var addons []models.ManagedClusterAddOn
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&addons).Error; err != nil {
+				return err
+			}
+			if len(addons) != 1 || addons[0].ClusterName != "cluster1" {
+				return fmt.Errorf("expected only the addon of cluster1 left, got %d", len(addons))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
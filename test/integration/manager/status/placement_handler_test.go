@@ -0,0 +1,108 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "PlacementHandler"
+var _ = Describe("PlacementHandler", Ordered, func() {
+	leafHubName := "hub1"
+	placementID := "7b1f5c0e-3c1a-4d55-9f0f-1c2d3e4f5a61"
+	decisionID := "7b1f5c0e-3c1a-4d55-9f0f-1c2d3e4f5a62"
+	placementVersion := eventversion.NewVersion()
+	decisionVersion := eventversion.NewVersion()
+
+	It("should sync the placement and the placement decision", func() {
+		placementVersion.Incr()
+		placementBundle := generic.GenericBundle[clusterv1beta1.Placement]{}
+		placementBundle.Create = []clusterv1beta1.Placement{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "placement1",
+				Namespace: "default",
+				UID:       types.UID(placementID),
+			},
+			Spec: clusterv1beta1.PlacementSpec{ClusterSets: []string{"global"}},
+		}}
+		evt := ToCloudEvent(leafHubName, string(enum.LocalPlacementType), placementVersion, placementBundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		placementVersion.Next()
+
+		decisionVersion.Incr()
+		decisionBundle := generic.GenericBundle[clusterv1beta1.PlacementDecision]{}
+		decisionBundle.Create = []clusterv1beta1.PlacementDecision{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "placement1-decision-1",
+				Namespace: "default",
+				UID:       types.UID(decisionID),
+				Labels: map[string]string{
+					clusterv1beta1.PlacementLabel: "placement1",
+				},
+			},
+			Status: clusterv1beta1.PlacementDecisionStatus{
+				Decisions: []clusterv1beta1.ClusterDecision{
+					{ClusterName: "cluster1"},
+					{ClusterName: "cluster2"},
+				},
+			},
+		}}
+		evt = ToCloudEvent(leafHubName, string(enum.LocalPlacementDecisionType), decisionVersion, decisionBundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		decisionVersion.Next()
+
+		Eventually(func() error {
+			var placements []models.LocalPlacement
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&placements).Error; err != nil {
+				return err
+			}
+			if len(placements) != 2 {
+				return fmt.Errorf("expected 2 placement resources, got %d", len(placements))
+			}
+
+			var clusters []string
+			err := database.GetGorm().Table("local_spec.placement_clusters").
+				Where("leaf_hub_name = ? AND namespace = ? AND placement_name = ?", leafHubName, "default",
+					"placement1").
+				Order("cluster_name").Pluck("cluster_name", &clusters).Error
+			if err != nil {
+				return err
+			}
+			if len(clusters) != 2 || clusters[0] != "cluster1" || clusters[1] != "cluster2" {
+				return fmt.Errorf("unexpected clusters of the placement: %v", clusters)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should delete the placement decision", func() {
+		decisionVersion.Incr()
+		bundle := generic.GenericBundle[clusterv1beta1.PlacementDecision]{}
+		bundle.Delete = []generic.ObjectMetadata{{Namespace: "default", Name: "placement1-decision-1"}}
+		evt := ToCloudEvent(leafHubName, string(enum.LocalPlacementDecisionType), decisionVersion, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		decisionVersion.Next()
+
+		Eventually(func() error {
+			var placements []models.LocalPlacement
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&placements).Error; err != nil {
+				return err
+			}
+			if len(placements) != 1 || placements[0].Kind != "Placement" {
+				return fmt.Errorf("expected only the placement left, got %d", len(placements))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
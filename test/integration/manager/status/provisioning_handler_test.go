@@ -0,0 +1,110 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ProvisioningHandler"
+var _ = Describe("ProvisioningHandler", Ordered, func() {
+	leafHubName := "hub1"
+	deploymentID := "3f9c2a41-6d1e-4b7a-8c55-0e1f2a3b4c51"
+	version := eventversion.NewVersion()
+
+	clusterDeployment := func(installed bool) unstructured.Unstructured {
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "hive.openshift.io/v1",
+			"kind":       "ClusterDeployment",
+			"spec":       map[string]any{"clusterName": "sno1", "installed": installed},
+		}}
+		obj.SetNamespace("sno1")
+		obj.SetName("sno1")
+		obj.SetUID(types.UID(deploymentID))
+		obj.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+		if installed {
+			_ = unstructured.SetNestedField(obj.Object, time.Now().Format(time.RFC3339), "status",
+				"installedTimestamp")
+		}
+		return obj
+	}
+
+	getProvisioning := func() (*models.ClusterProvisioning, error) {
+		provisioning := &models.ClusterProvisioning{}
+		err := database.GetGorm().Unscoped().Where("leaf_hub_name = ? AND id = ?", leafHubName, deploymentID).
+			First(provisioning).Error
+		return provisioning, err
+	}
+
+	It("should sync the installing cluster deployment", func() {
+		version.Incr()
+		bundle := generic.GenericBundle[unstructured.Unstructured]{}
+		bundle.Create = []unstructured.Unstructured{clusterDeployment(false)}
+		evt := ToCloudEvent(leafHubName, string(enum.ClusterDeploymentType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		Eventually(func() error {
+			provisioning, err := getProvisioning()
+			if err != nil {
+				return err
+			}
+			if provisioning.Operation != "Install" || provisioning.Phase != "InProgress" ||
+				provisioning.StartedAt == nil {
+				return fmt.Errorf("unexpected provisioning: %+v", provisioning)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should complete the installation of the cluster deployment", func() {
+		version.Incr()
+		bundle := generic.GenericBundle[unstructured.Unstructured]{}
+		bundle.Update = []unstructured.Unstructured{clusterDeployment(true)}
+		evt := ToCloudEvent(leafHubName, string(enum.ClusterDeploymentType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		Eventually(func() error {
+			provisioning, err := getProvisioning()
+			if err != nil {
+				return err
+			}
+			if provisioning.Phase != "Completed" || provisioning.CompletedAt == nil {
+				return fmt.Errorf("unexpected provisioning: %+v", provisioning)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should keep the provisioning once the cluster deployment is deleted", func() {
+		version.Incr()
+		bundle := generic.GenericBundle[unstructured.Unstructured]{}
+		bundle.Delete = []generic.ObjectMetadata{{ID: deploymentID, Namespace: "sno1", Name: "sno1"}}
+		evt := ToCloudEvent(leafHubName, string(enum.ClusterDeploymentType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		Eventually(func() error {
+			provisioning, err := getProvisioning()
+			if err != nil {
+				return err
+			}
+			if !provisioning.DeletedAt.Valid || provisioning.Phase != "Completed" {
+				return fmt.Errorf("expected the provisioning is soft deleted: %+v", provisioning)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
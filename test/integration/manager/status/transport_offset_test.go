@@ -15,6 +15,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/test/integration/utils/testpostgres"
 )
 
 var _ = Describe("TransportOffsetPersistence", Ordered, func() {
@@ -77,18 +78,10 @@ var _ = Describe("TransportOffsetPersistence", Ordered, func() {
 				Expect(err).NotTo(HaveOccurred())
 			}
 
-			// Create a dummy metadata function
-			metadataFunc := func() []conflator.ConflationMetadata {
-				return []conflator.ConflationMetadata{}
-			}
-
-			// Create committer and trigger migration
-			committer = conflator.NewKafkaConflationCommitter(metadataFunc)
-			err := committer.Start(testCtx)
+			// Revert the record of the transport migration, and migrate the database again
+			err := db.Exec("DELETE FROM public.schema_migrations WHERE name = ?", "transport_position_key").Error
 			Expect(err).NotTo(HaveOccurred())
-
-			// Wait a moment for migration to complete
-			time.Sleep(1 * time.Second)
+			Expect(testpostgres.MigrateDatabase(testPostgres.URI)).To(Succeed())
 
 			// Verify old records are gone
 			var oldCount int64
@@ -1,6 +1,7 @@
 package testpostgres
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 )
 
 func InitDatabase(uri string) error {
@@ -51,24 +53,34 @@ func InitDatabase(uri string) error {
 		fmt.Printf("script %s executed successfully.\n", file.Name())
 	}
 
-	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "upgrade")
-	upgradeFiles, err := os.ReadDir(sqlDir)
+	return MigrateDatabase(uri)
+}
+
+// MigrateDatabase applies the pending migrations of the global hub database
+func MigrateDatabase(uri string) error {
+	ctx := context.Background()
+	conn, err := database.PostgresConnection(ctx, uri, nil)
 	if err != nil {
 		return err
 	}
-	for _, file := range upgradeFiles {
-		filePath := filepath.Join(sqlDir, file.Name())
-		fileContent, err := os.ReadFile(filePath)
-		if err != nil {
-			return err
-		}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
 
-		result := db.Exec(string(fileContent))
-		if result.Error != nil {
-			return result.Error
-		}
-		fmt.Printf("script %s executed successfully.\n", file.Name())
+	migrations, err := migration.GlobalHubMigrations()
+	if err != nil {
+		return err
+	}
+	migrator, err := migration.NewMigrator(conn, migrations)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range applied {
+		fmt.Printf("migration %s applied successfully.\n", m.String())
 	}
-
 	return nil
 }